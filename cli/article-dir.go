@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/state"
+	"github.com/spf13/cobra"
+)
+
+// maxDirConcurrency --concurrency 的上限，避免触发服务端和微信接口限流
+const maxDirConcurrency = 16
+
+// dirResult --dir 模式下单个文件的处理结果（NDJSON 的一行）
+type dirResult struct {
+	Type string `json:"type"`
+	File string `json:"file"`
+	output.ErrorResponse
+	DraftID        string          `json:"draft_id,omitempty"`
+	MediaID        string          `json:"media_id,omitempty"`
+	Title          string          `json:"title,omitempty"`
+	UploadedImages []uploadedImage `json:"uploaded_images,omitempty"`
+	// Action --sync 时执行（或 --dry-run 时计划执行）的操作
+	Action state.Action `json:"action,omitempty"`
+	DryRun bool         `json:"dry_run,omitempty"`
+}
+
+// dirSummary --dir 模式的汇总（NDJSON 的最后一行）
+type dirSummary struct {
+	Type      string `json:"type"`
+	Total     int    `json:"total"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	// Actions --sync 时各操作的数量
+	Actions map[state.Action]int `json:"actions,omitempty"`
+	// Cancelled 被 Ctrl-C 或 --timeout 中止，未处理的文件不输出结果
+	Cancelled bool `json:"cancelled,omitempty"`
+}
+
+// validateArticleDirFlags 校验 --dir 模式的参数
+func validateArticleDirFlags() error {
+	info, err := os.Stat(flagArticleDir)
+	if err != nil {
+		return fmt.Errorf("读取目录失败: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("--dir 必须是目录: %s", flagArticleDir)
+	}
+	if _, err := filepath.Match(flagArticleGlob, ""); err != nil {
+		return fmt.Errorf("--glob 格式无效: %w", err)
+	}
+	if flagArticleConcurrency < 1 || flagArticleConcurrency > maxDirConcurrency {
+		return fmt.Errorf("--concurrency 必须在 1~%d 之间", maxDirConcurrency)
+	}
+	return nil
+}
+
+// findMarkdownFiles 递归查找 dir 下匹配 pattern 的文件，按路径排序
+//
+// pattern 不含 / 时匹配文件名，否则匹配相对 dir 的路径（以 / 分隔）。
+// 跳过以 . 开头的目录和文件（如 .git）。
+func findMarkdownFiles(dir, pattern string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && matchArticleGlob(dir, path, pattern) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("遍历目录失败: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// matchArticleGlob 判断 dir 下的 path 是否匹配 pattern，规则见 findMarkdownFiles
+func matchArticleGlob(dir, path, pattern string) bool {
+	name := filepath.Base(path)
+	if strings.Contains(pattern, "/") {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return false
+		}
+		name = filepath.ToSlash(rel)
+	}
+	ok, _ := filepath.Match(pattern, name)
+	return ok
+}
+
+// runArticleDir 将目录中的每个 Markdown 文件分别创建为草稿，逐行输出结果
+//
+// 每个文件使用自己的 front matter；单个文件失败不影响其他文件，有失败时退出码为 1。
+// --sync 时按同步状态跳过、更新或创建，--prune 在全部文件处理完后删除源文件
+// 已不存在的草稿。
+func runArticleDir(cmd *cobra.Command) {
+	files, err := findMarkdownFiles(flagArticleDir, flagArticleGlob)
+	if err != nil {
+		output.Error(err)
+	}
+	if len(files) == 0 {
+		output.Error(fmt.Errorf("%s 中没有匹配 %s 的文件", flagArticleDir, flagArticleGlob))
+	}
+
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	overrides := articleFlagOverrides(cmd)
+	upload := uploadLocalImages(cmd, flagUploadLocalImages)
+	var cache *imageCache
+	if upload {
+		cache = newImageCache(cfg)
+	}
+	store := draftStateStore()
+	process := func(src articleSource) (dirResult, error) {
+		if flagArticleSync {
+			return syncArticle(ctx, client, cache, store, src, upload, flagArticleDryRun)
+		}
+		return createDirDraft(ctx, client, cache, src, upload)
+	}
+
+	jobs := make(chan string)
+	results := make(chan dirResult)
+	var wg sync.WaitGroup
+	for range min(flagArticleConcurrency, len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				r, err := process(articleSource{Path: path, Overrides: overrides})
+				r.Type, r.File = "result", path
+				if err != nil {
+					r.ErrorResponse = output.NewErrorResponse(contextError(err))
+				} else {
+					r.Success = true
+				}
+				results <- r
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, path := range files {
+			select {
+			case jobs <- path:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	summary := dirSummary{Type: "summary", Total: len(files)}
+	if flagArticleSync {
+		summary.Actions = map[state.Action]int{}
+	}
+	report := func(r dirResult) {
+		if r.Success {
+			summary.Succeeded++
+			if r.Action != "" {
+				summary.Actions[r.Action]++
+			}
+		} else {
+			summary.Failed++
+		}
+		output.Line(r)
+	}
+	for r := range results {
+		report(r)
+	}
+
+	// 有文件处理失败时源文件列表仍然完整，可以安全清理
+	if flagArticlePrune && ctx.Err() == nil {
+		current := make(map[string]bool, len(files))
+		for _, path := range files {
+			if abs, err := filepath.Abs(path); err == nil {
+				current[abs] = true
+			}
+		}
+		deleted, err := pruneArticleDir(ctx, client, store, current, flagArticleDryRun)
+		if err != nil {
+			output.Line(dirResult{Type: "result", File: flagArticleDir, Action: state.ActionDelete, ErrorResponse: output.NewErrorResponse(err)})
+			summary.Failed++
+		}
+		summary.Total += len(deleted)
+		for _, r := range deleted {
+			report(r)
+		}
+	}
+
+	summary.Cancelled = ctx.Err() != nil
+	output.Line(summary)
+	if summary.Failed > 0 || summary.Cancelled {
+		os.Exit(1)
+	}
+}
+
+// createDirDraft 处理单个文件：合并参数、上传本地图片并创建草稿
+func createDirDraft(ctx context.Context, client *api.Client, cache *imageCache, src articleSource, upload bool) (dirResult, error) {
+	var r dirResult
+	a, err := prepareArticle(cfg, src, flagConvertVersion)
+	if err != nil {
+		return r, err
+	}
+	r.Title = a.Request.Title
+	if upload {
+		if r.UploadedImages, err = a.uploadImages(ctx, client, cache); err != nil {
+			return r, err
+		}
+	} else if err := a.checkLocalCover(); err != nil {
+		return r, err
+	}
+	resp, err := client.ArticleDraftContext(ctx, a.Request)
+	if err != nil {
+		return r, err
+	}
+	if err := resp.Err(); err != nil {
+		return r, err
+	}
+	r.DraftID = resp.Data.DraftID
+	r.MediaID = resp.Data.MediaID
+	return r, nil
+}
+
+// contextError 为取消和超时错误补充说明，用于逐行输出的结果
+func contextError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("操作已取消: %w", err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("请求超时: %w", err)
+	}
+	return err
+}
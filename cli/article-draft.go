@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/manifest"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/markdown"
 	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
 	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/themes"
 	"github.com/spf13/cobra"
@@ -13,131 +18,379 @@ import (
 
 // ArticleDraftCmd 图文草稿命令
 var ArticleDraftCmd = &cobra.Command{
-	Use:   "article-draft",
+	Use:   "article-draft [file.md...]",
 	Short: "创建图文消息草稿",
-	Long:  `将 Markdown 内容转换为微信公众号格式并创建图文草稿`,
-	Args:  cobra.NoArgs,
+	Long: `将 Markdown 内容转换为微信公众号格式并创建图文草稿。
+
+文件开头的 YAML（---）或 TOML（+++）front matter 会从正文中移除，其中的
+title、author、digest、source_url 写入草稿，theme、font_size、background_type
+和 cover 作为对应参数的默认值（命令行参数优先）：
+  ---
+  title: 本周精选
+  author: 张三
+  cover: ./img/cover.png
+  theme: bytedance
+  open_comment: true
+  ---
+
+--title、--author、--digest、--source-url 和评论参数会覆盖 front matter
+中的同名字段。标题、作者、摘要分别不超过 64、8、120 个字，超出时在本地报错，
+不会发起请求。
+
+Markdown 中引用的本地图片（如 ![](./img/a.png)）和本地封面图会先上传到
+微信素材库，并自动替换为返回的 CDN 地址。相对路径以 Markdown 文件所在
+目录为基准（--markdown 时为当前目录）；front matter 中的封面图同样以 Markdown
+文件所在目录为基准，--cover-image 以当前目录为基准。
+
+多图文草稿（最多 8 篇，第一篇为头条）可以重复 --file，或使用 --manifest
+清单为每篇文章指定主题、封面等参数：
+  md2wx article-draft --file a.md --file b.md --theme default
+  md2wx article-draft --manifest digest.yaml
+
+清单格式（顶层字段作为默认值，路径以清单所在目录为基准）：
+  theme: default
+  author: 编辑部
+  articles:
+    - file: posts/a.md
+      theme: bytedance
+      cover: ./img/a.png
+    - file: posts/b.md
+      title: 第二篇
+
+多篇文章时 --theme、--author 等参数作用于每篇文章，--title、--digest、
+--source-url、--cover-image 和裁剪坐标需要在 front matter 或清单中设置。
+
+Markdown 文件也可以作为位置参数传入（与 --file 相同）。--file - 从标准输入
+读取；未指定任何来源且标准输入是管道或重定向文件时，同样从标准输入读取，
+相对路径以当前目录为基准：
+  md2wx article-draft a.md b.md
+  cat article.md | md2wx article-draft --theme bytedance
+
+文件支持 UTF-8（可带 BOM）、带 BOM 的 UTF-16 和 GBK 编码，统一转换为 UTF-8
+后提交；单个文件不超过 2 MB。
+
+--dir 递归查找目录中匹配 --glob 的文件（跳过 . 开头的目录），每个文件按自己的
+front matter 分别创建一个草稿，--concurrency 控制并发数。每处理完一个文件输出
+一行 JSON（type 为 result，含 file、success、media_id 或 error），最后一行为
+汇总（type 为 summary）；单个文件失败不会中止其他文件，有失败时退出码为 1：
+  md2wx article-draft --dir posts/ --glob '*.md' --concurrency 4
+
+--sync 在本地记录每个文件（按配置档案和绝对路径区分）的内容哈希和草稿
+media_id：再次运行时内容未变的文件跳过（action 为 skip），内容变化的文件
+更新原草稿（update），没有记录的文件创建草稿（create）。--prune 删除 --dir
+目录中源文件已被删除的草稿（delete），--dry-run 只输出计划执行的操作：
+  md2wx article-draft --dir posts/ --sync --prune --dry-run
+  md2wx article-draft --file post.md --sync
+
+--watch 先创建草稿，之后监听 Markdown 文件和其中引用的本地图片、封面，每次保存
+（防抖后）更新同一个草稿而不是新建，每次更新输出一行状态，按 Ctrl-C 停止。
+与 --sync 一起使用时复用上次同步记录的草稿：
+  md2wx article-draft --file post.md --watch
+  md2wx article-draft --file post.md --watch --sync --interval 1s`,
+	Args: cobra.ArbitraryArgs,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		return validateArticleDraftFlags()
+		// 位置参数等同于 --file
+		flagMarkdownFiles = append(flagMarkdownFiles, args...)
+		return validateArticleDraftFlags(cmd)
 	},
 	Run: runArticleDraft,
 }
 
 var (
 	flagMarkdown       string
-	flagMarkdownFile   string
+	flagMarkdownFiles  []string
+	flagManifest       string
 	flagTheme          string
 	flagFontSize       string
 	flagBackgroundType string
 	flagConvertVersion string
 	flagCoverImage     string
+
+	flagUploadLocalImages bool
+
+	// 目录模式
+	flagArticleDir         string
+	flagArticleGlob        string
+	flagArticleConcurrency int
+
+	// 幂等同步
+	flagArticleSync   bool
+	flagArticlePrune  bool
+	flagArticleDryRun bool
+
+	// 监听模式
+	flagArticleWatch    bool
+	flagArticleInterval time.Duration
+
+	// 文章元数据
+	flagArticleTitle     string
+	flagArticleAuthor    string
+	flagArticleDigest    string
+	flagArticleSourceURL string
+	flagOpenComment      bool
+	flagFansOnlyComment  bool
+	flagCrop235          string
+	flagCrop11           string
 )
 
 func init() {
 	ArticleDraftCmd.Flags().StringVar(&flagMarkdown, "markdown", "", "Markdown 内容")
-	ArticleDraftCmd.Flags().StringVar(&flagMarkdownFile, "file", "", "Markdown 文件路径")
+	ArticleDraftCmd.Flags().StringArrayVar(&flagMarkdownFiles, "file", nil, "Markdown 文件路径，可重复指定以创建多图文草稿，- 表示标准输入")
+	ArticleDraftCmd.Flags().StringVar(&flagManifest, "manifest", "", "多图文清单文件 (YAML)")
+	ArticleDraftCmd.Flags().StringVar(&flagArticleDir, "dir", "", "为目录中的每个 Markdown 文件分别创建草稿（逐行输出 JSON 结果）")
+	ArticleDraftCmd.Flags().StringVar(&flagArticleGlob, "glob", "*.md", "--dir 模式下匹配的文件名，含 / 时匹配相对路径")
+	ArticleDraftCmd.Flags().IntVar(&flagArticleConcurrency, "concurrency", 4, "--dir 模式下同时处理的文件数")
+	ArticleDraftCmd.Flags().BoolVar(&flagArticleSync, "sync", false, "按本地同步状态跳过未变化的文件、更新已有草稿")
+	ArticleDraftCmd.Flags().BoolVar(&flagArticlePrune, "prune", false, "--sync --dir 时删除源文件已不存在的草稿")
+	ArticleDraftCmd.Flags().BoolVar(&flagArticleDryRun, "dry-run", false, "--sync 时只输出计划执行的操作，不调用接口")
+	ArticleDraftCmd.Flags().BoolVar(&flagArticleWatch, "watch", false, "监听文件变化，保存后更新同一个草稿")
+	ArticleDraftCmd.Flags().DurationVar(&flagArticleInterval, "interval", 500*time.Millisecond, "--watch 时的文件变化检测间隔")
 	ArticleDraftCmd.Flags().StringVar(&flagTheme, "theme", "", "主题名称（默认从配置读取）")
 	ArticleDraftCmd.Flags().StringVar(&flagFontSize, "font-size", "", "字体大小 (small/medium/large)")
 	ArticleDraftCmd.Flags().StringVar(&flagBackgroundType, "background-type", "", "背景类型 (default/grid/none)")
 	ArticleDraftCmd.Flags().StringVar(&flagConvertVersion, "convert-version", "v2", "转换版本")
-	ArticleDraftCmd.Flags().StringVar(&flagCoverImage, "cover-image", "", "封面图片 URL")
+	ArticleDraftCmd.Flags().StringVar(&flagCoverImage, "cover-image", "", "封面图片 URL 或本地路径")
+	ArticleDraftCmd.Flags().BoolVar(&flagUploadLocalImages, "upload-local-images", true, "上传本地图片并替换为微信 CDN 地址（默认取配置 upload.local_images）")
+	addRetriesFlag(ArticleDraftCmd)
+
+	ArticleDraftCmd.Flags().StringVar(&flagArticleTitle, "title", "", "文章标题（不超过 64 字）")
+	ArticleDraftCmd.Flags().StringVar(&flagArticleAuthor, "author", "", "作者（不超过 8 字）")
+	ArticleDraftCmd.Flags().StringVar(&flagArticleDigest, "digest", "", "摘要（不超过 120 字）")
+	ArticleDraftCmd.Flags().StringVar(&flagArticleSourceURL, "source-url", "", "阅读原文链接")
+	ArticleDraftCmd.Flags().BoolVar(&flagOpenComment, "open-comment", false, "打开评论")
+	ArticleDraftCmd.Flags().BoolVar(&flagFansOnlyComment, "fans-only-comment", false, "仅粉丝可评论（需同时打开评论）")
+	ArticleDraftCmd.Flags().StringVar(&flagCrop235, "crop-235", "", "封面 2.35:1 裁剪坐标 x1,y1,x2,y2（0~1）")
+	ArticleDraftCmd.Flags().StringVar(&flagCrop11, "crop-1-1", "", "封面 1:1 裁剪坐标 x1,y1,x2,y2（0~1）")
 }
 
-func validateArticleDraftFlags() error {
+func validateArticleDraftFlags(cmd *cobra.Command) error {
+	// 未指定来源时读取管道输入
+	if flagMarkdown == "" && len(flagMarkdownFiles) == 0 && flagManifest == "" && flagArticleDir == "" && stdinPiped() {
+		flagMarkdownFiles = []string{stdinPath}
+	}
+
 	// 检查 Markdown 来源
-	if flagMarkdown == "" && flagMarkdownFile == "" {
-		return fmt.Errorf("必须提供 --markdown 或 --file 参数")
+	sources := 0
+	for _, set := range []bool{flagMarkdown != "", len(flagMarkdownFiles) > 0, flagManifest != "", flagArticleDir != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources == 0 {
+		return fmt.Errorf("必须提供 --markdown、--file、--manifest、--dir 参数、文件参数或管道输入")
+	}
+	if sources > 1 {
+		return fmt.Errorf("--markdown、--file、--manifest 和 --dir 不能同时使用")
+	}
+	if flagArticleDir != "" {
+		if err := validateArticleDirFlags(); err != nil {
+			return err
+		}
+	}
+	if err := validateArticleSyncFlags(); err != nil {
+		return err
+	}
+	if err := validateArticleWatchFlags(cmd); err != nil {
+		return err
+	}
+	if i := slices.Index(flagMarkdownFiles, stdinPath); i >= 0 && slices.Contains(flagMarkdownFiles[i+1:], stdinPath) {
+		return fmt.Errorf("标准输入（-）只能指定一次")
 	}
-	if flagMarkdown != "" && flagMarkdownFile != "" {
-		return fmt.Errorf("--markdown 和 --file 不能同时使用")
+	if len(flagMarkdownFiles) > api.MaxNewsArticles {
+		return fmt.Errorf("一个草稿最多包含 %d 篇文章", api.MaxNewsArticles)
 	}
 
-	// 检查主题是否有效（如果指定了的话）
-	if flagTheme != "" && !themes.IsValidTheme(flagTheme) {
-		return fmt.Errorf("无效的主题: %s，使用 'themes list' 查看可用主题", flagTheme)
+	// 多篇文章时，标题、摘要、封面等单篇字段需在 front matter 或清单中设置
+	if len(flagMarkdownFiles) > 1 || flagManifest != "" || flagArticleDir != "" {
+		for _, name := range []string{"title", "digest", "source-url", "cover-image", "crop-235", "crop-1-1"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("多篇文章时不能使用 --%s，请在 front matter 或清单中为每篇文章设置", name)
+			}
+		}
+	}
+
+	// 校验主题
+	if err := validateTheme(flagTheme); err != nil {
+		return err
+	}
+
+	// 校验封面裁剪坐标
+	for name, crop := range map[string]string{"--crop-235": flagCrop235, "--crop-1-1": flagCrop11} {
+		if crop == "" {
+			continue
+		}
+		if _, err := api.NormalizeCrop(crop); err != nil {
+			return fmt.Errorf("%s %w", name, err)
+		}
+	}
+
+	// 检查配置
+	return checkCredentials()
+}
+
+// validateTheme 检查主题是否有效（如果指定了的话）
+func validateTheme(theme string) error {
+	if theme != "" && !themes.IsValidTheme(theme) {
+		return fmt.Errorf("无效的主题: %s，使用 'themes list' 查看可用主题", theme)
+	}
+	return nil
+}
+
+// applyStyleDefaults 为未指定的样式参数填充默认值并校验主题
+//
+// 优先级：命令行参数 > front matter > 配置 c > 内置默认值。fm 可以为 nil。
+func applyStyleDefaults(c *config.Config, fm *markdown.FrontMatter, theme, fontSize, backgroundType *string) error {
+	// 使用 front matter 中的样式（如果未通过参数指定）
+	if fm != nil {
+		if *theme == "" {
+			*theme = fm.Theme
+		}
+		if *fontSize == "" {
+			*fontSize = fm.FontSize
+		}
+		if *backgroundType == "" {
+			*backgroundType = fm.BackgroundType
+		}
+	}
+
+	if err := validateTheme(*theme); err != nil {
+		return err
 	}
 
 	// 使用配置中的默认主题（如果未指定）
-	if flagTheme == "" {
-		if cfg.DefaultTheme != "" {
-			flagTheme = cfg.DefaultTheme
+	if *theme == "" {
+		if c.DefaultTheme != "" {
+			*theme = c.DefaultTheme
 		} else {
-			flagTheme = "default"
+			*theme = "default"
 		}
 	}
 
 	// 使用配置中的默认背景类型（如果未指定）
-	if flagBackgroundType == "" {
-		if cfg.DefaultBackgroundType != "" {
-			flagBackgroundType = cfg.DefaultBackgroundType
+	if *backgroundType == "" {
+		if c.DefaultBackgroundType != "" {
+			*backgroundType = c.DefaultBackgroundType
 		} else {
-			flagBackgroundType = "none"
+			*backgroundType = "none"
 		}
 	}
 
 	// 使用配置中的默认字体大小（如果未指定）
-	if flagFontSize == "" {
-		if cfg.DefaultFontSize != "" {
-			flagFontSize = cfg.DefaultFontSize
+	if *fontSize == "" {
+		if c.DefaultFontSize != "" {
+			*fontSize = c.DefaultFontSize
 		} else {
-			flagFontSize = "medium"
+			*fontSize = "medium"
 		}
 	}
 
-	// 检查配置
-	if cfg.WechatAppID == "" {
+	return nil
+}
+
+// checkCredentials 检查微信凭证和 API Key 是否已配置
+func checkCredentials() error {
+	return checkConfigCredentials(cfg)
+}
+
+// checkConfigCredentials 检查配置 c 中的微信凭证和 API Key
+func checkConfigCredentials(c *config.Config) error {
+	if c.WechatAppID == "" {
 		return fmt.Errorf("wechat_appid 未配置，请使用 'config set wechat-appid' 设置")
 	}
-	if cfg.WechatAppSecret == "" {
+	if c.WechatAppSecret == "" {
 		return fmt.Errorf("wechat_appsecret 未配置，请使用 'config set wechat-appsecret' 设置")
 	}
-	if cfg.APIKey == "" {
+	return checkConfigAPIKey(c)
+}
+
+// checkAPIKey 检查 API Key 是否已配置
+func checkAPIKey() error {
+	return checkConfigAPIKey(cfg)
+}
+
+// checkConfigAPIKey 检查配置 c 中的 API Key
+func checkConfigAPIKey(c *config.Config) error {
+	if c.APIKey == "" {
 		return fmt.Errorf("api_key 未配置，请使用 'config set api-key' 设置")
 	}
-
 	return nil
 }
 
 func runArticleDraft(cmd *cobra.Command, args []string) {
-	// 获取 Markdown 内容
-	markdown := flagMarkdown
-	if flagMarkdownFile != "" {
-		content, err := readFileContent(flagMarkdownFile)
+	if flagArticleDir != "" {
+		runArticleDir(cmd)
+		return
+	}
+	if flagArticleWatch {
+		runArticleWatch(cmd)
+		return
+	}
+	if flagArticleSync {
+		runArticleSync(cmd)
+		return
+	}
+
+	// 收集文章并合并参数，上传图片前先在本地完成校验
+	sources, err := articleDraftSources(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+	articles := make([]*preparedArticle, 0, len(sources))
+	for _, src := range sources {
+		a, err := prepareArticle(cfg, src, flagConvertVersion)
 		if err != nil {
+			if len(sources) > 1 {
+				err = fmt.Errorf("%s: %w", src.name(), err)
+			}
 			output.Error(err)
 		}
-		markdown = content
+		articles = append(articles, a)
 	}
 
-	// 获取 API Base URL（命令行参数优先）
-	apiBase := cfg.APIBaseURL
-	if apiBaseFlag, _ := cmd.Parent().PersistentFlags().GetString("api-base"); apiBaseFlag != "" {
-		apiBase = apiBaseFlag
+	// 创建 API 客户端
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
 	}
 
-	// 获取 API Key（命令行参数优先）
-	apiKey := cfg.APIKey
-	if apiKeyFlag, _ := cmd.Parent().PersistentFlags().GetString("api-key"); apiKeyFlag != "" {
-		apiKey = apiKeyFlag
-	}
+	// Ctrl-C 或 --timeout 会中止上传和创建请求
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 
-	// 创建 API 客户端
-	client := api.NewClient(apiBase, cfg.WechatAppID, cfg.WechatAppSecret, apiKey)
+	// 上传本地图片并替换为微信 CDN 地址
+	var uploaded []uploadedImage
+	if uploadLocalImages(cmd, flagUploadLocalImages) {
+		cache := newImageCache(cfg)
+		for _, a := range articles {
+			images, err := a.uploadImages(ctx, client, cache)
+			if err != nil {
+				exitOnRequestError(err)
+			}
+			uploaded = append(uploaded, images...)
+		}
+	} else {
+		for _, a := range articles {
+			if err := a.checkLocalCover(); err != nil {
+				if len(articles) > 1 {
+					err = fmt.Errorf("%s: %w", a.Source, err)
+				}
+				output.Error(err)
+			}
+		}
+	}
 
-	// 构建请求
-	req := &api.ArticleDraftRequest{
-		Markdown:       markdown,
-		Theme:          flagTheme,
-		FontSize:       flagFontSize,
-		BackgroundType: flagBackgroundType,
-		ConvertVersion: flagConvertVersion,
-		CoverImageUrl:  flagCoverImage,
+	if len(articles) == 1 && flagManifest == "" {
+		createArticleDraft(ctx, client, articles[0], uploaded)
+		return
 	}
+	createNewsDraft(ctx, client, articles, uploaded)
+}
 
-	// 调用 API
-	resp, err := client.ArticleDraft(req)
+// createArticleDraft 创建单篇图文草稿并输出结果
+func createArticleDraft(ctx context.Context, client *api.Client, a *preparedArticle, uploaded []uploadedImage) {
+	resp, err := client.ArticleDraftContext(ctx, a.Request)
 	if err != nil {
-		output.Error(err)
+		exitOnRequestError(err)
 	}
 
 	// 输出结果
@@ -148,22 +401,106 @@ func runArticleDraft(cmd *cobra.Command, args []string) {
 		result["draft_id"] = resp.Data.DraftID
 		result["media_id"] = resp.Data.MediaID
 		result["published"] = resp.Data.Published
+		if a.Request.Title != "" {
+			result["title"] = a.Request.Title
+		}
+		if len(uploaded) > 0 {
+			result["uploaded_images"] = uploaded
+		}
 		if resp.Data.HTML != "" {
 			result["html_preview"] = resp.Data.HTML[:min(200, len(resp.Data.HTML))] + "..."
 		}
 		output.Success(result)
 	} else {
-		output.ErrorWithCode(fmt.Sprintf("API_ERROR_%d", resp.Code), resp.Msg)
+		output.Error(resp.Err())
 	}
 }
 
-// readFileContent 读取文件内容
-func readFileContent(path string) (string, error) {
-	data, err := os.ReadFile(path)
+// createNewsDraft 将多篇文章提交为一个多图文草稿并输出结果
+func createNewsDraft(ctx context.Context, client *api.Client, articles []*preparedArticle, uploaded []uploadedImage) {
+	req := &api.NewsDraftRequest{}
+	summary := make([]map[string]interface{}, 0, len(articles))
+	for _, a := range articles {
+		req.Articles = append(req.Articles, *a.Request)
+		summary = append(summary, map[string]interface{}{
+			"source": a.Source,
+			"title":  a.Request.Title,
+			"theme":  a.Request.Theme,
+		})
+	}
+
+	resp, err := client.NewsDraftContext(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("读取文件失败: %w", err)
+		exitOnRequestError(err)
+	}
+	if err := resp.Err(); err != nil {
+		output.Error(err)
+	}
+
+	result := map[string]interface{}{
+		"draft_id":  resp.Data.DraftID,
+		"media_id":  resp.Data.MediaID,
+		"published": resp.Data.Published,
+		"count":     len(articles),
+		"articles":  summary,
+	}
+	if len(uploaded) > 0 {
+		result["uploaded_images"] = uploaded
+	}
+	output.Success(result)
+}
+
+// articleDraftSources 根据 --markdown、--file 或 --manifest 收集待提交的文章
+func articleDraftSources(cmd *cobra.Command) ([]articleSource, error) {
+	overrides := articleFlagOverrides(cmd)
+
+	switch {
+	case flagManifest != "":
+		m, err := manifest.Load(flagManifest)
+		if err != nil {
+			return nil, err
+		}
+		sources := make([]articleSource, 0, len(m.Articles))
+		for _, entry := range m.Articles {
+			sources = append(sources, articleSource{
+				Path:      entry.File,
+				Overrides: mergeArticle(overrides, entry),
+				Defaults:  m.Defaults,
+			})
+		}
+		return sources, nil
+	case len(flagMarkdownFiles) > 0:
+		sources := make([]articleSource, 0, len(flagMarkdownFiles))
+		for _, path := range flagMarkdownFiles {
+			sources = append(sources, articleSource{Path: path, Overrides: overrides})
+		}
+		return sources, nil
+	default:
+		return []articleSource{{Content: flagMarkdown, Overrides: overrides}}, nil
+	}
+}
+
+// articleFlagOverrides 将命令行参数转换为文章参数，未指定的参数为空值
+func articleFlagOverrides(cmd *cobra.Command) manifest.Article {
+	o := manifest.Article{
+		Theme:          flagTheme,
+		FontSize:       flagFontSize,
+		BackgroundType: flagBackgroundType,
+		Cover:          flagCoverImage,
+		Title:          flagArticleTitle,
+		Author:         flagArticleAuthor,
+		Digest:         flagArticleDigest,
+		SourceURL:      flagArticleSourceURL,
+		Crop235:        flagCrop235,
+		Crop11:         flagCrop11,
+	}
+	if cmd.Flags().Changed("open-comment") {
+		o.NeedOpenComment = &flagOpenComment
+	}
+	if cmd.Flags().Changed("fans-only-comment") {
+		o.OnlyFansCanComment = &flagFansOnlyComment
 	}
-	return string(data), nil
+	return o
 }
 
 // min 返回两个整数中的较小值
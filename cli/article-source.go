@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/manifest"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/markdown"
+)
+
+// articleSource 一篇待提交的文章
+//
+// 参数优先级：Overrides（命令行、清单条目）> front matter > Defaults（清单顶层）> 配置文件
+// （含项目配置 .md2wx.yaml）。
+type articleSource struct {
+	// Path Markdown 文件路径，- 表示标准输入，--markdown 时为空
+	Path string
+	// Content 未指定 Path 时使用的 Markdown 内容
+	Content string
+	// Overrides 优先于 front matter 的参数
+	Overrides manifest.Article
+	// Defaults 低于 front matter 的参数
+	Defaults manifest.Article
+}
+
+// preparedArticle 已合并参数、尚未上传本地图片的文章
+type preparedArticle struct {
+	// Source 文章来源，用于输出和错误信息
+	Source  string
+	Request *api.ArticleDraftRequest
+	// FrontMatter 文章的 front matter，没有时为空结构
+	FrontMatter *markdown.FrontMatter
+
+	baseDir      string
+	cover        string
+	coverBaseDir string
+}
+
+// name 返回用于输出的文章来源
+func (s articleSource) name() string {
+	switch s.Path {
+	case "":
+		return "--markdown"
+	case stdinPath:
+		return "stdin"
+	}
+	return s.Path
+}
+
+// prepareArticle 读取文章，拆分 front matter，合并样式和元数据并校验
+//
+// 未在文章和命令行中指定的作者、封面和样式取配置 c 的默认值。
+func prepareArticle(c *config.Config, src articleSource, convertVersion string) (*preparedArticle, error) {
+	content := src.Content
+	baseDir := "."
+	if src.Path != "" {
+		data, err := readFileContent(src.Path)
+		if err != nil {
+			return nil, err
+		}
+		content = data
+		baseDir = filepath.Dir(src.Path)
+	}
+
+	fm, body, err := markdown.SplitFrontMatter(content)
+	if err != nil {
+		return nil, err
+	}
+	if fm == nil {
+		fm = &markdown.FrontMatter{}
+	}
+
+	o, d := src.Overrides, src.Defaults
+	req := &api.ArticleDraftRequest{
+		Markdown:         body,
+		Theme:            o.Theme,
+		FontSize:         o.FontSize,
+		BackgroundType:   o.BackgroundType,
+		ConvertVersion:   convertVersion,
+		Title:            firstNonEmpty(o.Title, fm.Title, d.Title),
+		Author:           firstNonEmpty(o.Author, fm.Author, d.Author, c.DefaultAuthor),
+		Digest:           firstNonEmpty(o.Digest, fm.Digest, d.Digest),
+		ContentSourceUrl: firstNonEmpty(o.SourceURL, fm.SourceURL, d.SourceURL),
+	}
+
+	// 样式：front matter 之后使用清单顶层默认值，再之后才是配置文件
+	style := &markdown.FrontMatter{
+		Theme:          firstNonEmpty(fm.Theme, d.Theme),
+		FontSize:       firstNonEmpty(fm.FontSize, d.FontSize),
+		BackgroundType: firstNonEmpty(fm.BackgroundType, d.BackgroundType),
+	}
+	if err := applyStyleDefaults(c, style, &req.Theme, &req.FontSize, &req.BackgroundType); err != nil {
+		return nil, err
+	}
+
+	req.NeedOpenComment = firstBool(o.NeedOpenComment, fm.NeedOpenComment, d.NeedOpenComment)
+	req.OnlyFansCanComment = firstBool(o.OnlyFansCanComment, fm.OnlyFansCanComment, d.OnlyFansCanComment)
+
+	if req.PicCrop235_1, err = normalizeCrop(firstNonEmpty(o.Crop235, d.Crop235)); err != nil {
+		return nil, fmt.Errorf("封面 2.35:1 裁剪坐标 %w", err)
+	}
+	if req.PicCrop1_1, err = normalizeCrop(firstNonEmpty(o.Crop11, d.Crop11)); err != nil {
+		return nil, fmt.Errorf("封面 1:1 裁剪坐标 %w", err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 封面图：命令行和清单中的路径以当前目录为基准，front matter 以文件目录为基准
+	a := &preparedArticle{Source: src.name(), Request: req, FrontMatter: fm, baseDir: baseDir}
+	switch {
+	case o.Cover != "":
+		a.cover, a.coverBaseDir = o.Cover, "."
+	case fm.Cover != "":
+		a.cover, a.coverBaseDir = fm.Cover, baseDir
+	default:
+		// 项目配置中的封面路径已转换为以 .md2wx.yaml 所在目录为基准
+		a.cover, a.coverBaseDir = firstNonEmpty(d.Cover, c.DefaultCover), "."
+	}
+	// 本地封面在 uploadImages 上传后才写入请求，本地路径不会提交给服务端
+	if !markdown.IsLocalPath(a.cover) {
+		req.CoverImageUrl = a.cover
+	}
+
+	return a, nil
+}
+
+// uploadImages 上传正文中的本地图片和本地封面图，并替换为微信 CDN 地址
+//
+// cache 应与 client 使用同一份配置创建（见 newImageCache），为 nil 时不使用上传缓存。
+func (a *preparedArticle) uploadImages(ctx context.Context, client *api.Client, cache *imageCache) ([]uploadedImage, error) {
+	body, uploaded, err := rewriteLocalImages(ctx, client, cache, a.Request.Markdown, a.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	a.Request.Markdown = body
+
+	cover, img, err := uploadLocalCover(ctx, client, cache, a.cover, a.coverBaseDir)
+	if err != nil {
+		return nil, err
+	}
+	a.Request.CoverImageUrl = cover
+	if img != nil {
+		uploaded = append(uploaded, *img)
+	}
+	return uploaded, nil
+}
+
+// checkLocalCover 不上传本地图片时调用：本地封面必须上传后才能使用，返回错误
+func (a *preparedArticle) checkLocalCover() error {
+	if markdown.IsLocalPath(a.cover) {
+		return fmt.Errorf("封面图 %s 是本地文件，需要开启本地图片上传（--upload-local-images 或配置 upload.local_images）或改用图片 URL", a.cover)
+	}
+	return nil
+}
+
+// mergeArticle 合并两组参数，high 中已设置的字段优先
+func mergeArticle(high, low manifest.Article) manifest.Article {
+	return manifest.Article{
+		File:               firstNonEmpty(high.File, low.File),
+		Theme:              firstNonEmpty(high.Theme, low.Theme),
+		FontSize:           firstNonEmpty(high.FontSize, low.FontSize),
+		BackgroundType:     firstNonEmpty(high.BackgroundType, low.BackgroundType),
+		Cover:              firstNonEmpty(high.Cover, low.Cover),
+		Title:              firstNonEmpty(high.Title, low.Title),
+		Author:             firstNonEmpty(high.Author, low.Author),
+		Digest:             firstNonEmpty(high.Digest, low.Digest),
+		SourceURL:          firstNonEmpty(high.SourceURL, low.SourceURL),
+		Crop235:            firstNonEmpty(high.Crop235, low.Crop235),
+		Crop11:             firstNonEmpty(high.Crop11, low.Crop11),
+		NeedOpenComment:    firstBoolPtr(high.NeedOpenComment, low.NeedOpenComment),
+		OnlyFansCanComment: firstBoolPtr(high.OnlyFansCanComment, low.OnlyFansCanComment),
+	}
+}
+
+// normalizeCrop 转换裁剪坐标，空字符串表示不裁剪
+func normalizeCrop(crop string) (string, error) {
+	if crop == "" {
+		return "", nil
+	}
+	return api.NormalizeCrop(crop)
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// firstBoolPtr 返回第一个已设置的布尔值指针
+func firstBoolPtr(values ...*bool) *bool {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// firstBool 返回第一个已设置的布尔值，都未设置时为 false
+func firstBool(values ...*bool) bool {
+	if v := firstBoolPtr(values...); v != nil {
+		return *v
+	}
+	return false
+}
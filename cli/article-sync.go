@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/state"
+	"github.com/spf13/cobra"
+)
+
+// draftStateStore 返回配置目录下的草稿同步状态
+func draftStateStore() *state.Store {
+	return state.NewStore(filepath.Join(config.GetConfigDir(), "state"))
+}
+
+// validateArticleSyncFlags 校验 --sync、--prune 和 --dry-run
+func validateArticleSyncFlags() error {
+	if !flagArticleSync {
+		if flagArticlePrune || flagArticleDryRun {
+			return fmt.Errorf("--prune 和 --dry-run 需要与 --sync 一起使用")
+		}
+		return nil
+	}
+	if flagArticlePrune && flagArticleDir == "" {
+		return fmt.Errorf("--prune 需要与 --dir 一起使用")
+	}
+	if flagArticleDir != "" {
+		return nil
+	}
+	// 同步以文件路径为键，只支持单个文件
+	if len(flagMarkdownFiles) != 1 || flagMarkdownFiles[0] == stdinPath {
+		return fmt.Errorf("--sync 需要与 --dir 或单个 --file 文件一起使用（不支持标准输入、--markdown 和 --manifest）")
+	}
+	return nil
+}
+
+// articleHash 计算文章内容哈希
+//
+// 哈希基于合并参数后、上传图片前的请求以及引用的本地图片和封面文件内容，
+// 正文、front matter、生效的主题等参数变化或替换图片文件都会触发更新。
+func articleHash(a *preparedArticle) (string, error) {
+	data, err := json.Marshal(a.Request)
+	if err != nil {
+		return "", err
+	}
+	for _, file := range a.localFiles() {
+		content, err := os.ReadFile(file)
+		switch {
+		case os.IsNotExist(err):
+			// 缺失的图片在上传时报错，这里只记录路径
+			data = fmt.Appendf(data, "\n%s -", file)
+		case err != nil:
+			return "", fmt.Errorf("读取本地图片失败: %w", err)
+		default:
+			data = fmt.Appendf(data, "\n%s %s", file, state.Hash(content))
+		}
+	}
+	return state.Hash(data), nil
+}
+
+// syncArticle 按同步状态处理单个文件：内容未变时跳过，变化时更新原草稿，
+// 没有记录时创建草稿；dryRun 时只返回计划执行的操作
+func syncArticle(ctx context.Context, client *api.Client, cache *imageCache, store *state.Store, src articleSource, upload, dryRun bool) (dirResult, error) {
+	r := dirResult{File: src.Path, DryRun: dryRun}
+	file, err := filepath.Abs(src.Path)
+	if err != nil {
+		return r, err
+	}
+
+	// 读取记录到写回记录期间持有文件锁，并发同步同一文件时不会重复创建草稿
+	if !dryRun {
+		unlock, err := store.LockFile(cfg.Profile, file)
+		if err != nil {
+			return r, err
+		}
+		defer unlock()
+	}
+
+	a, err := prepareArticle(cfg, src, flagConvertVersion)
+	if err != nil {
+		return r, err
+	}
+	r.Title = a.Request.Title
+	hash, err := articleHash(a)
+	if err != nil {
+		return r, err
+	}
+	entry, found, err := store.Get(cfg.Profile, file)
+	if err != nil {
+		return r, err
+	}
+	r.Action = state.Decide(entry, found, hash)
+	if found {
+		r.MediaID = entry.MediaID
+	}
+	if dryRun || r.Action == state.ActionSkip {
+		return r, nil
+	}
+
+	if upload {
+		if r.UploadedImages, err = a.uploadImages(ctx, client, cache); err != nil {
+			return r, err
+		}
+	} else if err := a.checkLocalCover(); err != nil {
+		return r, err
+	}
+
+	mediaID := ""
+	if r.Action == state.ActionUpdate {
+		mediaID = entry.MediaID
+	}
+	action, created, err := saveDraft(ctx, client, mediaID, a.Request)
+	if err != nil {
+		return r, err
+	}
+	r.Action = action
+	if created != nil {
+		r.DraftID = created.Data.DraftID
+		r.MediaID = created.Data.MediaID
+	}
+
+	if err := store.Put(state.Entry{
+		Profile: cfg.Profile,
+		File:    file,
+		Hash:    hash,
+		MediaID: r.MediaID,
+		Title:   r.Title,
+	}); err != nil {
+		return r, fmt.Errorf("草稿已保存（media_id: %s），但记录同步状态失败: %w", r.MediaID, err)
+	}
+	return r, nil
+}
+
+// saveDraft 用 req 更新 mediaID 对应的草稿；mediaID 为空或草稿已在后台删除、
+// 已发布时创建新草稿。返回实际执行的操作，创建时同时返回创建结果
+func saveDraft(ctx context.Context, client *api.Client, mediaID string, req *api.ArticleDraftRequest) (state.Action, *api.ArticleDraftResponse, error) {
+	if mediaID != "" {
+		_, err := client.UpdateDraftContext(ctx, mediaID, &api.UpdateDraftRequest{Article: *req})
+		if err == nil {
+			return state.ActionUpdate, nil, nil
+		}
+		if !errors.Is(err, api.ErrInvalidMediaID) {
+			return "", nil, err
+		}
+		// 草稿已在后台删除或已发布，重新创建
+	}
+	resp, err := client.ArticleDraftContext(ctx, req)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return "", nil, err
+	}
+	return state.ActionCreate, resp, nil
+}
+
+// pruneArticleDir 删除源文件已不存在的草稿
+//
+// current 为本次找到的文件绝对路径。只处理 --dir 目录下且匹配 --glob 的记录，
+// 缩小 --glob 范围或同步其他目录不会误删草稿。
+func pruneArticleDir(ctx context.Context, client *api.Client, store *state.Store, current map[string]bool, dryRun bool) ([]dirResult, error) {
+	dir, err := filepath.Abs(flagArticleDir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := store.List(cfg.Profile)
+	if err != nil {
+		return nil, err
+	}
+	inScope := func(file string) bool {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return false
+		}
+		return matchArticleGlob(dir, file, flagArticleGlob)
+	}
+
+	var results []dirResult
+	for _, e := range state.Orphans(entries, current, inScope) {
+		r := dirResult{
+			Type:    "result",
+			File:    e.File,
+			Action:  state.ActionDelete,
+			MediaID: e.MediaID,
+			Title:   e.Title,
+			DryRun:  dryRun,
+		}
+		if !dryRun {
+			err := deleteOrphanDraft(ctx, client, store, e)
+			if err != nil {
+				r.ErrorResponse = output.NewErrorResponse(contextError(err))
+				results = append(results, r)
+				if ctx.Err() != nil {
+					break
+				}
+				continue
+			}
+		}
+		r.Success = true
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// deleteOrphanDraft 删除草稿及其同步记录，草稿已不存在时只删除记录
+func deleteOrphanDraft(ctx context.Context, client *api.Client, store *state.Store, e state.Entry) error {
+	if _, err := client.DeleteDraftContext(ctx, e.MediaID); err != nil && !errors.Is(err, api.ErrInvalidMediaID) {
+		return err
+	}
+	return store.Delete(e.Profile, e.File)
+}
+
+// runArticleSync 同步单个文件并输出结果
+func runArticleSync(cmd *cobra.Command) {
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	src := articleSource{Path: flagMarkdownFiles[0], Overrides: articleFlagOverrides(cmd)}
+	r, err := syncArticle(ctx, client, newImageCache(cfg), draftStateStore(), src, uploadLocalImages(cmd, flagUploadLocalImages), flagArticleDryRun)
+	if err != nil {
+		exitOnRequestError(err)
+	}
+
+	result := map[string]interface{}{
+		"action":   r.Action,
+		"file":     r.File,
+		"media_id": r.MediaID,
+	}
+	if r.DraftID != "" {
+		result["draft_id"] = r.DraftID
+	}
+	if r.Title != "" {
+		result["title"] = r.Title
+	}
+	if r.DryRun {
+		result["dry_run"] = true
+	}
+	if len(r.UploadedImages) > 0 {
+		result["uploaded_images"] = r.UploadedImages
+	}
+	output.Success(result)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/markdown"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/state"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/watch"
+	"github.com/spf13/cobra"
+)
+
+// watchDebounce 最后一次保存后等待的时间，避免编辑器分多次写入时重复更新
+const watchDebounce = 300 * time.Millisecond
+
+// validateArticleWatchFlags 校验 --watch 和 --interval
+func validateArticleWatchFlags(cmd *cobra.Command) error {
+	if !flagArticleWatch {
+		if cmd.Flags().Changed("interval") {
+			return fmt.Errorf("--interval 需要与 --watch 一起使用")
+		}
+		return nil
+	}
+	if len(flagMarkdownFiles) != 1 || flagMarkdownFiles[0] == stdinPath {
+		return fmt.Errorf("--watch 需要与单个 --file 文件一起使用（不支持标准输入、--markdown、--manifest 和 --dir）")
+	}
+	if flagArticleDryRun {
+		return fmt.Errorf("--watch 不能与 --dry-run 一起使用")
+	}
+	if flagArticleInterval <= 0 {
+		return fmt.Errorf("--interval 必须大于 0")
+	}
+	return nil
+}
+
+// localFiles 返回文章引用的本地图片和本地封面文件，没有时为空列表（非 nil）
+func (a *preparedArticle) localFiles() []string {
+	files := []string{}
+	for _, ref := range markdown.LocalImages(a.Request.Markdown) {
+		files = append(files, resolveLocalPath(ref, a.baseDir))
+	}
+	if markdown.IsLocalPath(a.cover) {
+		files = append(files, resolveLocalPath(a.cover, a.coverBaseDir))
+	}
+	return files
+}
+
+// draftWatcher 文件保存后更新同一个草稿
+type draftWatcher struct {
+	client *api.Client
+	// cache 与 client 同一公众号的图片上传缓存，可为 nil
+	cache  *imageCache
+	src    articleSource
+	upload bool
+	// store --sync 时记录同步状态，否则为 nil
+	store *state.Store
+	file  string
+
+	mediaID string
+	hash    string
+}
+
+// publish 创建或更新草稿，返回执行的操作和文章引用的本地文件
+//
+// 内容哈希未变化且 force 为 false 时跳过；本地图片变化时 force 为 true，
+// 图片会重新上传（内容未变的图片命中上传缓存）。草稿已在后台删除时重新创建。
+// --sync 时与 article draft --sync 一样在读取到写回同步记录期间持有文件锁，
+// 并以记录中的草稿为准（其他进程可能已同步过同一文件）。
+func (w *draftWatcher) publish(ctx context.Context, force bool) (state.Action, []string, error) {
+	a, err := prepareArticle(cfg, w.src, flagConvertVersion)
+	if err != nil {
+		return "", nil, err
+	}
+	files := a.localFiles()
+	hash, err := articleHash(a)
+	if err != nil {
+		return "", files, err
+	}
+
+	if w.store != nil {
+		unlock, err := w.store.LockFile(cfg.Profile, w.file)
+		if err != nil {
+			return "", files, err
+		}
+		defer unlock()
+		entry, found, err := w.store.Get(cfg.Profile, w.file)
+		if err != nil {
+			return "", files, err
+		}
+		if found {
+			w.mediaID, w.hash = entry.MediaID, entry.Hash
+		}
+	}
+	if w.mediaID != "" && hash == w.hash && !force {
+		return state.ActionSkip, files, nil
+	}
+
+	if w.upload {
+		if _, err := a.uploadImages(ctx, w.client, w.cache); err != nil {
+			return "", files, err
+		}
+	} else if err := a.checkLocalCover(); err != nil {
+		return "", files, err
+	}
+
+	action, created, err := saveDraft(ctx, w.client, w.mediaID, a.Request)
+	if err != nil {
+		return "", files, err
+	}
+	if created != nil {
+		w.mediaID = created.Data.MediaID
+	}
+	w.hash = hash
+
+	if w.store != nil {
+		err := w.store.Put(state.Entry{
+			Profile: cfg.Profile,
+			File:    w.file,
+			Hash:    hash,
+			MediaID: w.mediaID,
+			Title:   a.Request.Title,
+		})
+		if err != nil {
+			return action, files, fmt.Errorf("草稿已保存，但记录同步状态失败: %w", err)
+		}
+	}
+	return action, files, nil
+}
+
+// report 输出一行状态
+func (w *draftWatcher) report(action state.Action, err error) {
+	now := time.Now().Format("15:04:05")
+	switch {
+	case err != nil:
+		output.PrintError("✗ [%s] 更新失败: %v", now, err)
+	case action == state.ActionCreate:
+		output.PrintSuccess("✓ [%s] 已创建草稿 %s", now, w.mediaID)
+	case action == state.ActionUpdate:
+		output.PrintSuccess("✓ [%s] 已更新草稿 %s", now, w.mediaID)
+	default:
+		output.PrintSuccess("- [%s] 内容未变化，草稿 %s 无需更新", now, w.mediaID)
+	}
+}
+
+// runArticleWatch 创建（或按 --sync 记录复用）草稿，之后每次保存 Markdown 或
+// 引用的本地图片时更新同一个草稿，直到 Ctrl-C
+func runArticleWatch(cmd *cobra.Command) {
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	path := flagMarkdownFiles[0]
+	file, err := filepath.Abs(path)
+	if err != nil {
+		output.Error(err)
+	}
+	w := &draftWatcher{
+		client: client,
+		cache:  newImageCache(cfg),
+		src:    articleSource{Path: path, Overrides: articleFlagOverrides(cmd)},
+		upload: uploadLocalImages(cmd, flagUploadLocalImages),
+		file:   file,
+	}
+	if flagArticleSync {
+		// 每次提交时读取同步记录，复用已同步的草稿
+		w.store = draftStateStore()
+	}
+
+	// 首次提交失败时继续监听，下次保存时重试
+	action, images, err := w.publish(ctx, false)
+	if ctx.Err() != nil {
+		exitOnRequestError(ctx.Err())
+	}
+	w.report(action, err)
+
+	watcher := watch.New(append([]string{path}, images...), flagArticleInterval, watchDebounce)
+	output.PrintSuccess("  监听文件: %s 及 %d 个本地图片（保存后自动更新草稿，Ctrl-C 停止）", path, len(images))
+
+	watcher.Run(ctx, func(changed []string) {
+		// 只有 Markdown 变化时按内容哈希判断，图片变化时总是重新提交
+		force := slices.ContainsFunc(changed, func(p string) bool { return p != path })
+		action, files, err := w.publish(ctx, force)
+		if ctx.Err() != nil {
+			return
+		}
+		w.report(action, err)
+		// 文章读取失败时保留原来的监听列表
+		if files != nil {
+			watcher.SetPaths(append([]string{path}, files...))
+		}
+	})
+	output.PrintSuccess("监听已停止")
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/manifest"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/state"
+)
+
+// useTestConfig 使用空配置，测试结束后恢复
+func useTestConfig(t *testing.T) {
+	t.Helper()
+	old := cfg
+	cfg = &config.Config{}
+	t.Cleanup(func() { cfg = old })
+}
+
+// writeArticle 在 dir 下写入文章并返回路径
+func writeArticle(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "article.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLocalFiles(t *testing.T) {
+	useTestConfig(t)
+	dir := t.TempDir()
+	path := writeArticle(t, dir, "---\ntitle: 标题\ncover: img/cover.jpg\n---\n\n"+
+		"![](img/a.png)\n![](https://example.com/b.png)\n![](/abs/c.png)\n")
+
+	a, err := prepareArticle(cfg, articleSource{Path: path}, "v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 正文图片和 front matter 封面以文章所在目录为基准，公网图片不监听
+	want := []string{filepath.Join(dir, "img/a.png"), "/abs/c.png", filepath.Join(dir, "img/cover.jpg")}
+	if got := a.localFiles(); !slices.Equal(got, want) {
+		t.Errorf("localFiles() = %v, want %v", got, want)
+	}
+
+	// 命令行指定的封面以当前目录为基准
+	a, err = prepareArticle(cfg, articleSource{Path: path, Overrides: manifest.Article{Cover: "cover.png"}}, "v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := a.localFiles(); got[len(got)-1] != "cover.png" {
+		t.Errorf("localFiles() with --cover = %v", got)
+	}
+
+	writeArticle(t, dir, "# 标题\n\n![](https://example.com/b.png)\n")
+	a, err = prepareArticle(cfg, articleSource{Path: path}, "v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := a.localFiles(); got == nil || len(got) != 0 {
+		t.Errorf("localFiles() without local files = %#v, want empty non-nil", got)
+	}
+}
+
+// fakeDraftServer 模拟创建和更新草稿的接口，创建的草稿依次为 d1、d2…，
+// updateCode 为更新草稿返回的错误码
+type fakeDraftServer struct {
+	creates, updates int
+	updateCode       int
+	// updated 最近一次更新的 media_id
+	updated string
+}
+
+func (s *fakeDraftServer) start(t *testing.T) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/v1/article-draft":
+			s.creates++
+			json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{"media_id": fmt.Sprintf("d%d", s.creates)},
+			})
+		case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/api/v1/drafts/"):
+			s.updates++
+			s.updated = strings.TrimPrefix(r.URL.Path, "/api/v1/drafts/")
+			json.NewEncoder(w).Encode(map[string]any{"code": s.updateCode, "msg": "invalid media_id"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return api.NewClient(server.URL, "test-app-id", "test-app-secret", "test-api-key")
+}
+
+func TestDraftWatcher_Publish(t *testing.T) {
+	useTestConfig(t)
+	dir := t.TempDir()
+	path := writeArticle(t, dir, "# 标题\n\n正文\n")
+	server := &fakeDraftServer{}
+	w := &draftWatcher{client: server.start(t), src: articleSource{Path: path}, file: path}
+	ctx := context.Background()
+
+	publish := func(force bool, want state.Action) {
+		t.Helper()
+		action, _, err := w.publish(ctx, force)
+		if err != nil || action != want {
+			t.Fatalf("publish(force=%v) = %q, %v; want %q", force, action, err, want)
+		}
+	}
+
+	// 首次提交创建草稿
+	publish(false, state.ActionCreate)
+	if w.mediaID != "d1" || server.creates != 1 {
+		t.Fatalf("after create: media_id = %q, creates = %d", w.mediaID, server.creates)
+	}
+
+	// 内容未变化时跳过，不发送请求
+	publish(false, state.ActionSkip)
+	if server.creates != 1 || server.updates != 0 {
+		t.Errorf("skip sent requests: creates = %d, updates = %d", server.creates, server.updates)
+	}
+
+	// 本地图片变化（force）或 Markdown 变化时更新同一个草稿
+	publish(true, state.ActionUpdate)
+	writeArticle(t, dir, "# 标题\n\n修改后的正文\n")
+	publish(false, state.ActionUpdate)
+	if w.mediaID != "d1" || server.updated != "d1" || server.updates != 2 || server.creates != 1 {
+		t.Errorf("after update: media_id = %q, updated = %q, updates = %d, creates = %d", w.mediaID, server.updated, server.updates, server.creates)
+	}
+}
+
+func TestDraftWatcher_PublishRecreatesDeletedDraft(t *testing.T) {
+	useTestConfig(t)
+	path := writeArticle(t, t.TempDir(), "# 标题\n\n正文\n")
+	// 草稿已在后台删除，更新返回 40007（ErrInvalidMediaID）
+	server := &fakeDraftServer{updateCode: 40007}
+	w := &draftWatcher{client: server.start(t), src: articleSource{Path: path}, file: path, mediaID: "m1", hash: "old"}
+
+	action, _, err := w.publish(context.Background(), false)
+	if err != nil || action != state.ActionCreate {
+		t.Fatalf("publish() = %q, %v; want create", action, err)
+	}
+	if server.updated != "m1" || server.creates != 1 || w.mediaID != "d1" {
+		t.Errorf("updated = %q, creates = %d, media_id = %q", server.updated, server.creates, w.mediaID)
+	}
+
+	// 之后按新草稿的内容哈希判断
+	if action, _, _ := w.publish(context.Background(), false); action != state.ActionSkip {
+		t.Errorf("publish() after recreate = %q, want skip", action)
+	}
+}
+
+func TestDraftWatcher_PublishSync(t *testing.T) {
+	useTestConfig(t)
+	cfg.Profile = "default"
+	path := writeArticle(t, t.TempDir(), "# 标题\n\n正文\n")
+	store := state.NewStore(t.TempDir())
+	server := &fakeDraftServer{}
+	w := &draftWatcher{client: server.start(t), src: articleSource{Path: path}, file: path, store: store}
+
+	// 其他进程同步过同一文件后，以同步记录中的草稿为准
+	if err := store.Put(state.Entry{Profile: "default", File: path, Hash: "old", MediaID: "m1"}); err != nil {
+		t.Fatal(err)
+	}
+	action, _, err := w.publish(context.Background(), false)
+	if err != nil || action != state.ActionUpdate || server.updated != "m1" || server.creates != 0 {
+		t.Fatalf("publish() = %q, %v; updated = %q, creates = %d", action, err, server.updated, server.creates)
+	}
+	entry, found, err := store.Get("default", path)
+	if err != nil || !found || entry.MediaID != "m1" || entry.Hash == "old" {
+		t.Errorf("entry = %+v, %v, %v", entry, found, err)
+	}
+
+	// 提交后释放文件锁
+	unlock, err := store.LockFile("default", path)
+	if err != nil {
+		t.Fatalf("LockFile() after publish error = %v", err)
+	}
+	unlock()
+}
+
+func TestPrepareArticle_LocalCover(t *testing.T) {
+	useTestConfig(t)
+	path := writeArticle(t, t.TempDir(), "---\ntitle: 标题\ncover: cover.jpg\n---\n\n正文\n")
+
+	// 本地封面上传前不写入请求，未开启上传时报错
+	a, err := prepareArticle(cfg, articleSource{Path: path}, "v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Request.CoverImageUrl != "" {
+		t.Errorf("CoverImageUrl = %q, want empty before upload", a.Request.CoverImageUrl)
+	}
+	if err := a.checkLocalCover(); err == nil {
+		t.Error("checkLocalCover() with local cover should fail")
+	}
+
+	a, err = prepareArticle(cfg, articleSource{Path: path, Overrides: manifest.Article{Cover: "https://example.com/c.jpg"}}, "v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Request.CoverImageUrl != "https://example.com/c.jpg" || a.checkLocalCover() != nil {
+		t.Errorf("remote cover: CoverImageUrl = %q, checkLocalCover() = %v", a.Request.CoverImageUrl, a.checkLocalCover())
+	}
+}
@@ -2,90 +2,264 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/imgcache"
 	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
 	"github.com/spf13/cobra"
 )
 
 // BatchUploadCmd 批量上传命令
 var BatchUploadCmd = &cobra.Command{
-	Use:   "batch-upload",
+	Use:   "batch-upload [files...]",
 	Short: "批量上传图片素材",
-	Long:  `批量上传图片到微信公众号素材库`,
-	Args:  cobra.NoArgs,
+	Long: `批量上传图片到微信公众号素材库。
+
+--images 和位置参数支持公网 URL、本地文件、目录和通配符：
+  md2wx batch-upload --images "https://cdn.example.com/a.jpg,./assets/*.png"
+  md2wx batch-upload ./assets/banner.jpg ./assets/icons/
+
+目录只上传其中的图片文件（不递归）。本地文件先在本地检查格式和大小，
+不符合要求的文件直接在结果中标记失败，不影响其余文件上传。
+
+上传成功的图片记录在本地缓存中（本地文件按内容 SHA-256，公网图片按 URL），
+再次上传相同图片时直接返回缓存的地址和 media_id（结果中 cached 为 true），
+不消耗素材配额。--no-cache 跳过缓存，'md2wx cache' 管理缓存。`,
+	Args: cobra.ArbitraryArgs,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		return validateBatchUploadFlags()
+		return validateBatchUploadFlags(args)
 	},
 	Run: runBatchUpload,
 }
 
 var flagUploadImages string
 
+// maxUploadImageSize 微信永久图片素材大小上限
+const maxUploadImageSize = 10 << 20
+
+// uploadImageTypes 微信永久图片素材支持的 MIME 类型
+var uploadImageTypes = []string{"image/jpeg", "image/png", "image/gif", "image/bmp"}
+
+// uploadImageExts 目录展开时识别为图片的扩展名
+var uploadImageExts = []string{".jpg", ".jpeg", ".png", ".gif", ".bmp"}
+
 func init() {
-	BatchUploadCmd.Flags().StringVar(&flagUploadImages, "images", "", "图片 URL，多个用逗号分隔")
+	BatchUploadCmd.Flags().StringVar(&flagUploadImages, "images", "", "图片 URL、本地文件、目录或通配符，多个用逗号分隔")
+	addRetriesFlag(BatchUploadCmd)
 }
 
-func validateBatchUploadFlags() error {
-	if flagUploadImages == "" {
-		return fmt.Errorf("必须提供 --images 参数")
+func validateBatchUploadFlags(args []string) error {
+	if flagUploadImages == "" && len(args) == 0 {
+		return fmt.Errorf("必须提供 --images 参数或文件参数")
 	}
 
-	imageUrls := parseImageList(flagUploadImages)
-	if len(imageUrls) == 0 {
+	inputs := append(parseImageList(flagUploadImages), args...)
+	if len(inputs) == 0 {
 		return fmt.Errorf("至少需要一张图片")
 	}
 
 	// 检查配置
-	if cfg.WechatAppID == "" {
-		return fmt.Errorf("wechat_appid 未配置，请使用 'config set wechat-appid' 设置")
-	}
-	if cfg.WechatAppSecret == "" {
-		return fmt.Errorf("wechat_appsecret 未配置，请使用 'config set wechat-appsecret' 设置")
+	return checkCredentials()
+}
+
+func runBatchUpload(cmd *cobra.Command, args []string) {
+	// 解析图片列表，展开本地目录和通配符
+	urls, files, err := expandImageInputs(append(parseImageList(flagUploadImages), args...))
+	if err != nil {
+		output.Error(err)
 	}
-	if cfg.APIKey == "" {
-		return fmt.Errorf("api_key 未配置，请使用 'config set api-key' 设置")
+
+	// 创建 API 客户端
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
 	}
 
-	return nil
-}
+	// Ctrl-C 或 --timeout 会中止请求
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 
-func runBatchUpload(cmd *cobra.Command, args []string) {
-	// 解析图片列表
-	imageUrls := parseImageList(flagUploadImages)
+	cache := newImageCache(cfg)
+	var results []api.UploadResult
 
-	// 获取 API Base URL（命令行参数优先）
-	apiBase := cfg.APIBaseURL
-	if apiBaseFlag, _ := cmd.Parent().PersistentFlags().GetString("api-base"); apiBaseFlag != "" {
-		apiBase = apiBaseFlag
+	// 上传公网 URL（已上传过的地址使用缓存结果）
+	var pending []string
+	for _, u := range urls {
+		if e, ok := cache.lookup(ctx, imgcache.URLKey(u)); ok {
+			results = append(results, cachedResult(u, e))
+		} else {
+			pending = append(pending, u)
+		}
+	}
+	if len(pending) > 0 {
+		req := &api.BatchUploadRequest{
+			ImageUrls: pending,
+		}
+		resp, err := client.BatchUploadContext(ctx, req)
+		if err != nil {
+			exitOnRequestError(err)
+		}
+		if err := resp.Err(); err != nil {
+			output.Error(err)
+		}
+		for i, r := range resp.Data.Results {
+			// 结果按请求顺序返回，服务端未返回来源时按位置对应
+			if r.Source == "" && len(resp.Data.Results) == len(pending) {
+				r.Source = pending[i]
+			}
+			if r.Success && r.Source != "" {
+				cache.store(imgcache.URLKey(r.Source), r.Source, r)
+			}
+			results = append(results, r)
+		}
 	}
 
-	// 获取 API Key（命令行参数优先）
-	apiKey := cfg.APIKey
-	if apiKeyFlag, _ := cmd.Parent().PersistentFlags().GetString("api-key"); apiKeyFlag != "" {
-		apiKey = apiKeyFlag
+	// 上传本地文件（本地检查未通过的文件直接记为失败，内容相同的文件使用缓存结果）
+	if len(files) > 0 {
+		uploads, rejected := loadUploadFiles(files)
+		results = append(results, rejected...)
+		// keys 与 pending 一一对应
+		var keys []string
+		var pending []api.UploadFile
+		for _, f := range uploads {
+			key := imgcache.FileKey(f.Data)
+			if e, ok := cache.lookup(ctx, key); ok {
+				results = append(results, cachedResult(f.Name, e))
+				continue
+			}
+			keys = append(keys, key)
+			pending = append(pending, f)
+		}
+		if len(pending) > 0 {
+			resp, err := client.BatchUploadFilesContext(ctx, pending)
+			if err != nil {
+				exitOnRequestError(err)
+			}
+			if err := resp.Err(); err != nil {
+				output.Error(err)
+			}
+			// 结果按请求顺序返回，按位置对应本地文件写入缓存（服务端返回的
+			// Source 可能只是文件名，不能用来查找）；数量不一致时无法对应，不缓存
+			if len(resp.Data.Results) == len(pending) {
+				for i, r := range resp.Data.Results {
+					if r.Success {
+						cache.store(keys[i], pending[i].Name, r)
+					}
+				}
+			}
+			results = append(results, resp.Data.Results...)
+		}
 	}
 
-	// 创建 API 客户端
-	client := api.NewClient(apiBase, cfg.WechatAppID, cfg.WechatAppSecret, apiKey)
+	// 输出结果
+	succeeded, cached := 0, 0
+	for _, r := range results {
+		if r.Success {
+			succeeded++
+		}
+		if r.Cached {
+			cached++
+		}
+	}
+	output.Success(map[string]interface{}{
+		"results":   results,
+		"total":     len(results),
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+		"cached":    cached,
+	})
+}
 
-	// 构建请求
-	req := &api.BatchUploadRequest{
-		ImageUrls: imageUrls,
+// expandImageInputs 将输入拆分为公网 URL 和本地文件，展开目录和通配符
+func expandImageInputs(inputs []string) ([]string, []string, error) {
+	var urls, files []string
+	addFile := func(path string) {
+		if !slices.Contains(files, path) {
+			files = append(files, path)
+		}
 	}
 
-	// 调用 API
-	resp, err := client.BatchUpload(req)
-	if err != nil {
-		output.Error(err)
+	for _, input := range inputs {
+		lower := strings.ToLower(input)
+		if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+			if !slices.Contains(urls, input) {
+				urls = append(urls, input)
+			}
+			continue
+		}
+
+		// 通配符
+		if strings.ContainsAny(input, "*?[") {
+			matches, err := filepath.Glob(input)
+			if err != nil {
+				return nil, nil, fmt.Errorf("无效的通配符 %s: %w", input, err)
+			}
+			if len(matches) == 0 {
+				return nil, nil, fmt.Errorf("通配符 %s 没有匹配的文件", input)
+			}
+			for _, m := range matches {
+				if info, err := os.Stat(m); err == nil && !info.IsDir() {
+					addFile(m)
+				}
+			}
+			continue
+		}
+
+		info, err := os.Stat(input)
+		if err != nil {
+			return nil, nil, fmt.Errorf("文件不存在: %s", input)
+		}
+		if !info.IsDir() {
+			addFile(input)
+			continue
+		}
+
+		// 目录：只取图片文件
+		entries, err := os.ReadDir(input)
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取目录失败: %w", err)
+		}
+		for _, e := range entries {
+			ext := strings.ToLower(filepath.Ext(e.Name()))
+			if !e.IsDir() && slices.Contains(uploadImageExts, ext) {
+				addFile(filepath.Join(input, e.Name()))
+			}
+		}
 	}
 
-	// 输出结果
-	if resp.Code == 0 {
-		output.Success(map[string]interface{}{
-			"results": resp.Data.Results,
-		})
-	} else {
-		output.ErrorWithCode(fmt.Sprintf("API_ERROR_%d", resp.Code), resp.Msg)
+	if len(urls) == 0 && len(files) == 0 {
+		return nil, nil, fmt.Errorf("没有找到可上传的图片")
+	}
+	return urls, files, nil
+}
+
+// loadUploadFiles 读取本地文件并检查格式和大小，返回待上传文件和本地拒绝的结果
+func loadUploadFiles(paths []string) ([]api.UploadFile, []api.UploadResult) {
+	var uploads []api.UploadFile
+	var rejected []api.UploadResult
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			rejected = append(rejected, api.UploadResult{Source: path, Error: fmt.Sprintf("读取文件失败: %v", err)})
+			continue
+		}
+
+		mimeType := api.DetectContentType(path, data)
+		result := api.UploadResult{Source: path, Size: int64(len(data)), MimeType: mimeType}
+		switch {
+		case !slices.Contains(uploadImageTypes, mimeType):
+			result.Error = fmt.Sprintf("不支持的文件类型: %s（支持 jpg/png/gif/bmp）", mimeType)
+			rejected = append(rejected, result)
+		case len(data) > maxUploadImageSize:
+			result.Error = fmt.Sprintf("文件大小 %d 字节超过 10MB 限制", len(data))
+			rejected = append(rejected, result)
+		default:
+			uploads = append(uploads, api.UploadFile{Name: path, Data: data})
+		}
 	}
+	return uploads, rejected
 }
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/imgcache"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// CacheCmd 图片上传缓存管理命令
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "管理图片上传缓存",
+	Long: `查看和清理图片上传缓存。
+
+batch-upload 和文章中本地图片、封面的上传结果会记录在 ~/.md2wx/cache/images.json
+（本地文件按内容 SHA-256，公网图片按 URL，按公众号 AppID 区分），再次上传相同
+图片时直接使用缓存的微信地址和 media_id，不重复消耗素材配额。
+
+相关配置：
+  upload.cache          是否使用缓存（默认 true），单次可用 --no-cache 跳过
+  upload.cache_ttl      缓存有效期，如 720h（默认不过期）
+  upload.cache_verify   使用缓存前检查图片地址是否仍可访问（默认 false）
+
+默认只操作当前公众号（wechat_appid）的缓存，--all 操作全部公众号。`,
+}
+
+// cacheListCmd 列出缓存命令
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出缓存的图片",
+	Args:  cobra.NoArgs,
+	Run:   runCacheList,
+}
+
+// cachePruneCmd 清理缓存命令
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "删除过期或已失效的缓存",
+	Long: `删除超过有效期（--ttl，默认取配置 upload.cache_ttl）的缓存记录。
+
+--verify 逐个检查缓存的图片地址，删除已无法访问的记录（素材可能已在公众号
+后台删除）；网络错误不会删除记录。`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if flagCacheTTL < 0 {
+			return fmt.Errorf("--ttl 不能为负数")
+		}
+		return nil
+	},
+	Run: runCachePrune,
+}
+
+// cacheClearCmd 清空缓存命令
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "清空缓存",
+	Args:  cobra.NoArgs,
+	Run:   runCacheClear,
+}
+
+var (
+	flagCacheAll    bool
+	flagCacheTTL    time.Duration
+	flagCacheVerify bool
+)
+
+func init() {
+	CacheCmd.AddCommand(cacheListCmd)
+	CacheCmd.AddCommand(cachePruneCmd)
+	CacheCmd.AddCommand(cacheClearCmd)
+	CacheCmd.PersistentFlags().BoolVar(&flagCacheAll, "all", false, "操作全部公众号的缓存")
+
+	cachePruneCmd.Flags().DurationVar(&flagCacheTTL, "ttl", 0, "删除早于该时长的记录，如 720h（默认取配置 upload.cache_ttl）")
+	cachePruneCmd.Flags().BoolVar(&flagCacheVerify, "verify", false, "检查图片地址，删除已无法访问的记录")
+}
+
+// cacheAccount 返回要操作的公众号，--all 时为空（全部）
+func cacheAccount() (string, error) {
+	if flagCacheAll {
+		return "", nil
+	}
+	if cfg.WechatAppID == "" {
+		return "", fmt.Errorf("wechat_appid 未配置，使用 --all 操作全部公众号的缓存")
+	}
+	return cfg.WechatAppID, nil
+}
+
+func runCacheList(cmd *cobra.Command, args []string) {
+	account, err := cacheAccount()
+	if err != nil {
+		output.Error(err)
+	}
+	cache := imageCacheStore()
+	entries, err := cache.List(account)
+	if err != nil {
+		output.Error(err)
+	}
+	ttl, err := cfg.ImageCacheTTL()
+	if err != nil {
+		output.Error(err)
+	}
+
+	expired := 0
+	now := time.Now()
+	for _, e := range entries {
+		if e.Expired(ttl, now) {
+			expired++
+		}
+	}
+	if entries == nil {
+		entries = []imgcache.Entry{}
+	}
+	output.Success(map[string]interface{}{
+		"path":    cache.Path(),
+		"entries": entries,
+		"count":   len(entries),
+		"expired": expired,
+	})
+}
+
+func runCachePrune(cmd *cobra.Command, args []string) {
+	account, err := cacheAccount()
+	if err != nil {
+		output.Error(err)
+	}
+	ttl := flagCacheTTL
+	if !cmd.Flags().Changed("ttl") {
+		if ttl, err = cfg.ImageCacheTTL(); err != nil {
+			output.Error(err)
+		}
+	}
+	if ttl == 0 && !flagCacheVerify {
+		output.Error(fmt.Errorf("未设置有效期，请指定 --ttl 或 --verify（或配置 upload.cache_ttl）"))
+	}
+
+	cache := imageCacheStore()
+	removed, err := cache.Prune(account, ttl)
+	if err != nil {
+		output.Error(err)
+	}
+
+	unverified := 0
+	if flagCacheVerify {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		entries, err := cache.List(account)
+		if err != nil {
+			output.Error(err)
+		}
+		gone := make(map[string]bool)
+		for _, e := range entries {
+			vctx, vcancel := context.WithTimeout(ctx, verifyTimeout)
+			err := imgcache.Verify(vctx, http.DefaultClient, e.URL)
+			vcancel()
+			switch {
+			case errors.Is(err, imgcache.ErrUnavailable):
+				gone[e.Account+"\x00"+e.Key] = true
+			case err != nil:
+				if ctx.Err() != nil {
+					exitOnRequestError(ctx.Err())
+				}
+				unverified++
+			}
+		}
+		invalid, err := cache.Remove(account, func(e imgcache.Entry) bool { return gone[e.Account+"\x00"+e.Key] })
+		if err != nil {
+			output.Error(err)
+		}
+		removed = append(removed, invalid...)
+	}
+
+	if removed == nil {
+		removed = []imgcache.Entry{}
+	}
+	result := map[string]interface{}{
+		"removed":       removed,
+		"removed_count": len(removed),
+	}
+	if unverified > 0 {
+		// 网络错误无法确认的记录保留
+		result["unverified"] = unverified
+	}
+	output.Success(result)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) {
+	account, err := cacheAccount()
+	if err != nil {
+		output.Error(err)
+	}
+	removed, err := imageCacheStore().Remove(account, func(imgcache.Entry) bool { return true })
+	if err != nil {
+		output.Error(err)
+	}
+	output.Success(map[string]interface{}{
+		"removed_count": len(removed),
+	})
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// configEditCmd 编辑配置文件命令
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "用编辑器修改配置文件",
+	Long: `用 $VISUAL 或 $EDITOR（默认 vi，Windows 为 notepad）打开配置文件的临时副本，
+保存退出后校验内容：有问题时列出行号并询问是否重新编辑，校验通过才写回配置文件。
+旧版 key=value 格式会先转换为 YAML 再打开。`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runConfigEdit(); err != nil {
+			output.Error(err)
+		}
+	},
+}
+
+// runConfigEdit 编辑、校验并保存配置，校验失败时可重新编辑
+func runConfigEdit() error {
+	original, err := config.EditableContent()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "md2wx-config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	path := tmp.Name()
+	defer os.Remove(path)
+	_, err = tmp.Write(original)
+	tmp.Close()
+	if err != nil {
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	for {
+		if err := openEditor(path); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("读取临时文件失败: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			output.PrintSuccess("配置未修改")
+			return nil
+		}
+
+		err = config.ApplyEdit(edited)
+		var verr *config.ValidationError
+		if !errors.As(err, &verr) {
+			if err != nil {
+				return err
+			}
+			output.PrintSuccess("✓ 配置已保存: %s", config.GetConfigPath())
+			return nil
+		}
+
+		fmt.Fprintln(os.Stderr, verr.Error())
+		fmt.Fprint(os.Stderr, "重新编辑？[Y/n] ")
+		answer, _ := stdin.ReadString('\n')
+		if answer == "" || strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "n") {
+			return verr
+		}
+	}
+}
+
+// openEditor 打开编辑器并等待退出，编辑器命令可以带参数（如 "code -w"）
+func openEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("运行编辑器 %s 失败: %w", editor, err)
+	}
+	return nil
+}
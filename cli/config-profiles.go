@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// configProfilesCmd 配置档案命令
+var configProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "管理配置档案（多个公众号）",
+	Long: `管理配置档案。每个档案可以单独设置 wechat-appid、wechat-appsecret、api-key
+以及默认的 default-theme、font-size、background-type，未设置的项沿用顶层配置（default 档案）。
+
+  md2wx config set --profile brand-b wechat-appid wx_xxx
+  md2wx --profile brand-b article-draft --file article.md
+  MD2WX_PROFILE=brand-b md2wx article-draft --file article.md
+  md2wx config profiles use brand-b
+
+档案选择优先级: --profile > MD2WX_PROFILE > current_profile（profiles use 设置）> default`,
+}
+
+// configProfilesListCmd 列出配置档案命令
+var configProfilesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出配置档案",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles, err := config.ListProfiles()
+		if err != nil {
+			output.Error(err)
+		}
+
+		output.PrintSuccess("配置档案:")
+		for _, p := range profiles {
+			mark := " "
+			if p.Active {
+				mark = "*"
+			}
+			line := fmt.Sprintf("%s %s", mark, p.Name)
+			if p.WechatAppID != "" {
+				line += fmt.Sprintf("  (wechat_appid: %s)", p.WechatAppID)
+			}
+			if p.Current {
+				line += "  [current]"
+			}
+			fmt.Println(line)
+		}
+		fmt.Println("\n* 为本次生效的档案，[current] 为 'config profiles use' 设置的默认档案")
+	},
+}
+
+// configProfilesUseCmd 切换默认配置档案命令
+var configProfilesUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "设置默认使用的配置档案",
+	Long:  `设置默认使用的配置档案，使用 default 恢复为顶层配置。`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.UseProfile(args[0]); err != nil {
+			output.Error(err)
+		}
+		output.PrintSuccess("✓ 默认配置档案: %s", args[0])
+	},
+}
+
+// configProfilesDeleteCmd 删除配置档案命令
+var configProfilesDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "删除配置档案",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.DeleteProfile(args[0]); err != nil {
+			output.Error(err)
+		}
+		output.PrintSuccess("✓ 配置档案已删除: %s", args[0])
+	},
+}
+
+func init() {
+	ConfigCmd.AddCommand(configProfilesCmd)
+	configProfilesCmd.AddCommand(configProfilesListCmd)
+	configProfilesCmd.AddCommand(configProfilesUseCmd)
+	configProfilesCmd.AddCommand(configProfilesDeleteCmd)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagExportFormat  string
+	flagExportSecrets string
+	flagExportOutput  string
+	flagImportReplace bool
+)
+
+// configExportCmd 导出配置命令
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出配置（JSON 或 YAML）",
+	Long: `导出用户配置（含配置档案，不含项目配置和环境变量），用于迁移到其他机器。
+
+--secrets 控制 wechat-appsecret、api-key 的导出方式：
+  mask     掩码（默认），导入时跳过
+  omit     不导出
+  include  导出明文（从密钥后端解密），请妥善保管导出文件
+
+secret_backend 是本机设置，不会导出。`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if flagExportFormat != config.FormatYAML && flagExportFormat != config.FormatJSON {
+			return fmt.Errorf("--format 只能是 yaml 或 json")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := config.Export(config.ExportOptions{Format: flagExportFormat, Secrets: flagExportSecrets})
+		if err != nil {
+			output.Error(err)
+		}
+		if flagExportOutput == "" || flagExportOutput == "-" {
+			os.Stdout.Write(data)
+			return
+		}
+		if err := os.WriteFile(flagExportOutput, data, 0600); err != nil {
+			output.Error(fmt.Errorf("写入 %s 失败: %w", flagExportOutput, err))
+		}
+		output.PrintSuccess("✓ 配置已导出: %s（secrets: %s）", flagExportOutput, flagExportSecrets)
+	},
+}
+
+// configImportCmd 导入配置命令
+var configImportCmd = &cobra.Command{
+	Use:   "import <file|->",
+	Short: "导入配置（JSON 或 YAML）",
+	Long: `导入 config export 生成的 JSON 或 YAML（也接受旧版 key=value 格式），- 表示从标准输入读取。
+
+默认合并到现有配置；--replace 替换全部配置（保留本机的 secret_backend）。
+内容校验失败时不做任何修改。掩码的密钥会被跳过，明文密钥按本机的 secret_backend 保存。`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		var data []byte
+		var err error
+		if source == "-" {
+			source = "stdin"
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(source)
+		}
+		if err != nil {
+			output.Error(fmt.Errorf("读取 %s 失败: %w", source, err))
+		}
+
+		result, err := config.Import(data, source, flagImportReplace)
+		if err != nil {
+			output.Error(err)
+		}
+		output.PrintSuccess("✓ 已导入 %d 项配置", result.Imported)
+		if len(result.Skipped) > 0 {
+			fmt.Printf("  跳过: %s\n", strings.Join(result.Skipped, ", "))
+		}
+	},
+}
+
+func init() {
+	configExportCmd.Flags().StringVar(&flagExportFormat, "format", config.FormatYAML, "导出格式: yaml, json")
+	configExportCmd.Flags().StringVar(&flagExportSecrets, "secrets", config.SecretsMask, "密钥导出方式: mask, omit, include")
+	configExportCmd.Flags().StringVarP(&flagExportOutput, "output", "o", "", "输出文件（默认标准输出）")
+
+	configImportCmd.Flags().BoolVar(&flagImportReplace, "replace", false, "替换全部配置而不是合并")
+}
@@ -12,19 +12,39 @@ import (
 var ConfigCmd = &cobra.Command{
 	Use:   "config",
 	Short: "管理配置文件",
-	Long:  `管理 md2wechat-lite 的配置文件，支持设置、获取和列出配置项。`,
+	Long: `管理 md2wechat-lite 的配置文件，支持设置、删除、获取、列出、校验、编辑、导入和导出配置项。
+
+配置文件为 YAML 格式（wechat、api、defaults、upload、retry 分组，profiles 下为配置档案），
+旧版 key=value 格式仍可读取，执行 config set 时自动迁移为 YAML 并备份为 config.yaml.bak。
+
+从工作目录向上查找的 .md2wx.yaml 为项目配置（可提交到 git），覆盖用户配置中的主题、
+作者、封面和图片上传等配置；密钥和 api.base_url 默认忽略，需在用户配置中设置
+project.allow_secrets: true。`,
 }
 
 var (
 	setKey, setValue string
+	flagShowOrigin   bool
 )
 
 // configSetCmd 设置配置命令
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "设置配置项",
-	Long:  `设置指定配置项的值。支持: wechat-appid, wechat-appsecret, api-key, api-base`,
-	Args:  cobra.ExactArgs(2),
+	Long: `设置指定配置项的值。支持: wechat-appid, wechat-appsecret, api-key, api-base,
+default-theme, background-type, font-size, upload-local-images 以及重试策略 retry-max-attempts,
+retry-base-delay, retry-max-delay, retry-jitter, retry-statuses, retry-codes,
+retry-honor-retry-after。也可以使用 YAML 路径，如 defaults.theme、upload.local_images。
+主题名称和枚举值会在保存前校验。
+
+选择了配置档案（--profile、MD2WX_PROFILE 或 current_profile）时，账号和样式配置
+（wechat-appid、wechat-appsecret、api-key、default-theme、background-type、font-size）
+写入该档案，档案不存在时自动创建。
+
+secret-backend 设置 wechat-appsecret、api-key 的存储方式：plain（明文，默认）、
+file（~/.md2wx/secrets.enc 加密文件，口令取自 MD2WX_SECRET_PASSPHRASE，未设置时使用
+自动生成的 ~/.md2wx/secret.key）、keyring（系统密钥环）。切换时自动迁移已有的密钥。`,
+	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		key := args[0]
 		value := args[1]
@@ -33,10 +53,32 @@ var configSetCmd = &cobra.Command{
 			output.Error(err)
 		}
 
+		if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+			output.PrintSuccess("✓ 配置已保存: %s = %s（配置档案: %s）", key, maskIfSensitive(key, value), profile)
+			return
+		}
 		output.PrintSuccess("✓ 配置已保存: %s = %s", key, maskIfSensitive(key, value))
 	},
 }
 
+// configUnsetCmd 删除配置命令
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "删除配置项",
+	Long: `删除指定配置项，恢复为默认值。配置项名称与 config set 相同。
+
+选择了配置档案时删除该档案中的账号和样式配置；wechat-appsecret、api-key 同时从
+密钥后端删除。unset current-profile 恢复为 default 档案，unset secret-backend 将密钥
+迁回明文。`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.Unset(args[0]); err != nil {
+			output.Error(err)
+		}
+		output.PrintSuccess("✓ 配置已删除: %s", args[0])
+	},
+}
+
 // configGetCmd 获取配置命令
 var configGetCmd = &cobra.Command{
 	Use:   "get <key>",
@@ -59,27 +101,49 @@ var configGetCmd = &cobra.Command{
 var configListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "列出所有配置",
-	Long:  `列出所有已配置的项`,
-	Args:  cobra.NoArgs,
+	Long: `列出生效的配置项（已合并配置档案、项目配置 .md2wx.yaml 和环境变量）。
+--show-origin 同时显示每个值的来源：文件路径和行号、环境变量或 default。`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.List()
+		entries, err := config.ListEntries()
 		if err != nil {
 			output.Error(err)
 		}
 
-		if len(cfg) == 0 {
-			output.PrintSuccess("暂无配置，请使用 'config set' 命令设置配置")
-			return
-		}
-
 		output.PrintSuccess("当前配置:")
-		for key, value := range cfg {
-			fmt.Printf("  %s: %s\n", key, value)
+		for _, e := range entries {
+			if flagShowOrigin {
+				fmt.Printf("  %s: %s\t(%s)\n", e.Key, e.Value, e.Origin)
+				continue
+			}
+			fmt.Printf("  %s: %s\n", e.Key, e.Value)
 		}
 		fmt.Printf("\n配置文件: %s\n", config.GetConfigPath())
 	},
 }
 
+// configValidateCmd 校验配置文件命令
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "校验配置文件",
+	Long: `检查配置文件的语法、未知配置项、主题名称和枚举值，问题按行号列出。
+找到项目配置 .md2wx.yaml 时一并检查，并报告项目配置中会被忽略的配置项。
+校验失败时以 CONFIG_INVALID 错误退出。`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.Validate(); err != nil {
+			output.Error(err)
+		}
+		if err := config.ValidateProject(); err != nil {
+			output.Error(err)
+		}
+		output.PrintSuccess("✓ 配置文件有效: %s", config.GetConfigPath())
+		if path, _ := config.FindProjectFile(); path != "" {
+			output.PrintSuccess("✓ 项目配置有效: %s", path)
+		}
+	},
+}
+
 // configPathCmd 显示配置路径命令
 var configPathCmd = &cobra.Command{
 	Use:   "path",
@@ -93,9 +157,16 @@ var configPathCmd = &cobra.Command{
 
 func init() {
 	ConfigCmd.AddCommand(configSetCmd)
+	ConfigCmd.AddCommand(configUnsetCmd)
 	ConfigCmd.AddCommand(configGetCmd)
 	ConfigCmd.AddCommand(configListCmd)
 	ConfigCmd.AddCommand(configPathCmd)
+	ConfigCmd.AddCommand(configValidateCmd)
+	ConfigCmd.AddCommand(configEditCmd)
+	ConfigCmd.AddCommand(configExportCmd)
+	ConfigCmd.AddCommand(configImportCmd)
+
+	configListCmd.Flags().BoolVar(&flagShowOrigin, "show-origin", false, "显示每个配置值的来源")
 }
 
 // maskIfSensitive 如果是敏感信息则掩码
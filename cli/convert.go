@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/markdown"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// ConvertCmd 转换命令
+var ConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "转换 Markdown 为公众号 HTML（不创建草稿）",
+	Long: `将 Markdown 转换为微信公众号格式 HTML，只返回转换结果，不创建草稿。
+
+适合在发布前检查排版效果：
+  md2wx convert --file article.md --theme bytedance -o article.html
+  md2wx convert --file article.md -o -        # 直接输出 HTML 到 stdout
+  cat article.md | md2wx convert --file - -o -`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateConvertFlags()
+	},
+	Run: runConvert,
+}
+
+var (
+	flagConvertMarkdown       string
+	flagConvertFile           string
+	flagConvertTheme          string
+	flagConvertFontSize       string
+	flagConvertBackgroundType string
+	flagConvertConvertVersion string
+	flagConvertOutput         string
+)
+
+func init() {
+	ConvertCmd.Flags().StringVar(&flagConvertMarkdown, "markdown", "", "Markdown 内容")
+	ConvertCmd.Flags().StringVar(&flagConvertFile, "file", "", "Markdown 文件路径，- 表示标准输入")
+	ConvertCmd.Flags().StringVar(&flagConvertTheme, "theme", "", "主题名称（默认从配置读取）")
+	ConvertCmd.Flags().StringVar(&flagConvertFontSize, "font-size", "", "字体大小 (small/medium/large)")
+	ConvertCmd.Flags().StringVar(&flagConvertBackgroundType, "background-type", "", "背景类型 (default/grid/none)")
+	ConvertCmd.Flags().StringVar(&flagConvertConvertVersion, "convert-version", "v2", "转换版本")
+	ConvertCmd.Flags().StringVarP(&flagConvertOutput, "output", "o", "", "HTML 输出文件路径，- 表示 stdout（默认输出 JSON）")
+	addRetriesFlag(ConvertCmd)
+}
+
+func validateConvertFlags() error {
+	// 检查 Markdown 来源
+	if flagConvertMarkdown == "" && flagConvertFile == "" {
+		return fmt.Errorf("必须提供 --markdown 或 --file 参数")
+	}
+	if flagConvertMarkdown != "" && flagConvertFile != "" {
+		return fmt.Errorf("--markdown 和 --file 不能同时使用")
+	}
+
+	// 校验主题
+	if err := validateTheme(flagConvertTheme); err != nil {
+		return err
+	}
+
+	// 转换只需要 API Key，不需要微信凭证
+	return checkAPIKey()
+}
+
+func runConvert(cmd *cobra.Command, args []string) {
+	// 获取 Markdown 内容
+	content := flagConvertMarkdown
+	if flagConvertFile != "" {
+		c, err := readFileContent(flagConvertFile)
+		if err != nil {
+			output.Error(err)
+		}
+		content = c
+	}
+
+	// 拆分 front matter，补全未指定的样式参数
+	fm, body, err := markdown.SplitFrontMatter(content)
+	if err != nil {
+		output.Error(err)
+	}
+	if err := applyStyleDefaults(cfg, fm, &flagConvertTheme, &flagConvertFontSize, &flagConvertBackgroundType); err != nil {
+		output.Error(err)
+	}
+
+	// 创建 API 客户端
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+
+	// 构建请求
+	req := &api.ConvertRequest{
+		Markdown:       body,
+		Theme:          flagConvertTheme,
+		FontSize:       flagConvertFontSize,
+		BackgroundType: flagConvertBackgroundType,
+		ConvertVersion: flagConvertConvertVersion,
+	}
+
+	// 调用 API（Ctrl-C 或 --timeout 会中止请求）
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	resp, err := client.ConvertContext(ctx, req)
+	if err != nil {
+		exitOnRequestError(err)
+	}
+	if err := resp.Err(); err != nil {
+		output.Error(err)
+	}
+
+	// 输出结果
+	switch flagConvertOutput {
+	case "":
+		output.Success(map[string]interface{}{
+			"html":  resp.Data.HTML,
+			"theme": flagConvertTheme,
+		})
+	case "-":
+		fmt.Fprint(os.Stdout, resp.Data.HTML)
+	default:
+		if err := os.WriteFile(flagConvertOutput, []byte(resp.Data.HTML), 0644); err != nil {
+			output.Error(fmt.Errorf("写入文件失败: %w", err))
+		}
+		output.Success(map[string]interface{}{
+			"output": flagConvertOutput,
+			"bytes":  len(resp.Data.HTML),
+			"theme":  flagConvertTheme,
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/doctor"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/secret"
+	"github.com/spf13/cobra"
+)
+
+var flagDoctorFormat string
+
+// DoctorCmd 环境诊断命令
+var DoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "诊断配置、网络和凭据问题",
+	Long: `依次检查以下项目，给出 pass / warn / fail / skip 报告，用于定位草稿创建失败的原因：
+
+  config_file         配置文件和项目配置（.md2wx.yaml）能否加载、是否有无效配置项
+  config_complete     必填凭据是否齐全、默认主题和重试配置是否有效（与 article-draft 的校验相同）
+  config_permissions  配置文件和本地密钥文件是否只有所有者可读写
+  api_reachable       API 服务是否可达及响应耗时
+  tls                 HTTPS 证书是否有效、是否即将过期
+  clock_skew          本地时钟与服务端的偏差
+  api_key             API Key 是否有效
+  wechat_credentials  公众号 AppID / AppSecret 是否有效
+  ip_whitelist        服务出口 IP 是否在公众号白名单中
+
+凭据检查通过服务端的一次轻量请求完成（不创建草稿），失败时不重试。
+有检查项失败时退出码为 1。
+
+示例:
+  md2wx doctor
+  md2wx doctor --format text
+  md2wx --profile brand-b doctor`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if flagDoctorFormat != "json" && flagDoctorFormat != "text" {
+			return fmt.Errorf("不支持的输出格式: %s（可选: json, text）", flagDoctorFormat)
+		}
+		return nil
+	},
+	Run: runDoctor,
+}
+
+func init() {
+	DoctorCmd.Flags().StringVar(&flagDoctorFormat, "format", "json", "输出格式: json, text")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) {
+	report := doctor.NewReport()
+
+	// 配置加载失败时仍使用默认 API 地址完成网络检查
+	loaded := true
+	var err error
+	if cfg, err = config.Load(); err != nil {
+		loaded = false
+		cfg = &config.Config{APIBaseURL: config.DefaultAPIBaseURL}
+		report.Add(doctor.Check{Name: "config_file", Status: doctor.Fail, Message: fmt.Sprintf("加载配置失败: %v", err)})
+	} else {
+		report.Add(doctorConfigFile())
+	}
+	if apiKey, _ := cmd.Flags().GetString("api-key"); apiKey != "" {
+		cfg.APIKey = apiKey
+	}
+	complete := doctorConfigComplete(cmd)
+	report.Add(complete)
+	report.Add(doctorPermissions())
+
+	// 诊断请求使用默认策略，不受 retry_* 配置影响，失败时不重试
+	apiBase := cfg.APIBaseURL
+	if apiBaseFlag, _ := cmd.Flags().GetString("api-base"); apiBaseFlag != "" {
+		apiBase = apiBaseFlag
+	}
+	client := api.NewClient(apiBase, cfg.WechatAppID, cfg.WechatAppSecret, cfg.APIKey)
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	probe, probeErr := client.ProbeContext(ctx)
+	exitOnCancel(ctx)
+	report.Add(
+		doctor.Reachability(probe, probeErr),
+		doctor.TLS(probe, probeErr, time.Now()),
+		doctor.ClockSkew(probe, probeErr),
+	)
+
+	switch {
+	case !loaded || cfg.WechatAppID == "" || cfg.WechatAppSecret == "" || cfg.APIKey == "":
+		for _, name := range []string{"api_key", "wechat_credentials", "ip_whitelist"} {
+			report.Add(doctor.Check{Name: name, Status: doctor.Skip, Message: "凭据未配置，未检查"})
+		}
+	default:
+		err := client.VerifyCredentialsContext(ctx)
+		exitOnCancel(ctx)
+		report.Add(doctor.Credentials(err)...)
+	}
+
+	if flagDoctorFormat == "text" {
+		report.WriteText(os.Stdout)
+	} else {
+		output.Success(report)
+	}
+	if report.Failed() {
+		os.Exit(1)
+	}
+}
+
+// exitOnCancel 诊断过程中被取消或超时时直接退出
+func exitOnCancel(ctx context.Context) {
+	if err := ctx.Err(); err != nil {
+		exitOnRequestError(err)
+	}
+}
+
+// doctorConfigFile 检查用户配置和项目配置的内容
+func doctorConfigFile() doctor.Check {
+	path := config.GetConfigPath()
+	check := doctor.Check{Name: "config_file", Status: doctor.Pass, Message: "配置文件有效: " + path,
+		Details: map[string]any{"path": path, "profile": cfg.Profile}}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		check.Status = doctor.Warn
+		check.Message = "配置文件不存在，仅使用环境变量和默认值"
+		check.Details["hint"] = "md2wx init"
+	}
+	if cfg.ProjectPath != "" {
+		check.Details["project_config"] = cfg.ProjectPath
+	}
+
+	var problems []config.Problem
+	for _, validate := range []func() error{config.Validate, config.ValidateProject} {
+		err := validate()
+		var vErr *config.ValidationError
+		switch {
+		case errors.As(err, &vErr):
+			for _, p := range vErr.Problems {
+				p.Message = fmt.Sprintf("%s:%d: %s", vErr.Path, p.Line, p.Message)
+				problems = append(problems, p)
+			}
+		case err != nil:
+			return doctor.Check{Name: "config_file", Status: doctor.Fail, Message: err.Error()}
+		}
+	}
+	if len(problems) > 0 {
+		check.Status = doctor.Warn
+		check.Message = fmt.Sprintf("配置中有 %d 个问题（无效或被忽略的配置项）", len(problems))
+		check.Details["problems"] = problems
+		check.Details["hint"] = "md2wx config validate"
+	}
+	return check
+}
+
+// doctorConfigComplete 复用 article-draft 的校验检查必填凭据、默认主题和重试配置
+func doctorConfigComplete(cmd *cobra.Command) doctor.Check {
+	var issues []string
+	if err := checkCredentials(); err != nil {
+		// checkCredentials 只报告第一个缺失项，这里列出全部
+		for key, value := range map[string]string{"wechat_appid": cfg.WechatAppID, "wechat_appsecret": cfg.WechatAppSecret, "api_key": cfg.APIKey} {
+			if value == "" {
+				issues = append(issues, key+" 未配置")
+			}
+		}
+		sort.Strings(issues)
+	}
+	if err := validateTheme(cfg.DefaultTheme); err != nil {
+		issues = append(issues, "default_theme: "+err.Error())
+	}
+	if _, err := retryPolicy(cmd, cfg); err != nil {
+		issues = append(issues, err.Error())
+	}
+	if len(issues) > 0 {
+		return doctor.Check{Name: "config_complete", Status: doctor.Fail, Message: strings.Join(issues, "；"),
+			Details: map[string]any{"issues": issues, "hint": "md2wx init 或 md2wx config set <key> <value>"}}
+	}
+	return doctor.Check{Name: "config_complete", Status: doctor.Pass, Message: "必填配置齐全（配置档案: " + cfg.Profile + "）"}
+}
+
+// doctorPermissions 检查配置文件和本地密钥文件的权限
+func doctorPermissions() doctor.Check {
+	keyFile := os.Getenv("MD2WX_SECRET_KEY_FILE")
+	if keyFile == "" {
+		keyFile = filepath.Join(config.GetConfigDir(), secret.KeyFile)
+	}
+	return doctor.Permissions("config_permissions",
+		config.GetConfigPath(),
+		config.GetConfigPath()+".bak",
+		filepath.Join(config.GetConfigDir(), secret.StoreFile),
+		keyFile,
+	)
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/manifest"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// DraftCmd 草稿管理命令
+var DraftCmd = &cobra.Command{
+	Use:   "draft",
+	Short: "管理草稿",
+	Long:  `查看、更新和删除公众号草稿箱中的草稿。`,
+}
+
+// draftListCmd 列出草稿命令
+var draftListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出草稿",
+	Long: `分页列出草稿箱中的草稿，按更新时间倒序。
+
+默认不返回文章正文，使用 --with-content 获取完整内容；--all 自动翻页获取全部草稿。`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if flagDraftCount < 1 || flagDraftCount > api.MaxDraftPageSize {
+			return fmt.Errorf("--count 必须在 1~%d 之间", api.MaxDraftPageSize)
+		}
+		if flagDraftOffset < 0 {
+			return fmt.Errorf("--offset 不能为负数")
+		}
+		return checkCredentials()
+	},
+	Run: runDraftList,
+}
+
+// draftGetCmd 获取草稿命令
+var draftGetCmd = &cobra.Command{
+	Use:   "get <media_id>",
+	Short: "获取草稿详情",
+	Args:  cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return checkCredentials()
+	},
+	Run: runDraftGet,
+}
+
+// draftUpdateCmd 更新草稿命令
+var draftUpdateCmd = &cobra.Command{
+	Use:   "update <media_id>",
+	Short: "用 Markdown 文件替换草稿中的文章",
+	Long: `将 Markdown 文件转换后替换草稿中指定位置的文章（--index，从 0 开始）。
+
+front matter、本地图片上传和参数优先级与 article-draft 相同。`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if flagDraftFile == "" {
+			return fmt.Errorf("必须提供 --file 参数")
+		}
+		if flagDraftIndex < 0 || flagDraftIndex >= api.MaxNewsArticles {
+			return fmt.Errorf("--index 必须在 0~%d 之间", api.MaxNewsArticles-1)
+		}
+		if err := validateTheme(flagDraftTheme); err != nil {
+			return err
+		}
+		return checkCredentials()
+	},
+	Run: runDraftUpdate,
+}
+
+// draftDeleteCmd 删除草稿命令
+var draftDeleteCmd = &cobra.Command{
+	Use:   "delete <media_id>",
+	Short: "删除草稿",
+	Long:  `删除草稿箱中的草稿。删除后无法恢复，需要 --yes 确认。`,
+	Args:  cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !flagDraftYes {
+			return fmt.Errorf("删除草稿后无法恢复，请添加 --yes 确认")
+		}
+		return checkCredentials()
+	},
+	Run: runDraftDelete,
+}
+
+var (
+	flagDraftOffset      int
+	flagDraftCount       int
+	flagDraftAll         bool
+	flagDraftWithContent bool
+
+	flagDraftFile              string
+	flagDraftIndex             int
+	flagDraftTheme             string
+	flagDraftFontSize          string
+	flagDraftBackgroundType    string
+	flagDraftConvertVersion    string
+	flagDraftCoverImage        string
+	flagDraftTitle             string
+	flagDraftAuthor            string
+	flagDraftDigest            string
+	flagDraftSourceURL         string
+	flagDraftUploadLocalImages bool
+
+	flagDraftYes bool
+)
+
+func init() {
+	DraftCmd.AddCommand(draftListCmd)
+	DraftCmd.AddCommand(draftGetCmd)
+	DraftCmd.AddCommand(draftUpdateCmd)
+	DraftCmd.AddCommand(draftDeleteCmd)
+	addRetriesFlag(DraftCmd)
+
+	draftListCmd.Flags().IntVar(&flagDraftOffset, "offset", 0, "起始位置")
+	draftListCmd.Flags().IntVar(&flagDraftCount, "count", api.MaxDraftPageSize, "每页数量 (1-20)")
+	draftListCmd.Flags().BoolVar(&flagDraftAll, "all", false, "自动翻页获取全部草稿")
+	draftListCmd.Flags().BoolVar(&flagDraftWithContent, "with-content", false, "返回文章正文")
+
+	draftUpdateCmd.Flags().StringVar(&flagDraftFile, "file", "", "Markdown 文件路径，- 表示标准输入")
+	draftUpdateCmd.Flags().IntVar(&flagDraftIndex, "index", 0, "要替换的文章位置（从 0 开始）")
+	draftUpdateCmd.Flags().StringVar(&flagDraftTheme, "theme", "", "主题名称（默认从配置读取）")
+	draftUpdateCmd.Flags().StringVar(&flagDraftFontSize, "font-size", "", "字体大小 (small/medium/large)")
+	draftUpdateCmd.Flags().StringVar(&flagDraftBackgroundType, "background-type", "", "背景类型 (default/grid/none)")
+	draftUpdateCmd.Flags().StringVar(&flagDraftConvertVersion, "convert-version", "v2", "转换版本")
+	draftUpdateCmd.Flags().StringVar(&flagDraftCoverImage, "cover-image", "", "封面图片 URL 或本地路径")
+	draftUpdateCmd.Flags().StringVar(&flagDraftTitle, "title", "", "文章标题（不超过 64 字）")
+	draftUpdateCmd.Flags().StringVar(&flagDraftAuthor, "author", "", "作者（不超过 8 字）")
+	draftUpdateCmd.Flags().StringVar(&flagDraftDigest, "digest", "", "摘要（不超过 120 字）")
+	draftUpdateCmd.Flags().StringVar(&flagDraftSourceURL, "source-url", "", "阅读原文链接")
+	draftUpdateCmd.Flags().BoolVar(&flagDraftUploadLocalImages, "upload-local-images", true, "上传本地图片并替换为微信 CDN 地址（默认取配置 upload.local_images）")
+
+	draftDeleteCmd.Flags().BoolVarP(&flagDraftYes, "yes", "y", false, "确认删除")
+}
+
+func runDraftList(cmd *cobra.Command, args []string) {
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	req := &api.DraftListRequest{
+		Offset:    flagDraftOffset,
+		Count:     flagDraftCount,
+		NoContent: !flagDraftWithContent,
+	}
+
+	items := []api.Draft{}
+	total := 0
+	for {
+		resp, err := client.ListDraftsContext(ctx, req)
+		if err != nil {
+			exitOnRequestError(err)
+		}
+		if err := resp.Err(); err != nil {
+			output.Error(err)
+		}
+		items = append(items, resp.Data.Items...)
+		total = resp.Data.TotalCount
+		req.Offset += len(resp.Data.Items)
+
+		if !flagDraftAll || len(resp.Data.Items) == 0 || req.Offset >= total {
+			break
+		}
+	}
+
+	result := map[string]interface{}{
+		"items":       items,
+		"item_count":  len(items),
+		"total_count": total,
+		"offset":      flagDraftOffset,
+	}
+	// 还有下一页时给出翻页位置
+	if req.Offset < total {
+		result["next_offset"] = req.Offset
+	}
+	output.Success(result)
+}
+
+func runDraftGet(cmd *cobra.Command, args []string) {
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	resp, err := client.GetDraftContext(ctx, args[0])
+	if err != nil {
+		exitOnRequestError(err)
+	}
+	if err := resp.Err(); err != nil {
+		output.Error(err)
+	}
+	output.Success(resp.Data)
+}
+
+func runDraftUpdate(cmd *cobra.Command, args []string) {
+	mediaID := args[0]
+
+	// 合并参数并在上传图片前完成校验
+	a, err := prepareArticle(cfg, articleSource{
+		Path: flagDraftFile,
+		Overrides: manifest.Article{
+			Theme:          flagDraftTheme,
+			FontSize:       flagDraftFontSize,
+			BackgroundType: flagDraftBackgroundType,
+			Cover:          flagDraftCoverImage,
+			Title:          flagDraftTitle,
+			Author:         flagDraftAuthor,
+			Digest:         flagDraftDigest,
+			SourceURL:      flagDraftSourceURL,
+		},
+	}, flagDraftConvertVersion)
+	if err != nil {
+		output.Error(err)
+	}
+
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	var uploaded []uploadedImage
+	if uploadLocalImages(cmd, flagDraftUploadLocalImages) {
+		if uploaded, err = a.uploadImages(ctx, client, newImageCache(cfg)); err != nil {
+			exitOnRequestError(err)
+		}
+	} else if err := a.checkLocalCover(); err != nil {
+		output.Error(err)
+	}
+
+	if _, err := client.UpdateDraftContext(ctx, mediaID, &api.UpdateDraftRequest{
+		Index:   flagDraftIndex,
+		Article: *a.Request,
+	}); err != nil {
+		exitOnRequestError(err)
+	}
+
+	result := map[string]interface{}{
+		"media_id": mediaID,
+		"index":    flagDraftIndex,
+		"title":    a.Request.Title,
+	}
+	if len(uploaded) > 0 {
+		result["uploaded_images"] = uploaded
+	}
+	output.Success(result)
+}
+
+func runDraftDelete(cmd *cobra.Command, args []string) {
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	if _, err := client.DeleteDraftContext(ctx, args[0]); err != nil {
+		exitOnRequestError(err)
+	}
+	output.Success(map[string]interface{}{
+		"media_id": args[0],
+		"deleted":  true,
+	})
+}
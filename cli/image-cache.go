@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/imgcache"
+)
+
+// verifyTimeout 校验单个缓存图片地址的超时时间
+const verifyTimeout = 10 * time.Second
+
+// flagNoCache --no-cache 本次不读取也不写入图片上传缓存
+var flagNoCache bool
+
+// imageCache 按当前配置使用的图片上传缓存，nil 表示不使用缓存
+type imageCache struct {
+	cache   *imgcache.Cache
+	account string
+	ttl     time.Duration
+	verify  bool
+}
+
+// imageCacheStore 返回配置目录下的图片上传缓存
+func imageCacheStore() *imgcache.Cache {
+	return imgcache.New(filepath.Join(config.GetConfigDir(), "cache"))
+}
+
+// newImageCache 返回按配置 c 使用的图片上传缓存，记录归属于 c 的公众号
+//
+// 与上传所用的客户端使用同一份配置创建，切换配置档案时不会读写其他公众号的
+// 记录。--no-cache、upload.cache 为 false 或未配置 AppID 时返回 nil；
+// upload.cache_ttl 无效时提示并停用缓存，不影响上传。
+func newImageCache(c *config.Config) *imageCache {
+	if flagNoCache || !c.ImageCacheEnabled() || c.WechatAppID == "" {
+		return nil
+	}
+	ttl, err := c.ImageCacheTTL()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "图片上传缓存已停用: %v\n", err)
+		return nil
+	}
+	return &imageCache{
+		cache:   imageCacheStore(),
+		account: c.WechatAppID,
+		ttl:     ttl,
+		verify:  c.ImageCacheVerify(),
+	}
+}
+
+// lookup 查找缓存的上传结果
+//
+// 开启 upload.cache_verify 时先检查图片地址，地址已失效的记录会被删除。
+// 缓存读取失败只给出提示，按未命中处理。
+func (c *imageCache) lookup(ctx context.Context, key string) (imgcache.Entry, bool) {
+	if c == nil {
+		return imgcache.Entry{}, false
+	}
+	e, found, err := c.cache.Get(c.account, key, c.ttl)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "读取图片上传缓存失败，重新上传: %v\n", err)
+		return imgcache.Entry{}, false
+	}
+	if !found || !c.verify {
+		return e, found
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+	if err := imgcache.Verify(ctx, http.DefaultClient, e.URL); err != nil {
+		if errors.Is(err, imgcache.ErrUnavailable) {
+			c.cache.Delete(c.account, key)
+		}
+		return imgcache.Entry{}, false
+	}
+	return e, true
+}
+
+// store 记录上传结果，写入失败只给出提示
+func (c *imageCache) store(key, source string, result api.UploadResult) {
+	if c == nil || result.URL == "" {
+		return
+	}
+	err := c.cache.Put(imgcache.Entry{
+		Account: c.account,
+		Key:     key,
+		Source:  source,
+		URL:     result.URL,
+		MediaID: result.MediaID,
+		Size:    result.Size,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "写入图片上传缓存失败: %v\n", err)
+	}
+}
+
+// cachedResult 将缓存记录转换为上传结果
+func cachedResult(source string, e imgcache.Entry) api.UploadResult {
+	return api.UploadResult{
+		Source:  source,
+		URL:     e.URL,
+		MediaID: e.MediaID,
+		Size:    e.Size,
+		Success: true,
+		Cached:  true,
+	}
+}
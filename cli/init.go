@@ -0,0 +1,252 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/themes"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagInitAppID          string
+	flagInitAppSecret      string
+	flagInitTheme          string
+	flagInitFontSize       string
+	flagInitBackgroundType string
+	flagInitNonInteractive bool
+	flagInitSkipVerify     bool
+)
+
+var (
+	initFontSizes       = []string{"small", "medium", "large"}
+	initBackgroundTypes = []string{"none", "default", "grid"}
+)
+
+// InitCmd 初始化配置命令
+var InitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "交互式初始化配置并校验凭据",
+	Long: `引导填写微信公众号 AppID、AppSecret 和 API Key（密钥输入不回显），选择默认主题、
+字体大小和背景类型，调用一次轻量接口校验凭据后写入配置文件。
+
+已有配置时以用户配置文件中的当前值作为默认值（不含项目配置 .md2wx.yaml 和环境变量），
+直接回车保留。指定 --profile 时写入该配置档案。
+
+--non-interactive 用于自动化脚本：取值优先级为命令行参数 > 环境变量
+（MD2WX_WECHAT_APPID、MD2WX_WECHAT_APPSECRET、MD2WX_API_KEY、MD2WX_DEFAULT_THEME、
+MD2WX_FONT_SIZE、MD2WX_BACKGROUND_TYPE）> 已有配置，凭据校验失败时以错误退出。
+
+示例:
+  md2wx init
+  md2wx init --non-interactive --wechat-appid wx... --wechat-appsecret ... --api-key wme_...`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if flagInitTheme != "" {
+			if err := validateTheme(flagInitTheme); err != nil {
+				return err
+			}
+		}
+		if flagInitFontSize != "" && !slices.Contains(initFontSizes, flagInitFontSize) {
+			return fmt.Errorf("无效的字体大小: %s（可选: small, medium, large）", flagInitFontSize)
+		}
+		if flagInitBackgroundType != "" && !slices.Contains(initBackgroundTypes, flagInitBackgroundType) {
+			return fmt.Errorf("无效的背景类型: %s（可选: none, default, grid）", flagInitBackgroundType)
+		}
+		return nil
+	},
+	Run: runInit,
+}
+
+func init() {
+	InitCmd.Flags().StringVar(&flagInitAppID, "wechat-appid", "", "微信公众号 AppID")
+	InitCmd.Flags().StringVar(&flagInitAppSecret, "wechat-appsecret", "", "微信公众号 AppSecret")
+	InitCmd.Flags().StringVar(&flagInitTheme, "theme", "", "默认主题")
+	InitCmd.Flags().StringVar(&flagInitFontSize, "font-size", "", "默认字体大小 (small/medium/large)")
+	InitCmd.Flags().StringVar(&flagInitBackgroundType, "background-type", "", "默认背景类型 (none/default/grid)")
+	InitCmd.Flags().BoolVar(&flagInitNonInteractive, "non-interactive", false, "不提示输入，只使用命令行参数、环境变量和已有配置")
+	InitCmd.Flags().BoolVar(&flagInitSkipVerify, "skip-verify", false, "跳过凭据校验")
+}
+
+// initValues init 收集的配置
+type initValues struct {
+	AppID, AppSecret, APIKey        string
+	Theme, FontSize, BackgroundType string
+	APIBase                         string
+}
+
+// runInit 收集配置、校验凭据并写入配置文件
+func runInit(cmd *cobra.Command, args []string) {
+	current, profile, err := initCurrentConfig(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+
+	// 环境变量只在 --non-interactive 时使用
+	env := func(name string) string {
+		if !flagInitNonInteractive {
+			return ""
+		}
+		return os.Getenv(name)
+	}
+	apiKeyFlag, _ := cmd.Flags().GetString("api-key")
+	apiBase, _ := cmd.Flags().GetString("api-base")
+	v := initValues{
+		AppID:          firstNonEmpty(flagInitAppID, env("MD2WX_WECHAT_APPID"), current.WechatAppID),
+		AppSecret:      firstNonEmpty(flagInitAppSecret, env("MD2WX_WECHAT_APPSECRET"), current.WechatAppSecret),
+		APIKey:         firstNonEmpty(apiKeyFlag, env("MD2WX_API_KEY"), current.APIKey),
+		Theme:          firstNonEmpty(flagInitTheme, env("MD2WX_DEFAULT_THEME"), current.DefaultTheme, "default"),
+		FontSize:       firstNonEmpty(flagInitFontSize, env("MD2WX_FONT_SIZE"), current.DefaultFontSize, "medium"),
+		BackgroundType: firstNonEmpty(flagInitBackgroundType, env("MD2WX_BACKGROUND_TYPE"), current.DefaultBackgroundType, "none"),
+		APIBase:        firstNonEmpty(apiBase, current.APIBaseURL, config.DefaultAPIBaseURL),
+	}
+
+	var p *prompter
+	if !flagInitNonInteractive {
+		p = newPrompter()
+		fmt.Fprintf(p.out, "md2wx 初始化（配置档案: %s，配置文件: %s）\n\n", profile, config.GetConfigPath())
+		if err := promptInitValues(cmd, p, &v); err != nil {
+			output.Error(err)
+		}
+	}
+
+	var missing []string
+	for _, f := range [][2]string{{"wechat-appid", v.AppID}, {"wechat-appsecret", v.AppSecret}, {"api-key", v.APIKey}} {
+		if f[1] == "" {
+			missing = append(missing, f[0])
+		}
+	}
+	if len(missing) > 0 {
+		output.Error(fmt.Errorf("缺少必填配置: %s（通过参数或环境变量提供）", strings.Join(missing, ", ")))
+	}
+
+	verified := false
+	if !flagInitSkipVerify {
+		if p != nil {
+			fmt.Fprint(p.out, "\n正在校验凭据...")
+		}
+		ctx, cancel := commandContext(cmd)
+		err := api.NewClient(v.APIBase, v.AppID, v.AppSecret, v.APIKey).VerifyCredentialsContext(ctx)
+		cancel()
+		switch {
+		case err == nil:
+			verified = true
+			if p != nil {
+				fmt.Fprintln(p.out, " 通过")
+			}
+		case p == nil:
+			exitOnRequestError(err)
+		default:
+			fmt.Fprintf(p.out, " 失败: %v\n", err)
+			save, err := p.confirm("仍然保存配置？", false)
+			if err != nil {
+				output.Error(err)
+			}
+			if !save {
+				exitOnRequestError(errors.New("凭据校验失败，配置未保存"))
+			}
+		}
+	}
+
+	if err := saveInitValues(cmd, v); err != nil {
+		output.Error(err)
+	}
+
+	if p == nil {
+		output.Success(map[string]any{
+			"config_path":     config.GetConfigPath(),
+			"profile":         profile,
+			"verified":        verified,
+			"wechat_appid":    maskIfSensitive("wechat_appid", v.AppID),
+			"default_theme":   v.Theme,
+			"font_size":       v.FontSize,
+			"background_type": v.BackgroundType,
+		})
+		return
+	}
+	fmt.Fprintln(p.out)
+	output.PrintSuccess("✓ 配置已保存: %s（配置档案: %s）", config.GetConfigPath(), profile)
+	fmt.Println("\n下一步: md2wx article-draft --file article.md")
+}
+
+// initCurrentConfig 加载用户配置作为默认值，--profile 指定的档案尚不存在时从空配置开始
+//
+// 不合并项目配置和环境变量，避免把它们的值写入用户配置。
+func initCurrentConfig(cmd *cobra.Command) (*config.Config, string, error) {
+	c, err := config.LoadUser()
+	if errors.Is(err, config.ErrProfileNotFound) {
+		profile, _ := cmd.Flags().GetString("profile")
+		return &config.Config{}, firstNonEmpty(profile, os.Getenv("MD2WX_PROFILE")), nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("加载配置失败: %w", err)
+	}
+	return c, c.Profile, nil
+}
+
+// promptInitValues 逐项提示输入，命令行已指定的项不再提示
+func promptInitValues(cmd *cobra.Command, p *prompter, v *initValues) error {
+	var err error
+	if !cmd.Flags().Changed("wechat-appid") {
+		if v.AppID, err = p.ask("微信公众号 AppID", v.AppID); err != nil {
+			return err
+		}
+	}
+	if !cmd.Flags().Changed("wechat-appsecret") {
+		secret, err := p.askSecret("微信公众号 AppSecret", v.AppSecret != "")
+		if err != nil {
+			return err
+		}
+		v.AppSecret = firstNonEmpty(secret, v.AppSecret)
+	}
+	if !cmd.Flags().Changed("api-key") {
+		fmt.Fprintln(p.out, "API Key 获取地址: https://www.md2wechat.cn/api-docs")
+		key, err := p.askSecret("md2wx API Key", v.APIKey != "")
+		if err != nil {
+			return err
+		}
+		v.APIKey = firstNonEmpty(key, v.APIKey)
+	}
+
+	fmt.Fprintln(p.out)
+	if !cmd.Flags().Changed("theme") {
+		describe := func(name string) string { return themes.ThemeDescriptions[name] }
+		if v.Theme, err = p.choose("默认主题（md2wx themes list 查看详情）", themes.AllThemes, describe, v.Theme); err != nil {
+			return err
+		}
+	}
+	noDesc := func(string) string { return "" }
+	if !cmd.Flags().Changed("font-size") {
+		if v.FontSize, err = p.choose("默认字体大小", initFontSizes, noDesc, v.FontSize); err != nil {
+			return err
+		}
+	}
+	if !cmd.Flags().Changed("background-type") {
+		if v.BackgroundType, err = p.choose("默认背景类型", initBackgroundTypes, noDesc, v.BackgroundType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveInitValues 一次写入配置，选择了配置档案时账号和样式配置写入该档案
+func saveInitValues(cmd *cobra.Command, v initValues) error {
+	items := [][2]string{
+		{"wechat_appid", v.AppID},
+		{"wechat_appsecret", v.AppSecret},
+		{"api_key", v.APIKey},
+		{"default_theme", v.Theme},
+		{"font_size", v.FontSize},
+		{"background_type", v.BackgroundType},
+	}
+	if cmd.Flags().Changed("api-base") {
+		items = append(items, [2]string{"api_base_url", v.APIBase})
+	}
+	return config.SetAll(items)
+}
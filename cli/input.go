@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/textenc"
+)
+
+// stdinPath 文件参数为 - 时从标准输入读取
+const stdinPath = "-"
+
+// maxInputSize Markdown / 正文输入的大小上限（微信图文正文不超过 1 MB，留出转换余量）
+const maxInputSize = 2 << 20
+
+// stdinRead 标准输入是否已被读取（只能读取一次）
+var stdinRead bool
+
+// flagInputEncoding --encoding 输入文件的编码，默认自动检测
+var flagInputEncoding string
+
+// validateInputEncoding 校验 --encoding
+func validateInputEncoding() error {
+	if !slices.Contains(textenc.Encodings, flagInputEncoding) {
+		return fmt.Errorf("无效的 --encoding: %s（可选: %s）", flagInputEncoding, strings.Join(textenc.Encodings, ", "))
+	}
+	return nil
+}
+
+// readFileContent 读取文件内容（- 表示标准输入），检测编码并转换为 UTF-8
+//
+// 支持 UTF-8（可带 BOM）、带 BOM 的 UTF-16 和 GBK（自动识别中文内容，或由
+// --encoding gbk 指定），超过 maxInputSize 或无法识别编码时报错，不会把乱码
+// 发送到服务端。
+func readFileContent(path string) (string, error) {
+	name := path
+	var r io.Reader
+	if path == stdinPath {
+		if stdinRead {
+			return "", fmt.Errorf("标准输入只能读取一次")
+		}
+		stdinRead = true
+		name, r = "标准输入", os.Stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("读取文件失败: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxInputSize+1))
+	if err != nil {
+		return "", fmt.Errorf("读取%s失败: %w", name, err)
+	}
+	if len(data) > maxInputSize {
+		return "", fmt.Errorf("%s 超过 %d MB 大小限制", name, maxInputSize>>20)
+	}
+	text, encoding, err := textenc.DecodeAs(data, flagInputEncoding)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	if encoding != textenc.UTF8 && encoding != textenc.UTF8BOM {
+		fmt.Fprintf(os.Stderr, "%s: 已从 %s 转换为 UTF-8\n", name, encoding)
+	}
+	return text, nil
+}
+
+// stdinPiped 标准输入是否为管道或重定向的文件
+//
+// 终端、/dev/null 和套接字不算，避免在交互式终端或 cron 中等待输入。
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	mode := info.Mode()
+	return mode&os.ModeNamedPipe != 0 || mode.IsRegular()
+}
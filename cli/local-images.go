@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/imgcache"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/markdown"
+)
+
+// uploadedImage 已上传的本地图片
+type uploadedImage struct {
+	Path    string `json:"path"`
+	URL     string `json:"url"`
+	MediaID string `json:"media_id,omitempty"`
+	// Cached 使用了图片上传缓存中的地址，未重复上传
+	Cached bool `json:"cached,omitempty"`
+}
+
+// rewriteLocalImages 上传 Markdown 中引用的本地图片，并替换为微信 CDN 地址
+//
+// 相对路径以 baseDir 为基准解析（通常为 Markdown 文件所在目录）。cache 为 nil 时不使用上传缓存。
+func rewriteLocalImages(ctx context.Context, client *api.Client, cache *imageCache, md, baseDir string) (string, []uploadedImage, error) {
+	refs := markdown.LocalImages(md)
+	if len(refs) == 0 {
+		return md, nil, nil
+	}
+
+	mapping := make(map[string]string, len(refs))
+	uploaded := make([]uploadedImage, 0, len(refs))
+	for _, ref := range refs {
+		img, err := uploadLocalImage(ctx, client, cache, resolveLocalPath(ref, baseDir))
+		if err != nil {
+			return "", nil, fmt.Errorf("上传本地图片 %s 失败: %w", ref, err)
+		}
+		mapping[ref] = img.URL
+		uploaded = append(uploaded, img)
+	}
+
+	return markdown.RewriteImages(md, mapping), uploaded, nil
+}
+
+// uploadLocalCover 封面图为本地路径时上传并返回 CDN 地址，远程地址原样返回
+func uploadLocalCover(ctx context.Context, client *api.Client, cache *imageCache, cover, baseDir string) (string, *uploadedImage, error) {
+	if !markdown.IsLocalPath(cover) {
+		return cover, nil, nil
+	}
+	img, err := uploadLocalImage(ctx, client, cache, resolveLocalPath(cover, baseDir))
+	if err != nil {
+		return "", nil, fmt.Errorf("上传封面图 %s 失败: %w", cover, err)
+	}
+	return img.URL, &img, nil
+}
+
+// uploadLocalImage 上传单个本地图片文件，内容相同的图片使用上传缓存中的地址
+func uploadLocalImage(ctx context.Context, client *api.Client, cache *imageCache, path string) (uploadedImage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return uploadedImage{}, fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	key := imgcache.FileKey(data)
+	if e, ok := cache.lookup(ctx, key); ok {
+		return uploadedImage{Path: path, URL: e.URL, MediaID: e.MediaID, Cached: true}, nil
+	}
+
+	resp, err := client.UploadImageContext(ctx, api.UploadFile{Name: path, Data: data})
+	if err != nil {
+		return uploadedImage{}, err
+	}
+	if err := resp.Err(); err != nil {
+		return uploadedImage{}, err
+	}
+	if resp.Data.URL == "" {
+		return uploadedImage{}, fmt.Errorf("服务端未返回图片地址")
+	}
+	if resp.Data.Size == 0 {
+		resp.Data.Size = int64(len(data))
+	}
+	cache.store(key, path, resp.Data)
+
+	return uploadedImage{
+		Path:    path,
+		URL:     resp.Data.URL,
+		MediaID: resp.Data.MediaID,
+	}, nil
+}
+
+// resolveLocalPath 将图片引用解析为文件路径，相对路径以 baseDir 为基准
+func resolveLocalPath(ref, baseDir string) string {
+	path := markdown.LocalFilePath(ref)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
@@ -1,11 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
 	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
 	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/textenc"
 	"github.com/spf13/cobra"
 )
 
@@ -17,10 +25,14 @@ var (
 )
 
 func main() {
+	// 收到 SIGINT/SIGTERM 时取消根 context，中止进行中的请求
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// 执行根命令
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		stop()
 		output.Error(err)
-		os.Exit(1)
 	}
 }
 
@@ -45,6 +57,151 @@ var rootCmd = &cobra.Command{
 
 var cfg *config.Config
 
+// commandContext 返回命令的 context，设置了 --timeout 时附加截止时间
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout, _ := cmd.Flags().GetDuration("timeout"); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// newAPIClient 根据配置和命令行参数创建 API 客户端
+//
+// --api-base、--api-key 和 --retries 优先于配置文件。
+func newAPIClient(cmd *cobra.Command) (*api.Client, error) {
+	return newConfigClient(cmd, cfg)
+}
+
+// newConfigClient 根据配置 c 和命令行参数创建 API 客户端
+func newConfigClient(cmd *cobra.Command, c *config.Config) (*api.Client, error) {
+	// 获取 API Base URL（命令行参数优先）
+	apiBase := c.APIBaseURL
+	if apiBaseFlag, _ := cmd.Flags().GetString("api-base"); apiBaseFlag != "" {
+		apiBase = apiBaseFlag
+	}
+
+	// 获取 API Key（命令行参数优先）
+	apiKey := c.APIKey
+	if apiKeyFlag, _ := cmd.Flags().GetString("api-key"); apiKeyFlag != "" {
+		apiKey = apiKeyFlag
+	}
+
+	policy, err := retryPolicy(cmd, c)
+	if err != nil {
+		return nil, err
+	}
+
+	client := api.NewClient(apiBase, c.WechatAppID, c.WechatAppSecret, apiKey)
+	client.SetRetryPolicy(policy)
+	return client, nil
+}
+
+// uploadLocalImages 返回是否上传本地图片，未指定 --upload-local-images 时取配置 upload.local_images
+func uploadLocalImages(cmd *cobra.Command, flag bool) bool {
+	if cmd.Flags().Changed("upload-local-images") {
+		return flag
+	}
+	return cfg.UploadImages()
+}
+
+// addRetriesFlag 为会发送 API 请求的命令注册 --retries（对子命令同样生效），
+// 由 retryPolicy 读取
+func addRetriesFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().Int("retries", 0, "失败重试次数（默认读取配置 retry_max_attempts；创建草稿、上传和发布只在请求未发出或返回 429/503 时重试）")
+}
+
+// retryPolicy 由配置 c 的 retry_* 项和 --retries 参数构建重试策略
+func retryPolicy(cmd *cobra.Command, c *config.Config) (api.RetryPolicy, error) {
+	policy := api.DefaultRetryPolicy()
+
+	if c.RetryMaxAttempts != "" {
+		n, err := strconv.Atoi(c.RetryMaxAttempts)
+		if err != nil {
+			return policy, fmt.Errorf("retry_max_attempts 无效: %s", c.RetryMaxAttempts)
+		}
+		policy.MaxAttempts = n
+	}
+	if c.RetryBaseDelay != "" {
+		d, err := time.ParseDuration(c.RetryBaseDelay)
+		if err != nil {
+			return policy, fmt.Errorf("retry_base_delay 无效: %s", c.RetryBaseDelay)
+		}
+		policy.BaseDelay = d
+	}
+	if c.RetryMaxDelay != "" {
+		d, err := time.ParseDuration(c.RetryMaxDelay)
+		if err != nil {
+			return policy, fmt.Errorf("retry_max_delay 无效: %s", c.RetryMaxDelay)
+		}
+		policy.MaxDelay = d
+	}
+	if c.RetryJitter != "" {
+		f, err := strconv.ParseFloat(c.RetryJitter, 64)
+		if err != nil {
+			return policy, fmt.Errorf("retry_jitter 无效: %s", c.RetryJitter)
+		}
+		policy.Jitter = f
+	}
+	if c.RetryStatuses != "" {
+		statuses, err := config.ParseIntList(c.RetryStatuses)
+		if err != nil {
+			return policy, fmt.Errorf("retry_statuses 无效: %s", c.RetryStatuses)
+		}
+		policy.RetryableStatuses = statuses
+	}
+	if c.RetryCodes != "" {
+		codes, err := config.ParseIntList(c.RetryCodes)
+		if err != nil {
+			return policy, fmt.Errorf("retry_codes 无效: %s", c.RetryCodes)
+		}
+		policy.RetryableCodes = codes
+	}
+	if c.RetryHonorRetryAfter != "" {
+		b, err := strconv.ParseBool(c.RetryHonorRetryAfter)
+		if err != nil {
+			return policy, fmt.Errorf("retry_honor_retry_after 无效: %s", c.RetryHonorRetryAfter)
+		}
+		policy.HonorRetryAfter = b
+	}
+
+	// --retries 指定重试次数（不含首次请求）
+	if cmd.Flags().Changed("retries") {
+		retries, _ := cmd.Flags().GetInt("retries")
+		if retries < 0 {
+			return policy, fmt.Errorf("--retries 不能为负数")
+		}
+		policy.MaxAttempts = retries + 1
+	}
+
+	return policy, nil
+}
+
+// exitOnRequestError 输出请求错误并退出，取消和超时输出结构化错误码
+func exitOnRequestError(err error) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		output.ErrorWithCode("CANCELLED", "操作已取消")
+	case errors.Is(err, context.DeadlineExceeded):
+		output.ErrorWithCode("TIMEOUT", "请求超时")
+	default:
+		output.Error(err)
+	}
+}
+
+// isConfigCommand 判断是否为 config 命令或其子命令
+func isConfigCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == ConfigCmd {
+			return true
+		}
+	}
+	return false
+}
+
 // 执行命令前的初始化
 func initConfig(cmd *cobra.Command, args []string) error {
 	var err error
@@ -60,21 +217,44 @@ func init() {
 	rootCmd.Version = fmt.Sprintf("%s (构建时间: %s, 提交: %s)", version, buildDate, gitCommit)
 
 	// 添加子命令
+	rootCmd.AddCommand(InitCmd)
 	rootCmd.AddCommand(ConfigCmd)
+	rootCmd.AddCommand(ConvertCmd)
+	rootCmd.AddCommand(PreviewCmd)
 	rootCmd.AddCommand(ArticleDraftCmd)
 	rootCmd.AddCommand(NewspicDraftCmd)
+	rootCmd.AddCommand(DraftCmd)
+	rootCmd.AddCommand(PublishCmd)
+	rootCmd.AddCommand(ScheduleCmd)
+	rootCmd.AddCommand(SchedulerCmd)
 	rootCmd.AddCommand(BatchUploadCmd)
 	rootCmd.AddCommand(ThemesCmd)
+	rootCmd.AddCommand(DoctorCmd)
+	rootCmd.AddCommand(CacheCmd)
 
 	// 持久化标志
 	rootCmd.PersistentFlags().StringP("api-base", "a", "", "API 基础 URL (覆盖配置文件)")
 	rootCmd.PersistentFlags().StringP("api-key", "k", "", "API Key (覆盖配置文件)")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "详细输出")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "整个命令的超时时间，如 30s、2m（默认不限制）")
+	rootCmd.PersistentFlags().String("profile", "", "使用的配置档案（覆盖 MD2WX_PROFILE 和 current_profile）")
+	rootCmd.PersistentFlags().BoolVar(&flagNoCache, "no-cache", false, "不读取也不写入图片上传缓存")
+	rootCmd.PersistentFlags().StringVar(&flagInputEncoding, "encoding", textenc.Auto, "输入文件编码 (auto/utf-8/gbk)，auto 只在内容为中文时识别 GBK")
 
 	// 绑定持久化标志到配置
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := validateInputEncoding(); err != nil {
+			return err
+		}
+		// --profile 同时作用于 config 子命令
+		if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+			config.SetProfile(profile)
+		}
+
 		// 某些命令不需要配置（如 help, version, config set, themes list）
-		skipConfig := cmd.Name() == "help" || cmd.Name() == "config" || cmd.Name() == "themes"
+		// config 子命令和 init 直接读写配置文件，不依赖已加载的配置（档案可能尚未创建）
+		// doctor 自行加载配置，加载失败也作为诊断结果输出
+		skipConfig := cmd.Name() == "help" || cmd.Name() == "themes" || isConfigCommand(cmd) || cmd == InitCmd || cmd == DoctorCmd
 		if !skipConfig {
 			if err := initConfig(cmd, args); err != nil {
 				return err
@@ -10,11 +10,25 @@ import (
 
 // NewspicDraftCmd 小绿书草稿命令
 var NewspicDraftCmd = &cobra.Command{
-	Use:   "newspic-draft",
+	Use:   "newspic-draft [content-file]",
 	Short: "创建小绿书草稿",
-	Long:  `创建微信公众号小绿书（图片文章）草稿`,
-	Args:  cobra.NoArgs,
+	Long: `创建微信公众号小绿书（图片文章）草稿
+
+正文文件可以用 --content-file 或位置参数指定，- 表示标准输入；未指定 --content
+和正文文件且标准输入是管道或重定向文件时，从标准输入读取正文。文件支持 UTF-8
+（可带 BOM）、带 BOM 的 UTF-16 和 GBK 编码，不超过 2 MB。
+
+示例:
+  md2wx newspic-draft --title "标题" --images "https://.../a.jpg" note.txt
+  echo "正文" | md2wx newspic-draft --title "标题" --images "https://.../a.jpg"`,
+	Args: cobra.MaximumNArgs(1),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			if flagContentFile != "" {
+				return fmt.Errorf("--content-file 和文件参数不能同时使用")
+			}
+			flagContentFile = args[0]
+		}
 		return validateNewspicDraftFlags()
 	},
 	Run: runNewspicDraft,
@@ -31,7 +45,8 @@ func init() {
 	NewspicDraftCmd.Flags().StringVar(&flagTitle, "title", "", "文章标题")
 	NewspicDraftCmd.Flags().StringVar(&flagContent, "content", "", "正文内容")
 	NewspicDraftCmd.Flags().StringVar(&flagImages, "images", "", "图片 URL，多个用逗号分隔")
-	NewspicDraftCmd.Flags().StringVar(&flagContentFile, "content-file", "", "正文内容文件路径")
+	NewspicDraftCmd.Flags().StringVar(&flagContentFile, "content-file", "", "正文内容文件路径，- 表示标准输入")
+	addRetriesFlag(NewspicDraftCmd)
 }
 
 func validateNewspicDraftFlags() error {
@@ -39,8 +54,12 @@ func validateNewspicDraftFlags() error {
 		return fmt.Errorf("必须提供 --title 参数")
 	}
 
+	// 未指定正文时读取管道输入
+	if flagContent == "" && flagContentFile == "" && stdinPiped() {
+		flagContentFile = stdinPath
+	}
 	if flagContent == "" && flagContentFile == "" {
-		return fmt.Errorf("必须提供 --content 或 --content-file 参数")
+		return fmt.Errorf("必须提供 --content、--content-file 参数、文件参数或管道输入")
 	}
 
 	if flagContent != "" && flagContentFile != "" {
@@ -52,17 +71,7 @@ func validateNewspicDraftFlags() error {
 	}
 
 	// 检查配置
-	if cfg.WechatAppID == "" {
-		return fmt.Errorf("wechat_appid 未配置，请使用 'config set wechat-appid' 设置")
-	}
-	if cfg.WechatAppSecret == "" {
-		return fmt.Errorf("wechat_appsecret 未配置，请使用 'config set wechat-appsecret' 设置")
-	}
-	if cfg.APIKey == "" {
-		return fmt.Errorf("api_key 未配置，请使用 'config set api-key' 设置")
-	}
-
-	return nil
+	return checkCredentials()
 }
 
 func runNewspicDraft(cmd *cobra.Command, args []string) {
@@ -83,20 +92,11 @@ func runNewspicDraft(cmd *cobra.Command, args []string) {
 		output.Error(fmt.Errorf("至少需要一张图片"))
 	}
 
-	// 获取 API Base URL（命令行参数优先）
-	apiBase := cfg.APIBaseURL
-	if apiBaseFlag, _ := cmd.Parent().PersistentFlags().GetString("api-base"); apiBaseFlag != "" {
-		apiBase = apiBaseFlag
-	}
-
-	// 获取 API Key（命令行参数优先）
-	apiKey := cfg.APIKey
-	if apiKeyFlag, _ := cmd.Parent().PersistentFlags().GetString("api-key"); apiKeyFlag != "" {
-		apiKey = apiKeyFlag
-	}
-
 	// 创建 API 客户端
-	client := api.NewClient(apiBase, cfg.WechatAppID, cfg.WechatAppSecret, apiKey)
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
 
 	// 构建请求
 	req := &api.NewspicDraftRequest{
@@ -105,10 +105,12 @@ func runNewspicDraft(cmd *cobra.Command, args []string) {
 		ImageUrls: imageUrls,
 	}
 
-	// 调用 API
-	resp, err := client.NewspicDraft(req)
+	// 调用 API（Ctrl-C 或 --timeout 会中止请求）
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	resp, err := client.NewspicDraftContext(ctx, req)
 	if err != nil {
-		output.Error(err)
+		exitOnRequestError(err)
 	}
 
 	// 输出结果
@@ -118,6 +120,6 @@ func runNewspicDraft(cmd *cobra.Command, args []string) {
 			"published": resp.Data.Published,
 		})
 	} else {
-		output.ErrorWithCode(fmt.Sprintf("API_ERROR_%d", resp.Code), resp.Msg)
+		output.Error(resp.Err())
 	}
 }
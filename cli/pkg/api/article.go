@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 微信图文消息字段长度限制（按字符计）
+const (
+	MaxTitleLength  = 64
+	MaxAuthorLength = 8
+	MaxDigestLength = 120
+)
+
+// MaxNewsArticles 一个草稿最多包含的文章数
+const MaxNewsArticles = 8
+
+// NewsDraftRequest 多图文草稿请求，文章按顺序展示，第一篇为头条
+type NewsDraftRequest struct {
+	Articles []ArticleDraftRequest `json:"articles"`
+}
+
+// NewsDraftResponse 多图文草稿响应
+type NewsDraftResponse struct {
+	ResponseStatus
+	Data struct {
+		DraftID   string `json:"draft_id,omitempty"`
+		MediaID   string `json:"media_id,omitempty"`
+		Count     int    `json:"count,omitempty"`
+		Published bool   `json:"published,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// Validate 检查文章数量并逐篇校验
+func (r *NewsDraftRequest) Validate() error {
+	if len(r.Articles) == 0 {
+		return fmt.Errorf("至少需要一篇文章")
+	}
+	if len(r.Articles) > MaxNewsArticles {
+		return fmt.Errorf("文章数量 %d 超过微信限制 %d", len(r.Articles), MaxNewsArticles)
+	}
+	for i := range r.Articles {
+		if err := r.Articles[i].Validate(); err != nil {
+			return fmt.Errorf("第 %d 篇文章: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// NewsDraft 创建包含多篇文章的图文草稿
+func (c *Client) NewsDraft(req *NewsDraftRequest) (*NewsDraftResponse, error) {
+	return c.NewsDraftContext(context.Background(), req)
+}
+
+// NewsDraftContext 创建多图文草稿，请求随 ctx 取消或超时而中止
+func (c *Client) NewsDraftContext(ctx context.Context, req *NewsDraftRequest) (*NewsDraftResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	endpoint := "/api/v1/news-draft"
+	var resp NewsDraftResponse
+	if err := c.doNonIdempotent(ctx, "POST", endpoint, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Validate 按微信接口限制检查请求字段，在发送请求前调用
+func (r *ArticleDraftRequest) Validate() error {
+	if strings.TrimSpace(r.Markdown) == "" {
+		return fmt.Errorf("markdown 内容不能为空")
+	}
+	if err := checkLength("标题", r.Title, MaxTitleLength); err != nil {
+		return err
+	}
+	if err := checkLength("作者", r.Author, MaxAuthorLength); err != nil {
+		return err
+	}
+	if err := checkLength("摘要", r.Digest, MaxDigestLength); err != nil {
+		return err
+	}
+	if r.OnlyFansCanComment && !r.NeedOpenComment {
+		return fmt.Errorf("仅粉丝可评论需要同时打开评论")
+	}
+	for name, crop := range map[string]string{"2.35:1": r.PicCrop235_1, "1:1": r.PicCrop1_1} {
+		if crop == "" {
+			continue
+		}
+		if _, err := NormalizeCrop(crop); err != nil {
+			return fmt.Errorf("封面裁剪坐标 (%s) %w", name, err)
+		}
+	}
+	return nil
+}
+
+// checkLength 检查字段字符数是否超过限制
+func checkLength(name, value string, limit int) error {
+	if n := utf8.RuneCountInString(value); n > limit {
+		return fmt.Errorf("%s长度为 %d 个字符，超过微信限制 %d", name, n, limit)
+	}
+	return nil
+}
+
+// NormalizeCrop 校验封面裁剪坐标并转换为微信格式 X1_Y1_X2_Y2
+//
+// 输入可用逗号或下划线分隔，坐标为 0~1 的相对值，且左上角必须在右下角之前，
+// 例如 "0.1,0,0.9,1"。
+func NormalizeCrop(s string) (string, error) {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '_' })
+	if len(parts) != 4 {
+		return "", fmt.Errorf("格式错误: %q，应为 x1,y1,x2,y2", s)
+	}
+
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || f < 0 || f > 1 {
+			return "", fmt.Errorf("坐标必须是 0~1 之间的数字: %q", p)
+		}
+		v[i] = f
+	}
+	if v[0] >= v[2] || v[1] >= v[3] {
+		return "", fmt.Errorf("左上角坐标必须小于右下角坐标: %q", s)
+	}
+
+	formatted := make([]string, 4)
+	for i, f := range v {
+		formatted[i] = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return strings.Join(formatted, "_"), nil
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestArticleDraftRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ArticleDraftRequest
+		wantErr string
+	}{
+		{"valid", ArticleDraftRequest{Markdown: "# a", Title: strings.Repeat("标", 64), Author: "八个字的作者名字", Digest: strings.Repeat("摘", 120)}, ""},
+		{"empty markdown", ArticleDraftRequest{Markdown: "  "}, "markdown"},
+		{"title too long", ArticleDraftRequest{Markdown: "# a", Title: strings.Repeat("标", 65)}, "标题"},
+		{"author too long", ArticleDraftRequest{Markdown: "# a", Author: "九个字的作者名字啊"}, "作者"},
+		{"digest too long", ArticleDraftRequest{Markdown: "# a", Digest: strings.Repeat("a", 121)}, "摘要"},
+		{"fans only without comment", ArticleDraftRequest{Markdown: "# a", OnlyFansCanComment: true}, "评论"},
+		{"bad crop", ArticleDraftRequest{Markdown: "# a", PicCrop1_1: "0.5_0_0.2_1"}, "1:1"},
+		{"valid crop", ArticleDraftRequest{Markdown: "# a", PicCrop235_1: "0_0.1_1_0.9", NeedOpenComment: true, OnlyFansCanComment: true}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeCrop(t *testing.T) {
+	tests := map[string]string{
+		"0.1,0,0.9,1":      "0.1_0_0.9_1",
+		"0_0_1_1":          "0_0_1_1",
+		" 0.25, 0, 0.75,1": "0.25_0_0.75_1",
+	}
+	for in, want := range tests {
+		got, err := NormalizeCrop(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeCrop(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "0,0,1", "0,0,1,2", "a,0,1,1", "0.5,0,0.5,1"} {
+		if _, err := NormalizeCrop(in); err == nil {
+			t.Errorf("NormalizeCrop(%q) should fail", in)
+		}
+	}
+}
+
+func TestArticleDraft_ValidatesBeforeRequest(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-app-id", "test-app-secret", "test-api-key")
+	_, err := client.ArticleDraft(&ArticleDraftRequest{Markdown: "# a", Title: strings.Repeat("t", 65)})
+	if err == nil {
+		t.Fatal("ArticleDraft() should fail validation")
+	}
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Errorf("server called %d times, want 0", calls)
+	}
+}
+
+func TestNewsDraft_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/news-draft" {
+			t.Errorf("Path = %s, want /api/v1/news-draft", r.URL.Path)
+		}
+		var req NewsDraftRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if len(req.Articles) != 2 || req.Articles[1].Title != "第二篇" {
+			t.Errorf("Articles = %+v", req.Articles)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"code": 0,
+			"msg":  "success",
+			"data": map[string]any{"draft_id": "d1", "media_id": "m1", "count": 2},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-app-id", "test-app-secret", "test-api-key")
+	resp, err := client.NewsDraft(&NewsDraftRequest{Articles: []ArticleDraftRequest{
+		{Markdown: "# 第一篇", Title: "第一篇"},
+		{Markdown: "# 第二篇", Title: "第二篇"},
+	}})
+	if err != nil {
+		t.Fatalf("NewsDraft() failed: %v", err)
+	}
+	if resp.Data.MediaID != "m1" || resp.Data.Count != 2 {
+		t.Errorf("Data = %+v", resp.Data)
+	}
+}
+
+func TestNewsDraftRequest_Validate(t *testing.T) {
+	article := ArticleDraftRequest{Markdown: "# a"}
+
+	if err := (&NewsDraftRequest{}).Validate(); err == nil {
+		t.Error("empty request should fail")
+	}
+
+	tooMany := &NewsDraftRequest{}
+	for i := 0; i <= MaxNewsArticles; i++ {
+		tooMany.Articles = append(tooMany.Articles, article)
+	}
+	if err := tooMany.Validate(); err == nil {
+		t.Error("more than 8 articles should fail")
+	}
+
+	bad := &NewsDraftRequest{Articles: []ArticleDraftRequest{article, {Markdown: "# b", Author: "一二三四五六七八九"}}}
+	if err := bad.Validate(); err == nil || !strings.Contains(err.Error(), "第 2 篇") {
+		t.Errorf("Validate() error = %v, want article index", err)
+	}
+}
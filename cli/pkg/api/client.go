@@ -1,22 +1,33 @@
 // Package api 提供 md2wechat API 服务的 HTTP 客户端。
 //
-// 客户端支持三种主要操作：
+// 客户端支持以下操作：
+//   - 转换 Markdown 为 HTML，不创建草稿 (Convert)
 //   - 创建图文草稿 (ArticleDraft)
+//   - 创建多图文草稿 (NewsDraft)
+//   - 管理草稿 (ListDrafts / GetDraft / UpdateDraft / DeleteDraft)
+//   - 发布草稿并查询发布状态 (Publish / GetPublishStatus / WaitPublishContext)
 //   - 创建小绿书草稿 (NewspicDraft)
 //   - 批量上传素材 (BatchUpload)
+//   - 上传本地图片 (UploadImage)
+//
+// 每个操作都有对应的 Context 版本（如 ArticleDraftContext），
+// 可通过 context 取消进行中的请求或设置截止时间。
 //
 // 使用方法：
 //
 //	client := api.NewClient(baseURL, appID, appSecret, apiKey)
-//	resp, err := client.ArticleDraft(req)
+//	resp, err := client.ArticleDraftContext(ctx, req)
 package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
 	"time"
 )
 
@@ -28,6 +39,8 @@ type Client struct {
 	apiKey          string
 	httpClient      *http.Client
 	timeout         time.Duration
+	retry           RetryPolicy
+	sleep           func(ctx context.Context, d time.Duration) error
 }
 
 // NewClient 创建 API 客户端
@@ -41,6 +54,8 @@ func NewClient(baseURL, wechatAppID, wechatAppSecret, apiKey string) *Client {
 			Timeout: 30 * time.Second,
 		},
 		timeout: 30 * time.Second,
+		retry:   DefaultRetryPolicy(),
+		sleep:   sleepContext,
 	}
 }
 
@@ -52,6 +67,29 @@ type ArticleDraftRequest struct {
 	BackgroundType string `json:"backgroundType,omitempty"`
 	ConvertVersion string `json:"convertVersion,omitempty"`
 	CoverImageUrl  string `json:"coverImageUrl,omitempty"`
+
+	// 文章元数据，未设置时由服务端决定（通常取正文一级标题）
+	Title            string `json:"title,omitempty"`
+	Author           string `json:"author,omitempty"`
+	Digest           string `json:"digest,omitempty"`
+	ContentSourceUrl string `json:"contentSourceUrl,omitempty"`
+
+	// 评论设置
+	NeedOpenComment    bool `json:"needOpenComment,omitempty"`
+	OnlyFansCanComment bool `json:"onlyFansCanComment,omitempty"`
+
+	// 封面裁剪坐标，格式为 X1_Y1_X2_Y2（0~1 的相对坐标），见 NormalizeCrop
+	PicCrop235_1 string `json:"picCrop235_1,omitempty"`
+	PicCrop1_1   string `json:"picCrop1_1,omitempty"`
+}
+
+// ConvertRequest Markdown 转换请求
+type ConvertRequest struct {
+	Markdown       string `json:"markdown"`
+	Theme          string `json:"theme,omitempty"`
+	FontSize       string `json:"fontSize,omitempty"`
+	BackgroundType string `json:"backgroundType,omitempty"`
+	ConvertVersion string `json:"convertVersion,omitempty"`
 }
 
 // NewspicDraftRequest 小绿书草稿请求
@@ -68,15 +106,13 @@ type BatchUploadRequest struct {
 
 // APIResponse 通用 API 响应
 type APIResponse struct {
-	Code int    `json:"code"`
-	Msg  string `json:"msg"`
-	Data any    `json:"data,omitempty"`
+	ResponseStatus
+	Data any `json:"data,omitempty"`
 }
 
 // ArticleDraftResponse 图文草稿响应
 type ArticleDraftResponse struct {
-	Code int    `json:"code"`
-	Msg  string `json:"msg"`
+	ResponseStatus
 	Data struct {
 		DraftID   string `json:"draft_id,omitempty"`
 		MediaID   string `json:"media_id,omitempty"`
@@ -85,10 +121,17 @@ type ArticleDraftResponse struct {
 	} `json:"data,omitempty"`
 }
 
+// ConvertResponse Markdown 转换响应
+type ConvertResponse struct {
+	ResponseStatus
+	Data struct {
+		HTML string `json:"html,omitempty"`
+	} `json:"data,omitempty"`
+}
+
 // NewspicDraftResponse 小绿书草稿响应
 type NewspicDraftResponse struct {
-	Code int    `json:"code"`
-	Msg  string `json:"msg"`
+	ResponseStatus
 	Data struct {
 		DraftID   string `json:"draft_id,omitempty"`
 		Published bool   `json:"published,omitempty"`
@@ -97,8 +140,7 @@ type NewspicDraftResponse struct {
 
 // BatchUploadResponse 批量上传响应
 type BatchUploadResponse struct {
-	Code int    `json:"code"`
-	Msg  string `json:"msg"`
+	ResponseStatus
 	Data struct {
 		Results []UploadResult `json:"results,omitempty"`
 	} `json:"data,omitempty"`
@@ -106,17 +148,47 @@ type BatchUploadResponse struct {
 
 // UploadResult 上传结果
 type UploadResult struct {
-	URL     string `json:"url,omitempty"`
-	MediaID string `json:"media_id,omitempty"`
-	Success bool   `json:"success,omitempty"`
-	Error   string `json:"error,omitempty"`
+	URL      string `json:"url,omitempty"`
+	MediaID  string `json:"media_id,omitempty"`
+	Success  bool   `json:"success,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	// Reason 微信侧拒绝原因（如格式不支持、超出大小限制）
+	Reason string `json:"reason,omitempty"`
+	// Cached 结果来自本地图片上传缓存，未实际上传（由 CLI 填写）
+	Cached bool `json:"cached,omitempty"`
+}
+
+// Convert 将 Markdown 转换为微信公众号格式 HTML，不创建草稿
+func (c *Client) Convert(req *ConvertRequest) (*ConvertResponse, error) {
+	return c.ConvertContext(context.Background(), req)
+}
+
+// ConvertContext 将 Markdown 转换为 HTML，请求随 ctx 取消或超时而中止
+func (c *Client) ConvertContext(ctx context.Context, req *ConvertRequest) (*ConvertResponse, error) {
+	endpoint := "/api/v1/convert"
+	var resp ConvertResponse
+	if err := c.doRequest(ctx, "POST", endpoint, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
 }
 
 // ArticleDraft 创建图文草稿
 func (c *Client) ArticleDraft(req *ArticleDraftRequest) (*ArticleDraftResponse, error) {
+	return c.ArticleDraftContext(context.Background(), req)
+}
+
+// ArticleDraftContext 创建图文草稿，请求随 ctx 取消或超时而中止
+func (c *Client) ArticleDraftContext(ctx context.Context, req *ArticleDraftRequest) (*ArticleDraftResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 	endpoint := "/api/v1/article-draft"
 	var resp ArticleDraftResponse
-	if err := c.doRequest("POST", endpoint, req, &resp); err != nil {
+	if err := c.doNonIdempotent(ctx, "POST", endpoint, req, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -124,9 +196,14 @@ func (c *Client) ArticleDraft(req *ArticleDraftRequest) (*ArticleDraftResponse,
 
 // NewspicDraft 创建小绿书草稿
 func (c *Client) NewspicDraft(req *NewspicDraftRequest) (*NewspicDraftResponse, error) {
+	return c.NewspicDraftContext(context.Background(), req)
+}
+
+// NewspicDraftContext 创建小绿书草稿，请求随 ctx 取消或超时而中止
+func (c *Client) NewspicDraftContext(ctx context.Context, req *NewspicDraftRequest) (*NewspicDraftResponse, error) {
 	endpoint := "/api/v1/newspic-draft"
 	var resp NewspicDraftResponse
-	if err := c.doRequest("POST", endpoint, req, &resp); err != nil {
+	if err := c.doNonIdempotent(ctx, "POST", endpoint, req, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -134,54 +211,108 @@ func (c *Client) NewspicDraft(req *NewspicDraftRequest) (*NewspicDraftResponse,
 
 // BatchUpload 批量上传素材
 func (c *Client) BatchUpload(req *BatchUploadRequest) (*BatchUploadResponse, error) {
+	return c.BatchUploadContext(context.Background(), req)
+}
+
+// BatchUploadContext 批量上传素材，请求随 ctx 取消或超时而中止
+func (c *Client) BatchUploadContext(ctx context.Context, req *BatchUploadRequest) (*BatchUploadResponse, error) {
 	endpoint := "/api/v1/batch-upload"
 	var resp BatchUploadResponse
-	if err := c.doRequest("POST", endpoint, req, &resp); err != nil {
+	if err := c.doNonIdempotent(ctx, "POST", endpoint, req, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// doRequest 执行 HTTP 请求
-func (c *Client) doRequest(method, endpoint string, body any, resp any) error {
-	// 构建完整 URL
-	url := c.baseURL + endpoint
+// doRequest 以 JSON 请求体执行幂等的 HTTP 请求，按重试策略重试
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, body any, resp any) error {
+	return c.doJSON(ctx, c.retry, method, endpoint, body, resp)
+}
 
-	// 序列化请求体
-	var reqBody io.Reader
+// doNonIdempotent 以 JSON 请求体执行非幂等的请求（创建草稿、上传素材、提交发布）
+//
+// 只在请求未发出或返回 429、503 时重试：服务端可能已处理了响应超时或返回其他
+// 5xx 的请求，重试会创建重复的草稿或素材。
+func (c *Client) doNonIdempotent(ctx context.Context, method, endpoint string, body any, resp any) error {
+	return c.doJSON(ctx, c.retry.unsent(), method, endpoint, body, resp)
+}
+
+// doJSON 序列化请求体并按 policy 执行请求
+func (c *Client) doJSON(ctx context.Context, policy RetryPolicy, method, endpoint string, body any, resp any) error {
+	// 序列化请求体（重试时需要重新发送，因此保留字节内容）
+	var payload []byte
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("序列化请求失败: %w", err)
 		}
-		reqBody = bytes.NewReader(jsonData)
+		payload = jsonData
 	}
+	return c.do(ctx, policy, method, endpoint, "application/json", payload, resp)
+}
 
-	// 创建请求
-	req, err := http.NewRequest(method, url, reqBody)
-	if err != nil {
-		return fmt.Errorf("创建请求失败: %w", err)
+// do 执行 HTTP 请求，按重试策略重试可恢复的失败
+func (c *Client) do(ctx context.Context, policy RetryPolicy, method, endpoint, contentType string, payload []byte, resp any) error {
+	// 构建完整 URL
+	url := c.baseURL + endpoint
+
+	// wait 在两次尝试之间按退避策略等待，ctx 取消时返回错误
+	wait := func(attempt int, retryAfter time.Duration) error {
+		if err := c.sleep(ctx, policy.backoff(attempt, retryAfter)); err != nil {
+			return fmt.Errorf("请求已中止: %w", err)
+		}
+		return nil
 	}
 
-	// 设置认证 Headers
-	c.setAuthHeaders(req)
+	maxAttempts := policy.attempts()
+	var respData []byte
+	var requestID string
+	for attempt := 1; ; attempt++ {
+		httpResp, data, err := c.send(ctx, method, url, contentType, payload)
+		if err != nil {
+			// 优先返回 context 错误，便于调用方通过 errors.Is 区分取消与超时
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return fmt.Errorf("请求已中止: %w", ctxErr)
+			}
+			if attempt < maxAttempts && policy.isRetryableError(err) {
+				if err := wait(attempt, 0); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
 
-	// 发送请求
-	httpResp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("请求失败: %w", err)
-	}
-	defer httpResp.Body.Close()
+		// 检查 HTTP 状态码
+		if httpResp.StatusCode != http.StatusOK {
+			if attempt < maxAttempts && policy.isRetryableStatus(httpResp.StatusCode) {
+				retryAfter := parseRetryAfter(httpResp.Header.Get("Retry-After"))
+				if err := wait(attempt, retryAfter); err != nil {
+					return err
+				}
+				continue
+			}
+			return &Error{
+				StatusCode: httpResp.StatusCode,
+				Code:       businessCode(data),
+				Message:    http.StatusText(httpResp.StatusCode),
+				RequestID:  httpResp.Header.Get("X-Request-Id"),
+				Retryable:  policy.isRetryableStatus(httpResp.StatusCode),
+				Body:       string(data),
+			}
+		}
 
-	// 读取响应
-	respData, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return fmt.Errorf("读取响应失败: %w", err)
-	}
+		// 检查可重试的业务状态码（最后一次尝试的结果交给上层处理）
+		if attempt < maxAttempts && policy.isRetryableCode(businessCode(data)) {
+			if err := wait(attempt, 0); err != nil {
+				return err
+			}
+			continue
+		}
 
-	// 检查 HTTP 状态码
-	if httpResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP 错误: %d, 响应: %s", httpResp.StatusCode, string(respData))
+		respData = data
+		requestID = httpResp.Header.Get("X-Request-Id")
+		break
 	}
 
 	// 解析响应
@@ -189,28 +320,59 @@ func (c *Client) doRequest(method, endpoint string, body any, resp any) error {
 		return fmt.Errorf("解析响应失败: %w (响应: %s)", err, string(respData))
 	}
 
-	// 检查业务状态码
-	if apiResp, ok := resp.(*APIResponse); ok && apiResp.Code != 0 {
-		return fmt.Errorf("API 错误 (code %d): %s", apiResp.Code, apiResp.Msg)
+	// 记录请求 ID 和可重试标记，供 ResponseStatus.Err 使用
+	if meta, ok := resp.(interface{ setMeta(string, bool) }); ok {
+		meta.setMeta(requestID, policy.isRetryableCode(businessCode(respData)))
 	}
 
-	// 检查响应状态码（针对有 Code 字段的响应）
-	checkCode := func(code int, msg string) {
-		if code != 0 {
-			// 非零 code 表示业务错误，但不返回 error，让上层处理
-		}
+	// 通用响应的业务错误直接返回，其余响应的非零 code 交给上层处理
+	if apiResp, ok := resp.(*APIResponse); ok {
+		return apiResp.Err()
+	}
+
+	return nil
+}
+
+// send 发送一次 HTTP 请求并读取完整响应体
+func (c *Client) send(ctx context.Context, method, url, contentType string, payload []byte) (*http.Response, []byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
 	}
 
-	switch r := resp.(type) {
-	case *ArticleDraftResponse:
-		checkCode(r.Code, r.Msg)
-	case *NewspicDraftResponse:
-		checkCode(r.Code, r.Msg)
-	case *BatchUploadResponse:
-		checkCode(r.Code, r.Msg)
+	// 创建请求
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建请求失败: %w", err)
 	}
 
-	return nil
+	// 设置认证 Headers
+	c.setAuthHeaders(req)
+	req.Header.Set("Content-Type", contentType)
+
+	// 记录是否已取得连接，之前失败的请求没有发出任何字节
+	var connected atomic.Bool
+	req = req.WithContext(httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) { connected.Store(true) },
+	}))
+
+	// 发送请求
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("请求失败: %w", err)
+		if !connected.Load() {
+			return nil, nil, &notSentError{err}
+		}
+		return nil, nil, err
+	}
+	defer httpResp.Body.Close()
+
+	// 读取响应
+	respData, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	return httpResp, respData, nil
 }
 
 // setAuthHeaders 设置认证 Headers
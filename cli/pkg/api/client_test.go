@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -129,6 +131,49 @@ func TestArticleDraft_APIError(t *testing.T) {
 	}
 }
 
+func TestConvert_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/convert" {
+			t.Errorf("Path = %s, want /api/v1/convert", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["theme"] != "bytedance" {
+			t.Errorf("theme = %v, want bytedance", body["theme"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"msg":  "success",
+			"data": map[string]interface{}{
+				"html": "<section><h1>Test</h1></section>",
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-appid", "test-secret", "test-key")
+
+	req := &ConvertRequest{
+		Markdown: "# Test",
+		Theme:    "bytedance",
+	}
+
+	resp, err := client.Convert(req)
+	if err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+
+	if resp.Code != 0 {
+		t.Errorf("Code = %d, want 0", resp.Code)
+	}
+	if resp.Data.HTML != "<section><h1>Test</h1></section>" {
+		t.Errorf("HTML = %s, want full html", resp.Data.HTML)
+	}
+}
+
 func TestNewspicDraft_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/newspic-draft" {
@@ -206,6 +251,47 @@ func TestBatchUpload_Success(t *testing.T) {
 	}
 }
 
+func TestArticleDraftContext_Canceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL, "test-appid", "test-secret", "test-key")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := client.ArticleDraftContext(ctx, &ArticleDraftRequest{Markdown: "# Test"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestBatchUploadContext_Deadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL, "test-appid", "test-secret", "test-key")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.BatchUploadContext(ctx, &BatchUploadRequest{ImageUrls: []string{"http://example.com/1.jpg"}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
 func TestSetTimeout(t *testing.T) {
 	client := NewClient("http://example.com", "appid", "secret", "key")
 
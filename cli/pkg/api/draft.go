@@ -0,0 +1,181 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// MaxDraftPageSize 草稿列表单页最大数量
+const MaxDraftPageSize = 20
+
+// DraftArticle 草稿中的一篇文章
+type DraftArticle struct {
+	Title              string `json:"title"`
+	Author             string `json:"author,omitempty"`
+	Digest             string `json:"digest,omitempty"`
+	Content            string `json:"content,omitempty"`
+	ContentSourceUrl   string `json:"content_source_url,omitempty"`
+	ThumbMediaID       string `json:"thumb_media_id,omitempty"`
+	ThumbURL           string `json:"thumb_url,omitempty"`
+	URL                string `json:"url,omitempty"`
+	NeedOpenComment    int    `json:"need_open_comment,omitempty"`
+	OnlyFansCanComment int    `json:"only_fans_can_comment,omitempty"`
+}
+
+// Draft 草稿
+type Draft struct {
+	MediaID    string         `json:"media_id"`
+	UpdateTime int64          `json:"update_time,omitempty"`
+	Articles   []DraftArticle `json:"articles"`
+}
+
+// DraftListRequest 草稿列表请求
+type DraftListRequest struct {
+	Offset int
+	// Count 每页数量，1~20，为 0 时使用 20
+	Count int
+	// NoContent 不返回文章正文，减少响应大小
+	NoContent bool
+}
+
+// DraftListResponse 草稿列表响应
+type DraftListResponse struct {
+	ResponseStatus
+	Data struct {
+		TotalCount int     `json:"total_count"`
+		ItemCount  int     `json:"item_count"`
+		Items      []Draft `json:"items"`
+	} `json:"data,omitempty"`
+}
+
+// DraftResponse 单个草稿响应
+type DraftResponse struct {
+	ResponseStatus
+	Data Draft `json:"data,omitempty"`
+}
+
+// UpdateDraftRequest 更新草稿请求，Index 为要替换的文章位置（从 0 开始）
+type UpdateDraftRequest struct {
+	Index   int                 `json:"index"`
+	Article ArticleDraftRequest `json:"article"`
+}
+
+// ListDrafts 分页获取草稿列表
+func (c *Client) ListDrafts(req *DraftListRequest) (*DraftListResponse, error) {
+	return c.ListDraftsContext(context.Background(), req)
+}
+
+// ListDraftsContext 分页获取草稿列表，请求随 ctx 取消或超时而中止
+func (c *Client) ListDraftsContext(ctx context.Context, req *DraftListRequest) (*DraftListResponse, error) {
+	count := req.Count
+	if count == 0 {
+		count = MaxDraftPageSize
+	}
+	if count < 1 || count > MaxDraftPageSize {
+		return nil, fmt.Errorf("每页数量必须在 1~%d 之间", MaxDraftPageSize)
+	}
+	if req.Offset < 0 {
+		return nil, fmt.Errorf("offset 不能为负数")
+	}
+
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(req.Offset))
+	query.Set("count", strconv.Itoa(count))
+	if req.NoContent {
+		query.Set("no_content", "1")
+	}
+
+	endpoint := "/api/v1/drafts?" + query.Encode()
+	var resp DraftListResponse
+	if err := c.doRequest(ctx, "GET", endpoint, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// VerifyCredentials 校验 API Key 和公众号凭据，见 VerifyCredentialsContext
+func (c *Client) VerifyCredentials() error {
+	return c.VerifyCredentialsContext(context.Background())
+}
+
+// VerifyCredentialsContext 通过获取一条不含正文的草稿校验 API Key 和公众号凭据
+//
+// 该请求需要服务端用 AppID/AppSecret 换取 access_token，因此能同时发现三者的错误；
+// 凭据无效时返回 *Error（如 ErrAccessTokenExpired、HTTP 401）。
+func (c *Client) VerifyCredentialsContext(ctx context.Context) error {
+	resp, err := c.ListDraftsContext(ctx, &DraftListRequest{Count: 1, NoContent: true})
+	if err != nil {
+		return err
+	}
+	return resp.Err()
+}
+
+// GetDraft 获取草稿详情
+func (c *Client) GetDraft(mediaID string) (*DraftResponse, error) {
+	return c.GetDraftContext(context.Background(), mediaID)
+}
+
+// GetDraftContext 获取草稿详情，请求随 ctx 取消或超时而中止
+func (c *Client) GetDraftContext(ctx context.Context, mediaID string) (*DraftResponse, error) {
+	endpoint, err := draftEndpoint(mediaID)
+	if err != nil {
+		return nil, err
+	}
+	var resp DraftResponse
+	if err := c.doRequest(ctx, "GET", endpoint, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateDraft 替换草稿中指定位置的文章
+func (c *Client) UpdateDraft(mediaID string, req *UpdateDraftRequest) (*APIResponse, error) {
+	return c.UpdateDraftContext(context.Background(), mediaID, req)
+}
+
+// UpdateDraftContext 替换草稿中指定位置的文章，请求随 ctx 取消或超时而中止
+func (c *Client) UpdateDraftContext(ctx context.Context, mediaID string, req *UpdateDraftRequest) (*APIResponse, error) {
+	endpoint, err := draftEndpoint(mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Index < 0 || req.Index >= MaxNewsArticles {
+		return nil, fmt.Errorf("文章位置必须在 0~%d 之间", MaxNewsArticles-1)
+	}
+	if err := req.Article.Validate(); err != nil {
+		return nil, err
+	}
+	var resp APIResponse
+	if err := c.doRequest(ctx, "PUT", endpoint, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteDraft 删除草稿
+func (c *Client) DeleteDraft(mediaID string) (*APIResponse, error) {
+	return c.DeleteDraftContext(context.Background(), mediaID)
+}
+
+// DeleteDraftContext 删除草稿，请求随 ctx 取消或超时而中止
+func (c *Client) DeleteDraftContext(ctx context.Context, mediaID string) (*APIResponse, error) {
+	endpoint, err := draftEndpoint(mediaID)
+	if err != nil {
+		return nil, err
+	}
+	var resp APIResponse
+	if err := c.doRequest(ctx, "DELETE", endpoint, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// draftEndpoint 返回单个草稿的接口路径
+func draftEndpoint(mediaID string) (string, error) {
+	if mediaID == "" {
+		return "", fmt.Errorf("media_id 不能为空")
+	}
+	return "/api/v1/drafts/" + url.PathEscape(mediaID), nil
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListDrafts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/v1/drafts" {
+			t.Errorf("%s %s, want GET /api/v1/drafts", r.Method, r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("offset") != "20" || q.Get("count") != "20" || q.Get("no_content") != "1" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"code": 0,
+			"data": map[string]any{
+				"total_count": 21,
+				"item_count":  1,
+				"items": []map[string]any{
+					{"media_id": "m1", "update_time": 1700000000, "articles": []map[string]any{{"title": "标题"}}},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-app-id", "test-app-secret", "test-api-key")
+	resp, err := client.ListDrafts(&DraftListRequest{Offset: 20, NoContent: true})
+	if err != nil {
+		t.Fatalf("ListDrafts() failed: %v", err)
+	}
+	if resp.Data.TotalCount != 21 || len(resp.Data.Items) != 1 || resp.Data.Items[0].Articles[0].Title != "标题" {
+		t.Errorf("Data = %+v", resp.Data)
+	}
+}
+
+func TestListDrafts_InvalidCount(t *testing.T) {
+	client := NewClient("http://127.0.0.1:0", "test-app-id", "test-app-secret", "test-api-key")
+	for _, req := range []*DraftListRequest{{Count: 21}, {Count: -1}, {Offset: -1}} {
+		if _, err := client.ListDrafts(req); err == nil {
+			t.Errorf("ListDrafts(%+v) should fail", req)
+		}
+	}
+}
+
+func TestVerifyCredentials(t *testing.T) {
+	code := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/drafts" || r.URL.Query().Get("count") != "1" || r.URL.Query().Get("no_content") != "1" {
+			t.Errorf("request = %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"code": code, "msg": "invalid appsecret"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-app-id", "test-app-secret", "test-api-key")
+	if err := client.VerifyCredentials(); err != nil {
+		t.Errorf("VerifyCredentials() = %v", err)
+	}
+	code = 40001
+	var apiErr *Error
+	if err := client.VerifyCredentials(); !errors.As(err, &apiErr) || apiErr.Code != 40001 {
+		t.Errorf("VerifyCredentials() = %v, want code 40001", err)
+	}
+}
+
+func TestDraftByID(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody UpdateDraftRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.EscapedPath()
+		if r.Method == "PUT" {
+			json.NewDecoder(r.Body).Decode(&gotBody)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"code": 0,
+			"data": map[string]any{"media_id": "m/1", "articles": []map[string]any{{"title": "a"}, {"title": "b"}}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-app-id", "test-app-secret", "test-api-key")
+
+	resp, err := client.GetDraft("m/1")
+	if err != nil {
+		t.Fatalf("GetDraft() failed: %v", err)
+	}
+	if gotMethod != "GET" || gotPath != "/api/v1/drafts/m%2F1" || len(resp.Data.Articles) != 2 {
+		t.Errorf("GetDraft: %s %s, data = %+v", gotMethod, gotPath, resp.Data)
+	}
+
+	if _, err := client.UpdateDraft("m1", &UpdateDraftRequest{Index: 1, Article: ArticleDraftRequest{Markdown: "# b", Title: "b"}}); err != nil {
+		t.Fatalf("UpdateDraft() failed: %v", err)
+	}
+	if gotMethod != "PUT" || gotBody.Index != 1 || gotBody.Article.Title != "b" {
+		t.Errorf("UpdateDraft: %s, body = %+v", gotMethod, gotBody)
+	}
+
+	if _, err := client.DeleteDraft("m1"); err != nil {
+		t.Fatalf("DeleteDraft() failed: %v", err)
+	}
+	if gotMethod != "DELETE" || gotPath != "/api/v1/drafts/m1" {
+		t.Errorf("DeleteDraft: %s %s", gotMethod, gotPath)
+	}
+}
+
+func TestDraftByID_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"code": 40007, "msg": "invalid media_id"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-app-id", "test-app-secret", "test-api-key")
+	if _, err := client.DeleteDraft("bad"); !errors.Is(err, ErrInvalidMediaID) {
+		t.Errorf("DeleteDraft() error = %v, want ErrInvalidMediaID", err)
+	}
+	if _, err := client.GetDraft(""); err == nil {
+		t.Error("GetDraft(\"\") should fail")
+	}
+	if _, err := client.UpdateDraft("m1", &UpdateDraftRequest{Index: 8, Article: ArticleDraftRequest{Markdown: "# a"}}); err == nil {
+		t.Error("UpdateDraft() with index 8 should fail")
+	}
+}
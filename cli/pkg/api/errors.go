@@ -0,0 +1,169 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 已知错误类型，可通过 errors.Is 判断
+//
+//	if errors.Is(err, api.ErrInvalidMediaID) { ... }
+var (
+	// ErrInvalidCredential AppSecret / API Key 错误或 access_token 无效
+	ErrInvalidCredential = errors.New("凭证无效")
+	// ErrInvalidAppID AppID 无效
+	ErrInvalidAppID = errors.New("AppID 无效")
+	// ErrInvalidAppSecret AppSecret 无效
+	ErrInvalidAppSecret = errors.New("AppSecret 无效")
+	// ErrIPNotWhitelisted 调用方 IP 不在公众号白名单中
+	ErrIPNotWhitelisted = errors.New("IP 不在白名单中")
+	// ErrAccessTokenExpired access_token 已过期
+	ErrAccessTokenExpired = errors.New("access_token 已过期")
+	// ErrInvalidMediaID media_id 无效（常见于封面图缺失或未上传）
+	ErrInvalidMediaID = errors.New("media_id 无效")
+	// ErrQuotaExceeded 接口调用次数超出配额
+	ErrQuotaExceeded = errors.New("调用次数超出配额")
+	// ErrRateLimited 请求过于频繁
+	ErrRateLimited = errors.New("请求过于频繁")
+	// ErrSystemBusy 服务繁忙，稍后可重试
+	ErrSystemBusy = errors.New("系统繁忙")
+	// ErrUnauthorized API Key 缺失或无效
+	ErrUnauthorized = errors.New("未授权")
+)
+
+// knownError 已知错误码对应的错误类型和稳定错误码
+type knownError struct {
+	err  error
+	name string
+}
+
+// businessErrors md2wechat / 微信业务 code 映射
+var businessErrors = map[int]knownError{
+	-1:    {ErrSystemBusy, "SYSTEM_BUSY"},
+	40001: {ErrInvalidCredential, "INVALID_CREDENTIAL"},
+	40007: {ErrInvalidMediaID, "INVALID_MEDIA_ID"},
+	40013: {ErrInvalidAppID, "INVALID_APPID"},
+	40125: {ErrInvalidAppSecret, "INVALID_APPSECRET"},
+	40164: {ErrIPNotWhitelisted, "IP_NOT_WHITELISTED"},
+	42001: {ErrAccessTokenExpired, "ACCESS_TOKEN_EXPIRED"},
+	45009: {ErrQuotaExceeded, "QUOTA_EXCEEDED"},
+	45011: {ErrRateLimited, "RATE_LIMITED"},
+}
+
+// statusErrors HTTP 状态码映射
+var statusErrors = map[int]knownError{
+	http.StatusUnauthorized:       {ErrUnauthorized, "UNAUTHORIZED"},
+	http.StatusForbidden:          {ErrUnauthorized, "UNAUTHORIZED"},
+	http.StatusTooManyRequests:    {ErrRateLimited, "RATE_LIMITED"},
+	http.StatusServiceUnavailable: {ErrSystemBusy, "SYSTEM_BUSY"},
+}
+
+// Error API 调用错误
+//
+// HTTP 状态码非 200 或业务 code 非零时产生，可通过 errors.As 获取详情：
+//
+//	var apiErr *api.Error
+//	if errors.As(err, &apiErr) && apiErr.Retryable { ... }
+type Error struct {
+	// StatusCode HTTP 状态码
+	StatusCode int
+	// Code 业务 code（HTTP 错误且响应体无法解析时为 0）
+	Code int
+	// Message 错误信息
+	Message string
+	// RequestID 服务端请求 ID（X-Request-Id 响应头）
+	RequestID string
+	// Retryable 按当前重试策略是否可重试
+	Retryable bool
+	// Body 原始响应体
+	Body string
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	if e.StatusCode != http.StatusOK {
+		return fmt.Sprintf("HTTP 错误: %d, 响应: %s", e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("API 错误 (code %d): %s", e.Code, e.Message)
+}
+
+// Unwrap 返回对应的已知错误类型，未知错误码返回 nil
+func (e *Error) Unwrap() error {
+	if known, ok := e.known(); ok {
+		return known.err
+	}
+	return nil
+}
+
+// ErrorCode 返回稳定的机器可读错误码
+//
+// 已知错误返回命名错误码（如 INVALID_MEDIA_ID），其余返回
+// API_ERROR_<code> 或 HTTP_ERROR_<status>。
+func (e *Error) ErrorCode() string {
+	if known, ok := e.known(); ok {
+		return known.name
+	}
+	if e.Code != 0 {
+		return fmt.Sprintf("API_ERROR_%d", e.Code)
+	}
+	return fmt.Sprintf("HTTP_ERROR_%d", e.StatusCode)
+}
+
+// ErrorDetails 返回用于输出的诊断信息
+func (e *Error) ErrorDetails() map[string]any {
+	details := map[string]any{
+		"http_status": e.StatusCode,
+		"retryable":   e.Retryable,
+	}
+	if e.Code != 0 {
+		details["api_code"] = e.Code
+	}
+	if e.RequestID != "" {
+		details["request_id"] = e.RequestID
+	}
+	return details
+}
+
+// known 查找错误对应的已知类型，业务 code 优先于 HTTP 状态码
+func (e *Error) known() (knownError, bool) {
+	if known, ok := businessErrors[e.Code]; ok && e.Code != 0 {
+		return known, true
+	}
+	known, ok := statusErrors[e.StatusCode]
+	return known, ok
+}
+
+// ResponseStatus 响应中的业务状态，嵌入各响应结构体
+type ResponseStatus struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+
+	requestID string
+	retryable bool
+}
+
+// Err 将非零业务 code 转换为 *Error，成功时返回 nil
+func (s *ResponseStatus) Err() error {
+	if s.Code == 0 {
+		return nil
+	}
+	return &Error{
+		StatusCode: http.StatusOK,
+		Code:       s.Code,
+		Message:    s.Msg,
+		RequestID:  s.requestID,
+		Retryable:  s.retryable,
+	}
+}
+
+// RequestID 返回服务端请求 ID
+func (s *ResponseStatus) RequestID() string {
+	return s.requestID
+}
+
+// setMeta 记录响应元信息
+func (s *ResponseStatus) setMeta(requestID string, retryable bool) {
+	s.requestID = requestID
+	s.retryable = retryable
+}
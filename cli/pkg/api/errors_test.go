@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestError_HTTPStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_123")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code":401,"msg":"invalid api key"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-appid", "test-secret", "test-key")
+
+	_, err := client.ArticleDraft(&ArticleDraftRequest{Markdown: "# Test"})
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *Error", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("StatusCode = %d, want 401", apiErr.StatusCode)
+	}
+	if apiErr.RequestID != "req_123" {
+		t.Errorf("RequestID = %s, want req_123", apiErr.RequestID)
+	}
+	if apiErr.Body != `{"code":401,"msg":"invalid api key"}` {
+		t.Errorf("Body = %s", apiErr.Body)
+	}
+	if apiErr.Retryable {
+		t.Error("401 should not be retryable")
+	}
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Error("errors.Is(err, ErrUnauthorized) = false, want true")
+	}
+	if apiErr.ErrorCode() != "UNAUTHORIZED" {
+		t.Errorf("ErrorCode() = %s, want UNAUTHORIZED", apiErr.ErrorCode())
+	}
+}
+
+func TestResponseStatus_Err(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_456")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 40007,
+			"msg":  "invalid media_id",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-appid", "test-secret", "test-key")
+
+	resp, err := client.ArticleDraft(&ArticleDraftRequest{Markdown: "# Test"})
+	if err != nil {
+		t.Fatalf("ArticleDraft() failed: %v", err)
+	}
+
+	err = resp.Err()
+	if !errors.Is(err, ErrInvalidMediaID) {
+		t.Fatalf("err = %v, want ErrInvalidMediaID", err)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *Error", err)
+	}
+	if apiErr.Code != 40007 {
+		t.Errorf("Code = %d, want 40007", apiErr.Code)
+	}
+	if apiErr.RequestID != "req_456" {
+		t.Errorf("RequestID = %s, want req_456", apiErr.RequestID)
+	}
+	if apiErr.ErrorCode() != "INVALID_MEDIA_ID" {
+		t.Errorf("ErrorCode() = %s, want INVALID_MEDIA_ID", apiErr.ErrorCode())
+	}
+}
+
+func TestResponseStatus_ErrSuccess(t *testing.T) {
+	status := ResponseStatus{Code: 0, Msg: "success"}
+	if err := status.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestError_ErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *Error
+		want string
+	}{
+		{"access_token 过期", &Error{StatusCode: 200, Code: 42001}, "ACCESS_TOKEN_EXPIRED"},
+		{"配额超限", &Error{StatusCode: 200, Code: 45009}, "QUOTA_EXCEEDED"},
+		{"IP 白名单", &Error{StatusCode: 200, Code: 40164}, "IP_NOT_WHITELISTED"},
+		{"未知业务 code", &Error{StatusCode: 200, Code: 12345}, "API_ERROR_12345"},
+		{"未知 HTTP 状态码", &Error{StatusCode: 502}, "HTTP_ERROR_502"},
+		{"业务 code 优先", &Error{StatusCode: 429, Code: 40001}, "INVALID_CREDENTIAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.ErrorCode(); got != tt.want {
+				t.Errorf("ErrorCode() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError_Unwrap(t *testing.T) {
+	err := &Error{StatusCode: 200, Code: 12345}
+	if err.Unwrap() != nil {
+		t.Errorf("Unwrap() = %v, want nil for unknown code", err.Unwrap())
+	}
+
+	err = &Error{StatusCode: 200, Code: -1}
+	if !errors.Is(err, ErrSystemBusy) {
+		t.Error("errors.Is(err, ErrSystemBusy) = false, want true")
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ProbeResult API 服务连通性探测结果
+type ProbeResult struct {
+	// URL 探测的地址（API Base URL）
+	URL string
+	// StatusCode HTTP 状态码，只要收到响应即视为可达
+	StatusCode int
+	// Latency 请求往返耗时
+	Latency time.Duration
+	// TLSVersion 协商的 TLS 版本，非 https 时为空
+	TLSVersion string
+	// CertSubject 服务端证书主体
+	CertSubject string
+	// CertExpiry 服务端证书过期时间，非 https 时为零值
+	CertExpiry time.Time
+	// ServerTime 响应 Date 头中的服务端时间，缺失时为零值
+	ServerTime time.Time
+	// LocalTime 请求往返中点的本地时间，用于计算时钟偏差
+	LocalTime time.Time
+}
+
+// ClockSkew 返回本地时间相对服务端时间的偏差（本地快为正），ok 表示响应带有 Date 头
+//
+// Date 头精确到秒，偏差小于 1 秒时没有意义。
+func (r *ProbeResult) ClockSkew() (skew time.Duration, ok bool) {
+	if r.ServerTime.IsZero() {
+		return 0, false
+	}
+	return r.LocalTime.Sub(r.ServerTime), true
+}
+
+// Probe 探测 API 服务，见 ProbeContext
+func (c *Client) Probe() (*ProbeResult, error) {
+	return c.ProbeContext(context.Background())
+}
+
+// ProbeContext 向 API Base URL 发送一次不带凭据的 GET 请求，记录耗时、TLS 信息和服务端时间
+//
+// 不重试；任何 HTTP 状态码都视为可达，证书校验失败时返回 *tls.CertificateVerificationError。
+func (c *Client) ProbeContext(ctx context.Context) (*ProbeResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	start := time.Now()
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer httpResp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(httpResp.Body, 64<<10))
+	latency := time.Since(start)
+
+	result := &ProbeResult{
+		URL:        c.baseURL,
+		StatusCode: httpResp.StatusCode,
+		Latency:    latency,
+		LocalTime:  start.Add(latency / 2),
+	}
+	if date := httpResp.Header.Get("Date"); date != "" {
+		if t, err := http.ParseTime(date); err == nil {
+			result.ServerTime = t
+		}
+	}
+	if state := httpResp.TLS; state != nil {
+		result.TLSVersion = tls.VersionName(state.Version)
+		if len(state.PeerCertificates) > 0 {
+			cert := state.PeerCertificates[0]
+			result.CertSubject = cert.Subject.CommonName
+			result.CertExpiry = cert.NotAfter
+		}
+	}
+	return result, nil
+}
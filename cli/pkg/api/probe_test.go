@@ -0,0 +1,69 @@
+package api
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProbe(t *testing.T) {
+	serverTime := time.Now().Add(-2 * time.Minute).UTC()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Md2wechat-API-Key") != "" || r.Header.Get("Wechat-App-Secret") != "" {
+			t.Error("probe should not send credentials")
+		}
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	result, err := NewClient(server.URL, "app", "secret", "key").Probe()
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if result.StatusCode != http.StatusNotFound || result.TLSVersion != "" || !result.CertExpiry.IsZero() {
+		t.Errorf("result = %+v", result)
+	}
+	skew, ok := result.ClockSkew()
+	if !ok || skew < 119*time.Second || skew > 122*time.Second {
+		t.Errorf("ClockSkew() = %v, %v, want ~2m", skew, ok)
+	}
+}
+
+func TestProbe_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// 未信任的自签名证书
+	_, err := NewClient(server.URL, "", "", "").Probe()
+	var certErr *tls.CertificateVerificationError
+	if !errors.As(err, &certErr) {
+		t.Errorf("Probe() untrusted error = %v, want *tls.CertificateVerificationError", err)
+	}
+
+	client := NewClient(server.URL, "", "", "")
+	client.httpClient = server.Client()
+	result, err := client.Probe()
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if result.TLSVersion == "" || result.CertExpiry.IsZero() {
+		t.Errorf("result = %+v, want TLS info", result)
+	}
+	if _, ok := result.ClockSkew(); !ok {
+		t.Error("ClockSkew() ok = false, want Date header from net/http")
+	}
+}
+
+func TestProbe_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	if _, err := NewClient(url, "", "", "").Probe(); err == nil {
+		t.Error("Probe() on closed server should fail")
+	}
+}
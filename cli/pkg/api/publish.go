@@ -0,0 +1,252 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PublishState 发布状态，与微信 freepublish 接口的 publish_status 一致
+type PublishState int
+
+const (
+	// PublishSuccess 发布成功
+	PublishSuccess PublishState = 0
+	// PublishPublishing 发布中
+	PublishPublishing PublishState = 1
+	// PublishOriginalFailed 原创声明失败
+	PublishOriginalFailed PublishState = 2
+	// PublishFailed 常规失败
+	PublishFailed PublishState = 3
+	// PublishAuditFailed 平台审核不通过
+	PublishAuditFailed PublishState = 4
+	// PublishDeleted 发布成功后用户删除所有文章
+	PublishDeleted PublishState = 5
+	// PublishBanned 发布成功后被系统封禁
+	PublishBanned PublishState = 6
+)
+
+// publishStateNames 发布状态的机器可读名称
+var publishStateNames = map[PublishState]string{
+	PublishSuccess:        "success",
+	PublishPublishing:     "publishing",
+	PublishOriginalFailed: "original_check_failed",
+	PublishFailed:         "failed",
+	PublishAuditFailed:    "audit_failed",
+	PublishDeleted:        "deleted",
+	PublishBanned:         "banned",
+}
+
+// String 返回状态名称
+func (s PublishState) String() string {
+	if name, ok := publishStateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown_%d", int(s))
+}
+
+// Terminal 是否为终态（不再变化）
+func (s PublishState) Terminal() bool {
+	return s != PublishPublishing
+}
+
+// PublishRequest 发布请求
+type PublishRequest struct {
+	MediaID string `json:"mediaId"`
+}
+
+// PublishResponse 发布响应
+type PublishResponse struct {
+	ResponseStatus
+	Data struct {
+		PublishID string `json:"publish_id"`
+		MsgDataID string `json:"msg_data_id,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// PublishArticle 已发布的文章
+type PublishArticle struct {
+	Index      int    `json:"idx"`
+	ArticleURL string `json:"article_url"`
+}
+
+// PublishStatus 发布状态详情
+type PublishStatus struct {
+	PublishID     string       `json:"publish_id"`
+	State         PublishState `json:"publish_status"`
+	ArticleID     string       `json:"article_id,omitempty"`
+	ArticleDetail *struct {
+		Count int              `json:"count"`
+		Items []PublishArticle `json:"item"`
+	} `json:"article_detail,omitempty"`
+	// FailIndex 原创声明失败或审核不通过的文章序号（从 1 开始）
+	FailIndex []int `json:"fail_idx,omitempty"`
+
+	// hasState 响应中包含非 null 的 publish_status
+	hasState bool
+}
+
+// UnmarshalJSON 解析发布状态并记录 publish_status 是否存在
+//
+// PublishSuccess 为 0，缺少该字段时不能按零值当作发布成功。
+func (s *PublishStatus) UnmarshalJSON(data []byte) error {
+	type plain PublishStatus
+	aux := struct {
+		*plain
+		State *PublishState `json:"publish_status"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	s.hasState = aux.State != nil
+	if aux.State != nil {
+		s.State = *aux.State
+	}
+	return nil
+}
+
+// ArticleURLs 返回已发布文章的链接
+func (s *PublishStatus) ArticleURLs() []string {
+	if s.ArticleDetail == nil {
+		return nil
+	}
+	urls := make([]string, 0, len(s.ArticleDetail.Items))
+	for _, item := range s.ArticleDetail.Items {
+		urls = append(urls, item.ArticleURL)
+	}
+	return urls
+}
+
+// PublishStatusResponse 发布状态响应
+type PublishStatusResponse struct {
+	ResponseStatus
+	Data PublishStatus `json:"data,omitempty"`
+}
+
+// PublishError 发布进入失败终态
+type PublishError struct {
+	Status *PublishStatus
+}
+
+// Error 实现 error 接口
+func (e *PublishError) Error() string {
+	msg := map[PublishState]string{
+		PublishOriginalFailed: "原创声明失败",
+		PublishFailed:         "发布失败",
+		PublishAuditFailed:    "平台审核不通过",
+		PublishDeleted:        "文章已被删除",
+		PublishBanned:         "文章已被封禁",
+	}[e.Status.State]
+	if msg == "" {
+		msg = fmt.Sprintf("发布状态异常: %s", e.Status.State)
+	}
+	if len(e.Status.FailIndex) > 0 {
+		msg += fmt.Sprintf("（失败文章序号: %v）", e.Status.FailIndex)
+	}
+	return msg
+}
+
+// ErrorCode 返回机器可读错误码，如 PUBLISH_AUDIT_FAILED
+func (e *PublishError) ErrorCode() string {
+	return "PUBLISH_" + strings.ToUpper(e.Status.State.String())
+}
+
+// ErrorDetails 返回发布 ID、状态和失败文章序号
+func (e *PublishError) ErrorDetails() map[string]any {
+	details := map[string]any{
+		"publish_id":     e.Status.PublishID,
+		"publish_status": int(e.Status.State),
+		"state":          e.Status.State.String(),
+	}
+	if len(e.Status.FailIndex) > 0 {
+		details["fail_idx"] = e.Status.FailIndex
+	}
+	return details
+}
+
+// Err 成功或发布中返回 nil，失败终态返回 *PublishError
+func (s *PublishStatus) Err() error {
+	if s.State == PublishSuccess || s.State == PublishPublishing {
+		return nil
+	}
+	return &PublishError{Status: s}
+}
+
+// Publish 提交草稿发布
+func (c *Client) Publish(mediaID string) (*PublishResponse, error) {
+	return c.PublishContext(context.Background(), mediaID)
+}
+
+// PublishContext 提交草稿发布，请求随 ctx 取消或超时而中止
+//
+// 发布是异步的，返回的 publish_id 用于查询发布状态。
+func (c *Client) PublishContext(ctx context.Context, mediaID string) (*PublishResponse, error) {
+	if mediaID == "" {
+		return nil, fmt.Errorf("media_id 不能为空")
+	}
+	endpoint := "/api/v1/publish"
+	var resp PublishResponse
+	if err := c.doNonIdempotent(ctx, "POST", endpoint, &PublishRequest{MediaID: mediaID}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetPublishStatus 查询发布状态
+func (c *Client) GetPublishStatus(publishID string) (*PublishStatusResponse, error) {
+	return c.GetPublishStatusContext(context.Background(), publishID)
+}
+
+// GetPublishStatusContext 查询发布状态，请求随 ctx 取消或超时而中止
+func (c *Client) GetPublishStatusContext(ctx context.Context, publishID string) (*PublishStatusResponse, error) {
+	if publishID == "" {
+		return nil, fmt.Errorf("publish_id 不能为空")
+	}
+	endpoint := "/api/v1/publish/" + url.PathEscape(publishID)
+	var resp PublishStatusResponse
+	if err := c.doRequest(ctx, "GET", endpoint, nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code == 0 && !resp.Data.hasState {
+		return nil, fmt.Errorf("发布状态响应缺少 publish_status")
+	}
+	return &resp, nil
+}
+
+// WaitPublishContext 轮询发布状态直到终态或 ctx 结束
+//
+// 轮询间隔从 interval 开始按 2 倍递增，不超过 maxInterval。
+// onPoll 在每次查询后调用，可为 nil。
+func (c *Client) WaitPublishContext(ctx context.Context, publishID string, interval, maxInterval time.Duration, onPoll func(*PublishStatus)) (*PublishStatus, error) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+
+	for {
+		resp, err := c.GetPublishStatusContext(ctx, publishID)
+		if err != nil {
+			return nil, err
+		}
+		if err := resp.Err(); err != nil {
+			return nil, err
+		}
+		status := &resp.Data
+		if onPoll != nil {
+			onPoll(status)
+		}
+		if status.State.Terminal() {
+			return status, nil
+		}
+
+		if err := c.sleep(ctx, interval); err != nil {
+			return status, fmt.Errorf("等待发布结果已中止: %w", err)
+		}
+		interval = min(interval*2, maxInterval)
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPublish(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v1/publish" {
+			t.Errorf("%s %s, want POST /api/v1/publish", r.Method, r.URL.Path)
+		}
+		var req PublishRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.MediaID != "m1" {
+			t.Errorf("MediaID = %s, want m1", req.MediaID)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"publish_id": "p1"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-app-id", "test-app-secret", "test-api-key")
+	resp, err := client.Publish("m1")
+	if err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	if resp.Data.PublishID != "p1" {
+		t.Errorf("PublishID = %s, want p1", resp.Data.PublishID)
+	}
+}
+
+func TestWaitPublish_Backoff(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/publish/p1" {
+			t.Errorf("Path = %s", r.URL.Path)
+		}
+		data := map[string]any{"publish_id": "p1", "publish_status": 1}
+		if polls.Add(1) == 4 {
+			data = map[string]any{
+				"publish_id":     "p1",
+				"publish_status": 0,
+				"article_id":     "a1",
+				"article_detail": map[string]any{"count": 1, "item": []map[string]any{{"idx": 1, "article_url": "https://mp.weixin.qq.com/s/x"}}},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": data})
+	}))
+	defer server.Close()
+
+	client, waits := newRetryTestClient(server.URL, DefaultRetryPolicy())
+	var seen []PublishState
+	status, err := client.WaitPublishContext(context.Background(), "p1", time.Second, 3*time.Second, func(s *PublishStatus) {
+		seen = append(seen, s.State)
+	})
+	if err != nil {
+		t.Fatalf("WaitPublishContext() failed: %v", err)
+	}
+	if status.State != PublishSuccess || status.ArticleID != "a1" {
+		t.Errorf("status = %+v", status)
+	}
+	if got := status.ArticleURLs(); !reflect.DeepEqual(got, []string{"https://mp.weixin.qq.com/s/x"}) {
+		t.Errorf("ArticleURLs() = %v", got)
+	}
+	if want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}; !reflect.DeepEqual(*waits, want) {
+		t.Errorf("waits = %v, want %v", *waits, want)
+	}
+	if len(seen) != 4 {
+		t.Errorf("onPoll called %d times, want 4", len(seen))
+	}
+}
+
+func TestWaitPublish_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"publish_status": 1}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-app-id", "test-app-secret", "test-api-key")
+	ctx, cancel := context.WithCancel(context.Background())
+	client.sleep = func(context.Context, time.Duration) error {
+		cancel()
+		return context.Canceled
+	}
+	if _, err := client.WaitPublishContext(ctx, "p1", time.Second, time.Second, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitPublishContext() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestPublishStatus_Err(t *testing.T) {
+	if err := (&PublishStatus{State: PublishSuccess}).Err(); err != nil {
+		t.Errorf("success Err() = %v", err)
+	}
+	if err := (&PublishStatus{State: PublishPublishing}).Err(); err != nil {
+		t.Errorf("publishing Err() = %v", err)
+	}
+
+	err := (&PublishStatus{PublishID: "p1", State: PublishOriginalFailed, FailIndex: []int{2}}).Err()
+	var publishErr *PublishError
+	if !errors.As(err, &publishErr) {
+		t.Fatalf("Err() = %v, want *PublishError", err)
+	}
+	if publishErr.ErrorCode() != "PUBLISH_ORIGINAL_CHECK_FAILED" {
+		t.Errorf("ErrorCode() = %s", publishErr.ErrorCode())
+	}
+	if details := publishErr.ErrorDetails(); details["publish_id"] != "p1" || details["state"] != "original_check_failed" {
+		t.Errorf("ErrorDetails() = %v", details)
+	}
+	if PublishState(9).String() != "unknown_9" || !PublishState(9).Terminal() {
+		t.Error("unknown state should be terminal")
+	}
+}
+
+func TestGetPublishStatus_MissingState(t *testing.T) {
+	for name, data := range map[string]any{
+		"missing": map[string]any{"publish_id": "p1"},
+		"null":    map[string]any{"publish_id": "p1", "publish_status": nil},
+		"no data": nil,
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]any{"code": 0}
+			if data != nil {
+				body["data"] = data
+			}
+			json.NewEncoder(w).Encode(body)
+		}))
+		client := NewClient(server.URL, "test-app-id", "test-app-secret", "test-api-key")
+		// 缺少状态不能按零值 (PublishSuccess) 当作发布成功
+		if resp, err := client.GetPublishStatus("p1"); err == nil {
+			t.Errorf("%s: GetPublishStatus() = %+v, want error", name, resp.Data)
+		}
+		server.Close()
+	}
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy 请求重试策略
+//
+// 网络错误、RetryableStatuses 中的 HTTP 状态码以及 RetryableCodes 中的
+// 业务 code 会触发重试，重试间隔按指数退避计算并叠加随机抖动。
+//
+// 幂等请求（转换、查询、更新、删除草稿）按上述条件重试。创建草稿、上传素材和
+// 提交发布只在服务端确定未处理请求时重试：连接建立前失败（请求未发出），
+// 或返回 429、503（且在 RetryableStatuses 中）；其余失败时服务端可能已处理
+// 请求，重试会产生重复的草稿或素材。
+type RetryPolicy struct {
+	// MaxAttempts 最大尝试次数（含首次请求），小于等于 1 表示不重试
+	MaxAttempts int
+	// BaseDelay 首次重试前的等待时间，之后每次翻倍
+	BaseDelay time.Duration
+	// MaxDelay 单次等待时间上限
+	MaxDelay time.Duration
+	// Jitter 抖动比例 [0, 1]，实际等待时间在 [delay*(1-Jitter), delay] 之间
+	Jitter float64
+	// RetryableStatuses 可重试的 HTTP 状态码
+	RetryableStatuses []int
+	// RetryableCodes 可重试的业务 code
+	RetryableCodes []int
+	// HonorRetryAfter 为 true 时优先使用响应中的 Retry-After 作为等待时间（不超过 MaxDelay）
+	HonorRetryAfter bool
+
+	// unsentOnly 只重试服务端未处理的请求，见 unsent
+	unsentOnly bool
+}
+
+// DefaultRetryPolicy 返回默认重试策略
+//
+// 默认只尝试一次；设置 MaxAttempts 后对网关错误、限流和微信
+// 系统繁忙 (-1) 进行重试。
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 1,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.2,
+		RetryableStatuses: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableCodes:  []int{-1},
+		HonorRetryAfter: true,
+	}
+}
+
+// SetRetryPolicy 设置请求重试策略
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// RetryPolicy 返回当前重试策略
+func (c *Client) RetryPolicy() RetryPolicy {
+	return c.retry
+}
+
+// attempts 返回最大尝试次数
+func (p RetryPolicy) attempts() int {
+	return max(p.MaxAttempts, 1)
+}
+
+// unsent 返回只重试服务端未处理请求的策略，用于非幂等请求
+//
+// 请求未发出时的网络错误和 429、503 响应可以重试，业务 code 不重试。
+func (p RetryPolicy) unsent() RetryPolicy {
+	p.unsentOnly = true
+	return p
+}
+
+// isRetryableError 判断网络错误是否可重试
+func (p RetryPolicy) isRetryableError(err error) bool {
+	var notSent *notSentError
+	return !p.unsentOnly || errors.As(err, &notSent)
+}
+
+// isRetryableStatus 判断 HTTP 状态码是否可重试
+func (p RetryPolicy) isRetryableStatus(status int) bool {
+	if p.unsentOnly && status != http.StatusTooManyRequests && status != http.StatusServiceUnavailable {
+		return false
+	}
+	return slices.Contains(p.RetryableStatuses, status)
+}
+
+// isRetryableCode 判断业务 code 是否可重试
+func (p RetryPolicy) isRetryableCode(code int) bool {
+	return !p.unsentOnly && code != 0 && slices.Contains(p.RetryableCodes, code)
+}
+
+// notSentError 连接建立前失败，请求未发出
+type notSentError struct {
+	err error
+}
+
+func (e *notSentError) Error() string { return e.err.Error() }
+func (e *notSentError) Unwrap() error { return e.err }
+
+// backoff 计算第 attempt 次失败后的等待时间
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if p.HonorRetryAfter && retryAfter > 0 {
+		if p.MaxDelay > 0 {
+			return min(retryAfter, p.MaxDelay)
+		}
+		return retryAfter
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 && delay > 0 {
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+	return delay
+}
+
+// businessCode 从响应体中提取业务 code，无法解析时返回 0
+func businessCode(data []byte) int {
+	var probe struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return 0
+	}
+	return probe.Code
+}
+
+// parseRetryAfter 解析 Retry-After 响应头（秒数或 HTTP 日期）
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleepContext 等待 d，ctx 取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRetryTestClient 创建记录等待时间且不真正休眠的客户端
+func newRetryTestClient(baseURL string, policy RetryPolicy) (*Client, *[]time.Duration) {
+	client := NewClient(baseURL, "test-appid", "test-secret", "test-key")
+	client.SetRetryPolicy(policy)
+	var waits []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return client, &waits
+}
+
+func TestDoRequest_NoRetryByDefault(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, _ := newRetryTestClient(server.URL, DefaultRetryPolicy())
+
+	if _, err := client.ArticleDraft(&ArticleDraftRequest{Markdown: "# Test"}); err == nil {
+		t.Fatal("ArticleDraft() should fail on 503")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestDoRequest_RetryOnStatus(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]interface{}{"html": "<p>ok</p>"},
+		})
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 3
+	policy.Jitter = 0
+	client, waits := newRetryTestClient(server.URL, policy)
+
+	resp, err := client.Convert(&ConvertRequest{Markdown: "# Test"})
+	if err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	if resp.Data.HTML != "<p>ok</p>" {
+		t.Errorf("HTML = %s, want <p>ok</p>", resp.Data.HTML)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+	want := []time.Duration{500 * time.Millisecond, time.Second}
+	if len(*waits) != len(want) || (*waits)[0] != want[0] || (*waits)[1] != want[1] {
+		t.Errorf("waits = %v, want %v", *waits, want)
+	}
+}
+
+func TestDoRequest_NonRetryableStatus(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 3
+	client, _ := newRetryTestClient(server.URL, policy)
+
+	if _, err := client.Convert(&ConvertRequest{Markdown: "# Test"}); err == nil {
+		t.Fatal("Convert() should fail on 401")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestDoRequest_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0})
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 2
+	client, waits := newRetryTestClient(server.URL, policy)
+
+	if _, err := client.Convert(&ConvertRequest{Markdown: "# Test"}); err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Errorf("waits = %v, want [7s]", *waits)
+	}
+}
+
+func TestDoRequest_RetryableBusinessCode(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": -1,
+			"msg":  "system error",
+		})
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 3
+	client, _ := newRetryTestClient(server.URL, policy)
+
+	resp, err := client.Convert(&ConvertRequest{Markdown: "# Test"})
+	// 重试耗尽后业务错误仍交给上层处理
+	if err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	if resp.Code != -1 {
+		t.Errorf("Code = %d, want -1", resp.Code)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+}
+
+func TestNonIdempotent_RetryOnlyUnprocessed(t *testing.T) {
+	var calls atomic.Int32
+	var status atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 3
+	client, waits := newRetryTestClient(server.URL, policy)
+
+	tests := map[string]func() error{
+		"ArticleDraft": func() error {
+			_, err := client.ArticleDraft(&ArticleDraftRequest{Markdown: "# Test"})
+			return err
+		},
+		"NewspicDraft": func() error {
+			_, err := client.NewspicDraft(&NewspicDraftRequest{Title: "t"})
+			return err
+		},
+		"BatchUpload": func() error {
+			_, err := client.BatchUpload(&BatchUploadRequest{})
+			return err
+		},
+		"UploadImage": func() error {
+			_, err := client.UploadImage(UploadFile{Name: "a.png", Data: []byte("png")})
+			return err
+		},
+		"Publish": func() error {
+			_, err := client.Publish("media_1")
+			return err
+		},
+	}
+	// 502 时请求可能已被服务端处理，不能重试；429、503 表示服务端未处理，可以重试
+	for _, tt := range []struct {
+		status int
+		calls  int32
+	}{
+		{http.StatusBadGateway, 1},
+		{http.StatusServiceUnavailable, 3},
+		{http.StatusTooManyRequests, 3},
+	} {
+		status.Store(int32(tt.status))
+		for name, call := range tests {
+			calls.Store(0)
+			if err := call(); err == nil {
+				t.Errorf("%s() should fail on %d", name, tt.status)
+			}
+			if calls.Load() != tt.calls {
+				t.Errorf("%s() on %d calls = %d, want %d", name, tt.status, calls.Load(), tt.calls)
+			}
+		}
+	}
+
+	// 连接失败时请求未发出，可以重试
+	server.Close()
+	*waits = nil
+	if _, err := client.Publish("media_1"); err == nil {
+		t.Fatal("Publish() should fail when the server is down")
+	}
+	if len(*waits) != 2 {
+		t.Errorf("waits after connection errors = %v, want 2 retries", *waits)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay: time.Second,
+		MaxDelay:  5 * time.Second,
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.attempt, 0); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+
+	// Retry-After 不超过 MaxDelay
+	policy.HonorRetryAfter = true
+	if got := policy.backoff(1, time.Hour); got != 5*time.Second {
+		t.Errorf("backoff(retryAfter=1h) = %v, want 5s", got)
+	}
+	if got := policy.backoff(1, 2*time.Second); got != 2*time.Second {
+		t.Errorf("backoff(retryAfter=2s) = %v, want 2s", got)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		got := policy.backoff(3, 0)
+		if got < 2*time.Second || got > 4*time.Second {
+			t.Fatalf("backoff with jitter = %v, want within [2s, 4s]", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("3"); got != 3*time.Second {
+		t.Errorf("parseRetryAfter(\"3\") = %v, want 3s", got)
+	}
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("parseRetryAfter(\"\") = %v, want 0", got)
+	}
+	if got := parseRetryAfter("invalid"); got != 0 {
+		t.Errorf("parseRetryAfter(\"invalid\") = %v, want 0", got)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 0 || got > time.Minute {
+		t.Errorf("parseRetryAfter(date) = %v, want within (0, 1m]", got)
+	}
+}
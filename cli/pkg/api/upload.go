@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+)
+
+// UploadFile 待上传的本地文件
+type UploadFile struct {
+	// Name 本地文件路径或文件名；请求中只发送文件名部分（服务端据此识别
+	// 扩展名），服务端未返回 Source 时作为结果的 Source
+	Name string
+	// Data 文件内容
+	Data []byte
+}
+
+// UploadImageResponse 单张图片上传响应
+type UploadImageResponse struct {
+	ResponseStatus
+	Data UploadResult `json:"data,omitempty"`
+}
+
+// UploadImage 以 multipart 方式上传单张本地图片到微信素材库
+func (c *Client) UploadImage(file UploadFile) (*UploadImageResponse, error) {
+	return c.UploadImageContext(context.Background(), file)
+}
+
+// UploadImageContext 上传单张本地图片，请求随 ctx 取消或超时而中止
+//
+// 返回的 Data.URL 为微信 CDN 地址，可直接用于文章正文和封面。
+func (c *Client) UploadImageContext(ctx context.Context, file UploadFile) (*UploadImageResponse, error) {
+	endpoint := "/api/v1/upload-image"
+	contentType, payload, err := buildMultipart("file", []UploadFile{file})
+	if err != nil {
+		return nil, err
+	}
+	var resp UploadImageResponse
+	if err := c.do(ctx, c.retry.unsent(), "POST", endpoint, contentType, payload, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BatchUploadFiles 以 multipart 方式批量上传本地图片到微信素材库
+func (c *Client) BatchUploadFiles(files []UploadFile) (*BatchUploadResponse, error) {
+	return c.BatchUploadFilesContext(context.Background(), files)
+}
+
+// BatchUploadFilesContext 批量上传本地图片，请求随 ctx 取消或超时而中止
+//
+// 结果按上传顺序返回；服务端未返回的 Source、Size、MimeType 由本地文件信息补全。
+func (c *Client) BatchUploadFilesContext(ctx context.Context, files []UploadFile) (*BatchUploadResponse, error) {
+	endpoint := "/api/v1/batch-upload-files"
+	contentType, payload, err := buildMultipart("files", files)
+	if err != nil {
+		return nil, err
+	}
+	var resp BatchUploadResponse
+	if err := c.do(ctx, c.retry.unsent(), "POST", endpoint, contentType, payload, &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Data.Results) == len(files) {
+		for i := range resp.Data.Results {
+			r := &resp.Data.Results[i]
+			if r.Source == "" {
+				r.Source = files[i].Name
+			}
+			if r.Size == 0 {
+				r.Size = int64(len(files[i].Data))
+			}
+			if r.MimeType == "" {
+				r.MimeType = DetectContentType(files[i].Name, files[i].Data)
+			}
+		}
+	}
+	return &resp, nil
+}
+
+// buildMultipart 构建 multipart/form-data 请求体，每个文件使用同一字段名
+func buildMultipart(field string, files []UploadFile) (string, []byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, f := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			field, escapeQuotes(filepath.Base(f.Name))))
+		header.Set("Content-Type", DetectContentType(f.Name, f.Data))
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return "", nil, fmt.Errorf("构建上传请求失败: %w", err)
+		}
+		if _, err := part.Write(f.Data); err != nil {
+			return "", nil, fmt.Errorf("构建上传请求失败: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", nil, fmt.Errorf("构建上传请求失败: %w", err)
+	}
+	return writer.FormDataContentType(), buf.Bytes(), nil
+}
+
+// DetectContentType 检测文件 MIME 类型，优先根据内容识别，其次根据扩展名
+func DetectContentType(name string, data []byte) string {
+	contentType := http.DetectContentType(data)
+	if contentType == "application/octet-stream" || strings.HasPrefix(contentType, "text/plain") {
+		if byExt := mime.TypeByExtension(strings.ToLower(filepath.Ext(name))); byExt != "" {
+			return byExt
+		}
+	}
+	return contentType
+}
+
+// quoteEscaper 转义 Content-Disposition 中的引号和反斜杠
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
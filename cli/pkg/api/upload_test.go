@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// pngHeader PNG 文件头，用于 MIME 类型检测
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestUploadImage_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/upload-image" {
+			t.Errorf("Path = %s, want /api/v1/upload-image", r.URL.Path)
+		}
+		if r.Header.Get("Md2wechat-API-Key") != "test-key" {
+			t.Errorf("Md2wechat-API-Key header missing")
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile() failed: %v", err)
+		}
+		defer file.Close()
+		if header.Filename != "a.png" {
+			t.Errorf("Filename = %s, want a.png", header.Filename)
+		}
+		if ct := header.Header.Get("Content-Type"); ct != "image/png" {
+			t.Errorf("Content-Type = %s, want image/png", ct)
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != string(pngHeader) {
+			t.Errorf("file content mismatch")
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]interface{}{
+				"url":      "https://mmbiz.qpic.cn/a.png",
+				"media_id": "media_1",
+				"success":  true,
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-appid", "test-secret", "test-key")
+
+	resp, err := client.UploadImage(UploadFile{Name: "img/a.png", Data: pngHeader})
+	if err != nil {
+		t.Fatalf("UploadImage() failed: %v", err)
+	}
+	if resp.Data.URL != "https://mmbiz.qpic.cn/a.png" {
+		t.Errorf("URL = %s, want https://mmbiz.qpic.cn/a.png", resp.Data.URL)
+	}
+	if resp.Data.MediaID != "media_1" {
+		t.Errorf("MediaID = %s, want media_1", resp.Data.MediaID)
+	}
+}
+
+func TestBatchUploadFiles_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/batch-upload-files" {
+			t.Errorf("Path = %s, want /api/v1/batch-upload-files", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("ParseMultipartForm() failed: %v", err)
+		}
+		files := r.MultipartForm.File["files"]
+		if len(files) != 2 {
+			t.Fatalf("len(files) = %d, want 2", len(files))
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]interface{}{
+				"results": []map[string]interface{}{
+					{"url": "https://mmbiz.qpic.cn/a.png", "media_id": "media_1", "success": true},
+					{"success": false, "error": "upload failed", "reason": "invalid file type"},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-appid", "test-secret", "test-key")
+
+	resp, err := client.BatchUploadFiles([]UploadFile{
+		{Name: "assets/a.png", Data: pngHeader},
+		{Name: "assets/b.txt", Data: []byte("hello")},
+	})
+	if err != nil {
+		t.Fatalf("BatchUploadFiles() failed: %v", err)
+	}
+	if len(resp.Data.Results) != 2 {
+		t.Fatalf("len(Results) = %d, want 2", len(resp.Data.Results))
+	}
+
+	first := resp.Data.Results[0]
+	if first.Source != "assets/a.png" || first.Size != int64(len(pngHeader)) || first.MimeType != "image/png" {
+		t.Errorf("Results[0] = %+v, want source/size/mime filled", first)
+	}
+	second := resp.Data.Results[1]
+	if second.Success || second.Reason != "invalid file type" {
+		t.Errorf("Results[1] = %+v, want rejection reason", second)
+	}
+	if second.MimeType != "text/plain; charset=utf-8" {
+		t.Errorf("Results[1].MimeType = %s, want text/plain", second.MimeType)
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data []byte
+		want string
+	}{
+		{"PNG 内容", "a.bin", pngHeader, "image/png"},
+		{"JPEG 内容", "a", []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), "image/jpeg"},
+		{"按扩展名识别", "a.svg", []byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>"), "image/svg+xml"},
+		{"未知类型", "a", []byte{0x00, 0x01, 0x02}, "application/octet-stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectContentType(tt.file, tt.data); got != tt.want {
+				t.Errorf("DetectContentType(%q) = %s, want %s", tt.file, got, tt.want)
+			}
+		})
+	}
+}
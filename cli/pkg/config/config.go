@@ -2,7 +2,11 @@
 //
 // 配置文件位置: ~/.md2wx/config.yaml
 //
-// 支持的配置项:
+// 配置文件为 YAML 格式，按 wechat、api、defaults、upload、retry 分组，
+// 配置档案写在 profiles.<name> 下；仍可读取旧版 key=value 格式，
+// 保存时自动迁移为 YAML。
+//
+// 支持的配置项（扁平名称，用于 config set/get）:
 //   - wechat_appid: 微信 AppID
 //   - wechat_appsecret: 微信 AppSecret
 //   - api_key: Md2wechat API Key
@@ -10,8 +14,22 @@
 //   - default_theme: 默认主题名称
 //   - background_type: 默认背景类型
 //   - font_size: 默认字体大小
+//   - default_author / default_cover: 文章默认作者 / 封面
+//   - upload_local_images: 是否上传本地图片
+//   - upload_cache: 是否使用图片上传缓存
+//   - upload_cache_ttl: 缓存有效期（如 720h，为空表示不过期）
+//   - upload_cache_verify: 使用缓存前是否检查图片地址仍可访问
+//   - retry_max_attempts: 请求最大尝试次数（含首次）
+//   - retry_base_delay / retry_max_delay: 重试退避基础时长 / 上限
+//   - retry_jitter: 重试抖动比例 (0~1)
+//   - retry_statuses / retry_codes: 可重试的 HTTP 状态码 / 业务 code（逗号分隔）
+//   - retry_honor_retry_after: 是否遵循 Retry-After 响应头
+//   - secret_backend: 敏感配置的存储后端 (plain/file/keyring)
+//   - current_profile: 默认使用的配置档案
+//   - project_allow_secrets: 是否允许项目配置设置密钥和 api_base_url
+//   - profile.<name>.<key>: 配置档案中的账号和样式配置
 //
-// 配置优先级: 环境变量 > 配置文件 > 默认值
+// 配置优先级: 环境变量 > 项目配置（.md2wx.yaml）> 配置档案 > 配置文件 > 默认值
 package config
 
 import (
@@ -19,17 +37,64 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/internal/fsutil"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/secret"
 )
 
 // Config 应用配置
 type Config struct {
-	WechatAppID         string `yaml:"wechat_appid" json:"wechat_appid"`
-	WechatAppSecret   string `yaml:"wechat_appsecret" json:"wechat_appsecret"`
-	APIKey              string `yaml:"api_key" json:"api_key"`
-	APIBaseURL          string `yaml:"api_base_url" json:"api_base_url"`
-	DefaultTheme       string `yaml:"default_theme" json:"default_theme"`
+	WechatAppID           string `yaml:"wechat_appid" json:"wechat_appid"`
+	WechatAppSecret       string `yaml:"wechat_appsecret" json:"wechat_appsecret"`
+	APIKey                string `yaml:"api_key" json:"api_key"`
+	APIBaseURL            string `yaml:"api_base_url" json:"api_base_url"`
+	DefaultTheme          string `yaml:"default_theme" json:"default_theme"`
 	DefaultBackgroundType string `yaml:"background_type" json:"background_type"`
-	DefaultFontSize     string `yaml:"font_size" json:"font_size"`
+	DefaultFontSize       string `yaml:"font_size" json:"font_size"`
+	// DefaultAuthor / DefaultCover 文章默认作者和封面（低于命令行、清单和 front matter）
+	DefaultAuthor string `yaml:"default_author" json:"default_author,omitempty"`
+	DefaultCover  string `yaml:"default_cover" json:"default_cover,omitempty"`
+	// UploadLocalImages 是否上传本地图片（--upload-local-images 的默认值），为空表示 true
+	UploadLocalImages string `yaml:"upload_local_images" json:"upload_local_images,omitempty"`
+	// UploadCache 是否使用图片上传缓存，为空表示 true
+	UploadCache string `yaml:"upload_cache" json:"upload_cache,omitempty"`
+	// UploadCacheTTL 缓存有效期，为空表示不过期
+	UploadCacheTTL string `yaml:"upload_cache_ttl" json:"upload_cache_ttl,omitempty"`
+	// UploadCacheVerify 使用缓存前是否检查图片地址，为空表示 false
+	UploadCacheVerify string `yaml:"upload_cache_verify" json:"upload_cache_verify,omitempty"`
+
+	// 重试策略（为空时使用 API 客户端默认值）
+	RetryMaxAttempts     string `yaml:"retry_max_attempts" json:"retry_max_attempts"`
+	RetryBaseDelay       string `yaml:"retry_base_delay" json:"retry_base_delay"`
+	RetryMaxDelay        string `yaml:"retry_max_delay" json:"retry_max_delay"`
+	RetryJitter          string `yaml:"retry_jitter" json:"retry_jitter"`
+	RetryStatuses        string `yaml:"retry_statuses" json:"retry_statuses"`
+	RetryCodes           string `yaml:"retry_codes" json:"retry_codes"`
+	RetryHonorRetryAfter string `yaml:"retry_honor_retry_after" json:"retry_honor_retry_after"`
+
+	// SecretBackend 敏感配置的存储后端，为空表示明文保存在配置文件中
+	SecretBackend string `yaml:"secret_backend" json:"secret_backend,omitempty"`
+
+	// CurrentProfile 默认使用的配置档案（config profiles use 设置）
+	CurrentProfile string `yaml:"current_profile" json:"current_profile,omitempty"`
+	// Profiles 命名配置档案，覆盖顶层的账号和样式配置
+	Profiles map[string]*Profile `yaml:"profiles" json:"profiles,omitempty"`
+	// Profile 本次生效的配置档案，default 表示只使用顶层配置
+	Profile string `yaml:"-" json:"-"`
+
+	// ProjectAllowSecrets 是否允许项目配置（.md2wx.yaml）设置密钥和 api_base_url，只在用户配置中生效
+	ProjectAllowSecrets string `yaml:"project_allow_secrets" json:"project_allow_secrets,omitempty"`
+	// ProjectPath 本次合并的项目配置文件，没有时为空
+	ProjectPath string `yaml:"-" json:"-"`
+
+	// origins 配置项的来源，键为扁平名称
+	origins map[string]string
+	// secrets 已打开的密钥后端，对应 secretsName；同一份配置只打开（解密）一次
+	secrets     secret.Backend
+	secretsName string
 }
 
 const (
@@ -67,8 +132,112 @@ func GetConfigDir() string {
 	return configDir
 }
 
-// Load 从配置文件加载配置
+// LoadOptions 加载配置时使用的配置档案和项目目录
+type LoadOptions struct {
+	// Profile 配置档案，为空时按 MD2WX_PROFILE > current_profile 选择
+	Profile string
+	// ProjectDir 查找项目配置的起始目录，为空时使用工作目录
+	ProjectDir string
+}
+
+// Load 从配置文件加载配置，并应用当前配置档案、项目配置和环境变量
+//
+// 优先级: 环境变量 > 项目配置（.md2wx.yaml）> 配置档案 > 用户配置 > 默认值。
+// 项目配置中的 current_profile 用于选择档案，因此先于档案合并。
+// 档案和项目目录取 SetProfile 和 SetProjectDir 的设置。
 func Load() (*Config, error) {
+	return LoadWith(LoadOptions{Profile: selectedProfile, ProjectDir: projectDir})
+}
+
+// LoadWith 按 opts 指定的配置档案和项目目录加载配置，其余同 Load
+//
+// 不读取也不修改 SetProfile 和 SetProjectDir 的设置，可以在同一进程中
+// 为不同档案和目录分别加载配置（如调度器执行的每个任务）。
+func LoadWith(opts LoadOptions) (*Config, error) {
+	cfg, err := read()
+	if err != nil {
+		return nil, err
+	}
+	project, err := cfg.loadProject(opts.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	if project != nil {
+		cfg.ProjectPath = project.path
+		cfg.setOrigin("project_config", "search")
+		if os.Getenv("MD2WX_PROJECT_CONFIG") != "" {
+			cfg.setOrigin("project_config", "env MD2WX_PROJECT_CONFIG")
+		}
+	}
+	isProfileKey := func(key string) bool { return key == "current_profile" }
+	project.apply(cfg, isProfileKey)
+	if err := cfg.applyProfile(opts.Profile); err != nil {
+		return nil, err
+	}
+	project.apply(cfg, func(key string) bool { return !isProfileKey(key) })
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
+	// 环境变量覆盖（优先级更高）
+	for _, env := range envOverrides {
+		if v := os.Getenv(env[1]); v != "" {
+			*cfg.ptr(env[0]) = v
+			cfg.setOrigin(env[0], "env "+env[1])
+		}
+	}
+
+	return cfg, nil
+}
+
+// LoadUser 只加载用户配置文件和选择的配置档案（解析密钥引用），不合并项目配置和环境变量
+//
+// 用于以用户配置的现值为默认值修改配置（如 init），档案选择规则与 Set 相同。
+func LoadUser() (*Config, error) {
+	cfg, err := read()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.applyProfile(selectedProfile); err != nil {
+		return nil, err
+	}
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// envOverrides 可由环境变量覆盖的配置项
+var envOverrides = [][2]string{
+	{"wechat_appid", "MD2WX_WECHAT_APPID"},
+	{"wechat_appsecret", "MD2WX_WECHAT_APPSECRET"},
+	{"api_key", "MD2WX_API_KEY"},
+	{"api_base_url", "MD2WX_API_BASE_URL"},
+	{"default_theme", "MD2WX_DEFAULT_THEME"},
+	{"background_type", "MD2WX_BACKGROUND_TYPE"},
+	{"font_size", "MD2WX_FONT_SIZE"},
+	{"retry_max_attempts", "MD2WX_RETRY_MAX_ATTEMPTS"},
+}
+
+// Origin 返回配置项的来源：文件路径和行号、env 变量名，未设置时为 default
+func (c *Config) Origin(key string) string {
+	if origin, ok := c.origins[key]; ok {
+		return origin
+	}
+	return "default"
+}
+
+func (c *Config) setOrigin(key, origin string) {
+	if c.origins == nil {
+		c.origins = make(map[string]string)
+	}
+	c.origins[key] = origin
+}
+
+// read 读取配置文件原始内容，不应用配置档案和环境变量
+//
+// 同时支持 YAML 和旧版 key=value 格式，未知配置项忽略（config validate 会报告）。
+func read() (*Config, error) {
 	cfg := &Config{
 		APIBaseURL: DefaultAPIBaseURL,
 	}
@@ -83,113 +252,45 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("读取配置文件失败: %w", err)
 	}
+	return parseUserConfig(data)
+}
 
-	// 简单的 key=value 解析（为了保持轻量，不依赖 yaml 库）
-	// 格式: key=value
-	lines := splitLines(data)
-	for _, line := range lines {
-		line = trimSpace(line)
-		if line == "" || line[0] == '#' {
-			continue
-		}
-		key, value, ok := parseKeyValue(line)
-		if !ok {
-			continue
-		}
-		switch key {
-		case "wechat_appid":
-			cfg.WechatAppID = value
-		case "wechat_appsecret":
-			cfg.WechatAppSecret = value
-		case "api_key":
-			cfg.APIKey = value
-		case "api_base_url":
-			cfg.APIBaseURL = value
-		case "default_theme":
-			cfg.DefaultTheme = value
-		case "background_type":
-			cfg.DefaultBackgroundType = value
-		case "font_size":
-			cfg.DefaultFontSize = value
-		}
-	}
-
-	// 环境变量覆盖（优先级更高）
-	if v := os.Getenv("MD2WX_WECHAT_APPID"); v != "" {
-		cfg.WechatAppID = v
-	}
-	if v := os.Getenv("MD2WX_WECHAT_APPSECRET"); v != "" {
-		cfg.WechatAppSecret = v
-	}
-	if v := os.Getenv("MD2WX_API_KEY"); v != "" {
-		cfg.APIKey = v
-	}
-	if v := os.Getenv("MD2WX_API_BASE_URL"); v != "" {
-		cfg.APIBaseURL = v
-	}
-	if v := os.Getenv("MD2WX_DEFAULT_THEME"); v != "" {
-		cfg.DefaultTheme = v
+// parseUserConfig 解析用户配置文件内容，来源记录为配置文件的行号
+func parseUserConfig(data []byte) (*Config, error) {
+	cfg := &Config{
+		APIBaseURL: DefaultAPIBaseURL,
 	}
-	if v := os.Getenv("MD2WX_BACKGROUND_TYPE"); v != "" {
-		cfg.DefaultBackgroundType = v
+	settings, _, err := parseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件 %s 失败: %w", configPath, err)
 	}
-	if v := os.Getenv("MD2WX_FONT_SIZE"); v != "" {
-		cfg.DefaultFontSize = v
+	for _, s := range settings {
+		if f := cfg.ptr(s.key); f != nil {
+			*f = s.value
+			cfg.setOrigin(s.key, fmt.Sprintf("%s:%d", configPath, s.line))
+		}
 	}
 
 	return cfg, nil
 }
 
-// Save 保存配置到文件
+// Save 以 YAML 格式保存配置到文件
+//
+// 原文件为旧版 key=value 格式时，先备份为 config.yaml.bak。
 func Save(cfg *Config) error {
 	// 确保配置目录存在
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("创建配置目录失败: %w", err)
 	}
 
-	// 构建配置内容
-	var content string
-	content += "# md2wx 配置文件\n"
-	content += "# 可通过环境变量覆盖（优先级更高）\n"
-	content += "#\n"
-	content += "# 配置项说明：\n"
-	content += "#   wechat_appid    - 微信公众号 AppID（必填）\n"
-	content += "#   wechat_appsecret - 微信公众号 AppSecret（必填）\n"
-	content += "#   api_key         - md2wx API Key（必填，获取地址：https://www.md2wechat.cn/api-docs）\n"
-	content += "#   api_base_url    - API 基础 URL（可选，默认：http://111.231.20.31:8080）\n"
-	content += "#   default_theme   - 默认主题（可选）\n"
-	content += "#     内置主题: default, bytedance, chinese, apple, sports, cyber\n"
-	content += "#     模板主题: minimal-gold, focus-blue, elegant-red, bold-navy 等 32 种\n"
-	content += "#   background_type - 默认背景类型（可选）\n"
-	content += "#     可选值: none（无）, default（默认）, grid（网格）\n"
-	content += "#   font_size       - 默认字体大小（可选）\n"
-	content += "#     可选值: small（小）, medium（中）, large（大）\n"
-	content += "#\n\n"
-
-	if cfg.WechatAppID != "" {
-		content += fmt.Sprintf("wechat_appid=%s\n", cfg.WechatAppID)
-	}
-	if cfg.WechatAppSecret != "" {
-		content += fmt.Sprintf("wechat_appsecret=%s\n", cfg.WechatAppSecret)
-	}
-	if cfg.APIKey != "" {
-		content += fmt.Sprintf("api_key=%s\n", cfg.APIKey)
-	}
-	if cfg.APIBaseURL != "" && cfg.APIBaseURL != DefaultAPIBaseURL {
-		content += fmt.Sprintf("api_base_url=%s\n", cfg.APIBaseURL)
-	}
-	if cfg.DefaultTheme != "" && cfg.DefaultTheme != "default" {
-		content += fmt.Sprintf("default_theme=%s\n", cfg.DefaultTheme)
-	}
-	if cfg.DefaultBackgroundType != "" && cfg.DefaultBackgroundType != "none" {
-		content += fmt.Sprintf("background_type=%s\n", cfg.DefaultBackgroundType)
-	}
-	if cfg.DefaultFontSize != "" && cfg.DefaultFontSize != "medium" {
-		content += fmt.Sprintf("font_size=%s\n", cfg.DefaultFontSize)
+	if old, err := os.ReadFile(configPath); err == nil && isLegacyFormat(old) {
+		if err := fsutil.WriteFile(configPath+".bak", old, 0600); err != nil {
+			return fmt.Errorf("备份旧配置文件失败: %w", err)
+		}
 	}
 
-	// 写入文件
-	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+	// 原子写入文件，写入中途退出不会留下不完整的配置
+	if err := fsutil.WriteFile(configPath, []byte(marshalYAML(cfg)), 0600); err != nil {
 		return fmt.Errorf("写入配置文件失败: %w", err)
 	}
 
@@ -197,30 +298,112 @@ func Save(cfg *Config) error {
 }
 
 // Set 设置单个配置项
+//
+// 选择了配置档案（--profile、MD2WX_PROFILE 或 current_profile）时，
+// 账号和样式配置写入该档案，不存在的档案会自动创建；其余配置项写入顶层。
+// 设置了 secret_backend 时，wechat_appsecret 和 api_key 保存到密钥后端。
 func Set(key, value string) error {
-	cfg, err := Load()
+	return SetAll([][2]string{{key, value}})
+}
+
+// SetAll 按顺序设置多个配置项（键值对），最后只写入一次配置文件
+//
+// 规则与 Set 相同，敏感配置在 file 后端中也只加密写入一次。出错时不写入配置文件。
+func SetAll(items [][2]string) error {
+	cfg, err := read()
 	if err != nil {
 		return err
 	}
 
-	switch key {
-	case "wechat-appid", "wechat_appid":
-		cfg.WechatAppID = value
-	case "wechat-appsecret", "wechat_appsecret":
-		cfg.WechatAppSecret = value
-	case "api-key", "api_key":
-		cfg.APIKey = value
-	case "api-base", "api_base_url":
-		cfg.APIBaseURL = value
-	case "default-theme", "default_theme":
-		cfg.DefaultTheme = value
-	case "background-type", "background_type":
-		cfg.DefaultBackgroundType = value
-	case "font-size", "font_size":
-		cfg.DefaultFontSize = value
-	default:
+	var secrets []secretField
+	for _, item := range items {
+		key, value := item[0], item[1]
+		if key == "secret-backend" || key == "secret_backend" {
+			if err := cfg.setSecretBackend(value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		spec, ok := lookupKey(key)
+		if !ok {
+			return fmt.Errorf("未知的配置项: %s", key)
+		}
+		if spec.name == "current_profile" {
+			return fmt.Errorf("请使用 'md2wx config profiles use <name>' 切换配置档案")
+		}
+		if err := spec.validateValue(value); err != nil {
+			return fmt.Errorf("%s: %w", spec.name, err)
+		}
+
+		name, err := cfg.resolveProfile()
+		if err != nil {
+			return err
+		}
+		field := spec.name
+		if name != DefaultProfile && spec.profile {
+			field = "profile." + name + "." + spec.name
+		}
+		f := cfg.ptr(field)
+		*f = value
+		if IsSensitive(spec.name) {
+			secrets = append(secrets, secretField{field, f})
+		}
+	}
+	if _, err := cfg.storeSecrets(secrets); err != nil {
+		return err
+	}
+
+	return Save(cfg)
+}
+
+// Unset 删除单个配置项，恢复为默认值
+//
+// 档案选择规则与 Set 相同；敏感配置同时从密钥后端删除，unset current_profile
+// 恢复为 default 档案，unset secret_backend 将密钥迁回明文。配置项未设置时返回错误。
+func Unset(key string) error {
+	cfg, err := read()
+	if err != nil {
+		return err
+	}
+
+	spec, ok := lookupKey(key)
+	if !ok {
 		return fmt.Errorf("未知的配置项: %s", key)
 	}
+	field := spec.name
+	if spec.profile {
+		name, err := cfg.resolveProfile()
+		if err != nil {
+			return err
+		}
+		if name != DefaultProfile {
+			if _, ok := cfg.Profiles[name]; !ok {
+				return profileNotFound(name)
+			}
+			field = "profile." + name + "." + spec.name
+		}
+	}
+
+	f := cfg.ptr(field)
+	if *f == "" || (field == "api_base_url" && *f == DefaultAPIBaseURL) {
+		return fmt.Errorf("%s 未设置", field)
+	}
+	if field == "secret_backend" {
+		if err := cfg.setSecretBackend(""); err != nil {
+			return err
+		}
+		return Save(cfg)
+	}
+	*f = ""
+	if field == "api_base_url" {
+		*f = DefaultAPIBaseURL
+	}
+	if IsSensitive(spec.name) {
+		if err := cfg.storeSecret(field, f); err != nil {
+			return err
+		}
+	}
 
 	return Save(cfg)
 }
@@ -250,6 +433,15 @@ func Get(key string) (string, error) {
 		return cfg.APIKey, nil
 	case "api-base", "api_base_url":
 		return cfg.APIBaseURL, nil
+	case "upload-local-images", "upload_local_images":
+		return strconv.FormatBool(cfg.UploadImages()), nil
+	case "upload-cache", "upload_cache":
+		return strconv.FormatBool(cfg.ImageCacheEnabled()), nil
+	case "secret-backend", "secret_backend":
+		if cfg.SecretBackend == "" {
+			return "plain", nil
+		}
+		return cfg.SecretBackend, nil
 	case "default-theme", "default_theme":
 		if cfg.DefaultTheme == "" {
 			return "default", nil
@@ -265,45 +457,193 @@ func Get(key string) (string, error) {
 			return "medium", nil
 		}
 		return cfg.DefaultFontSize, nil
+	case "retry-max-attempts", "retry_max_attempts",
+		"retry-base-delay", "retry_base_delay",
+		"retry-max-delay", "retry_max_delay",
+		"retry-jitter", "retry_jitter",
+		"retry-statuses", "retry_statuses",
+		"retry-codes", "retry_codes",
+		"retry-honor-retry-after", "retry_honor_retry_after":
+		name := strings.ReplaceAll(key, "-", "_")
+		value := *retryField(cfg, name)
+		if value == "" {
+			return "", fmt.Errorf("%s 未配置（使用默认值）", name)
+		}
+		return value, nil
 	default:
-		return "", fmt.Errorf("未知的配置项: %s", key)
+		spec, ok := lookupKey(key)
+		if !ok {
+			return "", fmt.Errorf("未知的配置项: %s", key)
+		}
+		value := *cfg.ptr(spec.name)
+		if value == "" {
+			return "", fmt.Errorf("%s 未配置", spec.name)
+		}
+		return value, nil
 	}
 }
 
-// List 列出所有配置项
-func List() (map[string]string, error) {
+// Entry 生效的配置项
+type Entry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// Origin 来源：文件路径和行号、env 变量名、--profile 或 default
+	Origin string `json:"origin"`
+}
+
+// ListEntries 按固定顺序列出生效的配置项及来源，敏感信息已掩码，未设置且无默认值的配置项不列出
+func ListEntries() ([]Entry, error) {
 	cfg, err := Load()
 	if err != nil {
 		return nil, err
 	}
 
-	result := make(map[string]string)
-	if cfg.WechatAppID != "" {
-		result["wechat_appid"] = maskSensitive(cfg.WechatAppID)
+	entries := []Entry{{Key: "profile", Value: cfg.Profile, Origin: cfg.profileOrigin()}}
+	if cfg.ProjectPath != "" {
+		entries = append(entries, Entry{Key: "project_config", Value: cfg.ProjectPath, Origin: cfg.Origin("project_config")})
+	}
+	for _, spec := range keySpecs {
+		if spec.name == "current_profile" {
+			continue
+		}
+		value := *cfg.ptr(spec.name)
+		switch {
+		case value != "" && (IsSensitive(spec.name) || spec.name == "wechat_appid"):
+			value = maskSensitive(value)
+		case value != "":
+		case spec.def != "":
+			value = spec.def
+		case spec.name == "secret_backend":
+			value = "plain"
+		case spec.name == "upload_local_images":
+			value = "true"
+		default:
+			continue
+		}
+		entries = append(entries, Entry{Key: spec.name, Value: value, Origin: cfg.Origin(spec.name)})
+	}
+	return entries, nil
+}
+
+// List 列出所有配置项
+func List() (map[string]string, error) {
+	entries, err := ListEntries()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(entries))
+	for _, e := range entries {
+		result[e.Key] = e.Value
 	}
-	if cfg.WechatAppSecret != "" {
-		result["wechat_appsecret"] = maskSensitive(cfg.WechatAppSecret)
+	return result, nil
+}
+
+// UploadImages 是否上传 Markdown 中的本地图片，未配置时为 true
+func (c *Config) UploadImages() bool {
+	v, err := strconv.ParseBool(c.UploadLocalImages)
+	return err != nil || v
+}
+
+// ImageCacheEnabled 是否使用图片上传缓存，未配置时为 true
+func (c *Config) ImageCacheEnabled() bool {
+	v, err := strconv.ParseBool(c.UploadCache)
+	return err != nil || v
+}
+
+// ImageCacheTTL 返回图片上传缓存的有效期，未配置时为 0（不过期）
+func (c *Config) ImageCacheTTL() (time.Duration, error) {
+	if c.UploadCacheTTL == "" {
+		return 0, nil
 	}
-	if cfg.APIKey != "" {
-		result["api_key"] = maskSensitive(cfg.APIKey)
+	if err := validateDuration(c.UploadCacheTTL); err != nil {
+		return 0, fmt.Errorf("upload_cache_ttl %w", err)
 	}
-	result["api_base_url"] = cfg.APIBaseURL
-	if cfg.DefaultTheme != "" {
-		result["default_theme"] = cfg.DefaultTheme
-	} else {
-		result["default_theme"] = "default"
+	return time.ParseDuration(c.UploadCacheTTL)
+}
+
+// ImageCacheVerify 使用缓存前是否检查图片地址仍可访问，未配置时为 false
+func (c *Config) ImageCacheVerify() bool {
+	v, _ := strconv.ParseBool(c.UploadCacheVerify)
+	return v
+}
+
+// retryKeyValues 按固定顺序返回重试配置的键值对
+func retryKeyValues(cfg *Config) [][2]string {
+	return [][2]string{
+		{"retry_max_attempts", cfg.RetryMaxAttempts},
+		{"retry_base_delay", cfg.RetryBaseDelay},
+		{"retry_max_delay", cfg.RetryMaxDelay},
+		{"retry_jitter", cfg.RetryJitter},
+		{"retry_statuses", cfg.RetryStatuses},
+		{"retry_codes", cfg.RetryCodes},
+		{"retry_honor_retry_after", cfg.RetryHonorRetryAfter},
 	}
-	if cfg.DefaultBackgroundType != "" {
-		result["background_type"] = cfg.DefaultBackgroundType
-	} else {
-		result["background_type"] = "none"
+}
+
+// retryField 返回重试配置项对应的字段指针
+func retryField(cfg *Config, name string) *string {
+	switch name {
+	case "retry_max_attempts":
+		return &cfg.RetryMaxAttempts
+	case "retry_base_delay":
+		return &cfg.RetryBaseDelay
+	case "retry_max_delay":
+		return &cfg.RetryMaxDelay
+	case "retry_jitter":
+		return &cfg.RetryJitter
+	case "retry_statuses":
+		return &cfg.RetryStatuses
+	case "retry_codes":
+		return &cfg.RetryCodes
+	default:
+		return &cfg.RetryHonorRetryAfter
 	}
-	if cfg.DefaultFontSize != "" {
-		result["font_size"] = cfg.DefaultFontSize
-	} else {
-		result["font_size"] = "medium"
+}
+
+// validateRetryValue 校验重试配置项的值，空值表示恢复默认
+func validateRetryValue(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	switch name {
+	case "retry_max_attempts":
+		if n, err := strconv.Atoi(value); err != nil || n < 1 {
+			return fmt.Errorf("必须是大于等于 1 的整数: %s", value)
+		}
+	case "retry_base_delay", "retry_max_delay":
+		if d, err := time.ParseDuration(value); err != nil || d < 0 {
+			return fmt.Errorf("必须是有效时长（如 500ms、2s）: %s", value)
+		}
+	case "retry_jitter":
+		if f, err := strconv.ParseFloat(value, 64); err != nil || f < 0 || f > 1 {
+			return fmt.Errorf("必须是 0~1 之间的小数: %s", value)
+		}
+	case "retry_statuses", "retry_codes":
+		if _, err := ParseIntList(value); err != nil {
+			return fmt.Errorf("必须是逗号分隔的整数列表: %s", value)
+		}
+	case "retry_honor_retry_after":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("必须是 true 或 false: %s", value)
+		}
 	}
+	return nil
+}
 
+// ParseIntList 解析逗号分隔的整数列表，如 "429,502,503"
+func ParseIntList(s string) ([]int, error) {
+	var result []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
 	return result, nil
 }
 
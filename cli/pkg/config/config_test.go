@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoad_Default(t *testing.T) {
@@ -208,3 +209,123 @@ func TestList(t *testing.T) {
 		t.Error("api_key should be masked")
 	}
 }
+
+func TestSet_RetryKeys(t *testing.T) {
+	// 创建临时目录
+	tmpDir := t.TempDir()
+
+	// 设置临时主目录
+	oldHome := os.Getenv("HOME")
+	oldConfigDir := configDir
+	oldConfigPath := configPath
+	defer func() {
+		os.Setenv("HOME", oldHome)
+		configDir = oldConfigDir
+		configPath = oldConfigPath
+	}()
+	os.Setenv("HOME", tmpDir)
+	configDir = filepath.Join(tmpDir, ConfigDir)
+	configPath = filepath.Join(configDir, ConfigFile)
+
+	if err := Set("retry-max-attempts", "3"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if err := Set("retry_statuses", "429, 503"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	loadedCfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if loadedCfg.RetryMaxAttempts != "3" {
+		t.Errorf("RetryMaxAttempts = %s, want 3", loadedCfg.RetryMaxAttempts)
+	}
+	if loadedCfg.RetryStatuses != "429, 503" {
+		t.Errorf("RetryStatuses = %s, want '429, 503'", loadedCfg.RetryStatuses)
+	}
+
+	// 无效值应被拒绝
+	invalid := map[string]string{
+		"retry-max-attempts":      "0",
+		"retry-base-delay":        "abc",
+		"retry-jitter":            "1.5",
+		"retry-codes":             "-1,x",
+		"retry-honor-retry-after": "maybe",
+	}
+	for key, value := range invalid {
+		if err := Set(key, value); err == nil {
+			t.Errorf("Set(%q, %q) should fail", key, value)
+		}
+	}
+}
+
+func TestSet_UploadCacheKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	oldHome := os.Getenv("HOME")
+	oldConfigDir := configDir
+	oldConfigPath := configPath
+	defer func() {
+		os.Setenv("HOME", oldHome)
+		configDir = oldConfigDir
+		configPath = oldConfigPath
+	}()
+	os.Setenv("HOME", tmpDir)
+	configDir = filepath.Join(tmpDir, ConfigDir)
+	configPath = filepath.Join(configDir, ConfigFile)
+
+	// 未配置时的默认值
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.ImageCacheEnabled() || cfg.ImageCacheVerify() {
+		t.Errorf("defaults: enabled = %v, verify = %v, want true, false", cfg.ImageCacheEnabled(), cfg.ImageCacheVerify())
+	}
+	if ttl, err := cfg.ImageCacheTTL(); err != nil || ttl != 0 {
+		t.Errorf("ImageCacheTTL() = %v, %v, want 0", ttl, err)
+	}
+
+	for key, value := range map[string]string{
+		"upload-cache":        "false",
+		"upload.cache_ttl":    "720h",
+		"upload_cache_verify": "true",
+	} {
+		if err := Set(key, value); err != nil {
+			t.Fatalf("Set(%q) failed: %v", key, err)
+		}
+	}
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.ImageCacheEnabled() || !cfg.ImageCacheVerify() {
+		t.Errorf("enabled = %v, verify = %v, want false, true", cfg.ImageCacheEnabled(), cfg.ImageCacheVerify())
+	}
+	if ttl, err := cfg.ImageCacheTTL(); err != nil || ttl != 720*time.Hour {
+		t.Errorf("ImageCacheTTL() = %v, %v, want 720h", ttl, err)
+	}
+
+	for _, value := range []string{"abc", "-1h"} {
+		if err := Set("upload_cache_ttl", value); err == nil {
+			t.Errorf("Set(upload_cache_ttl, %q) should fail", value)
+		}
+	}
+}
+
+func TestParseIntList(t *testing.T) {
+	got, err := ParseIntList("429, 502,,-1")
+	if err != nil {
+		t.Fatalf("ParseIntList() failed: %v", err)
+	}
+	want := []int{429, 502, -1}
+	if len(got) != len(want) {
+		t.Fatalf("ParseIntList() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ParseIntList()[%d] = %d, want %d", i, got[i], want[i])
+		}
+	}
+}
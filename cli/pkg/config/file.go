@@ -0,0 +1,522 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/internal/fsutil"
+)
+
+// setting 配置文件中的一项配置
+type setting struct {
+	// key 扁平名称，档案中的配置为 profile.<name>.<key>
+	key   string
+	value string
+	line  int
+}
+
+// Problem 配置文件中的问题
+type Problem struct {
+	// Line 所在行号，0 表示与具体行无关
+	Line    int    `json:"line,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+// String 返回带行号的描述
+func (p Problem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("第 %d 行: %s", p.Line, p.Message)
+	}
+	return p.Message
+}
+
+// ValidationError 配置文件校验失败
+type ValidationError struct {
+	Path     string
+	Problems []Problem
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "配置文件 %s 有 %d 个问题:", e.Path, len(e.Problems))
+	for _, p := range e.Problems {
+		b.WriteString("\n  " + p.String())
+	}
+	return b.String()
+}
+
+// ErrorCode 返回机器可读错误码
+func (e *ValidationError) ErrorCode() string {
+	return "CONFIG_INVALID"
+}
+
+// ErrorDetails 返回文件路径和问题列表
+func (e *ValidationError) ErrorDetails() map[string]any {
+	return map[string]any{"path": e.Path, "problems": e.Problems}
+}
+
+// Validate 校验配置文件，文件不存在时视为有效，有问题时返回 *ValidationError
+func Validate() error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	if problems := ValidateData(data); len(problems) > 0 {
+		return &ValidationError{Path: configPath, Problems: problems}
+	}
+	return nil
+}
+
+// EditableContent 返回用于编辑的配置内容，旧版格式先转换为 YAML，文件不存在时返回模板
+func EditableContent() ([]byte, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	if err == nil && !isLegacyFormat(data) {
+		return data, nil
+	}
+	cfg, err := read()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(marshalYAML(cfg)), nil
+}
+
+// ApplyEdit 校验并保存编辑后的配置内容，有问题时不写入并返回 *ValidationError
+//
+// 设置了 secret_backend 时，编辑中填入的明文密钥先转存到密钥后端，配置文件
+// 中只写入引用；转存失败时不写入配置文件，明文不会落盘。
+func ApplyEdit(data []byte) error {
+	if problems := ValidateData(data); len(problems) > 0 {
+		return &ValidationError{Path: configPath, Problems: problems}
+	}
+	cfg, err := parseUserConfig(data)
+	if err != nil {
+		return err
+	}
+	if cfg.SecretBackend != "" {
+		changed, err := cfg.storeSecrets(cfg.secretFields())
+		if err != nil {
+			return err
+		}
+		if changed {
+			data = []byte(marshalYAML(cfg))
+		}
+	}
+
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("创建配置目录失败: %w", err)
+	}
+	if err := fsutil.WriteFile(configPath, data, 0600); err != nil {
+		return fmt.Errorf("写入配置文件失败: %w", err)
+	}
+	return nil
+}
+
+// ValidateData 校验配置内容（YAML 或旧版 key=value 格式），返回发现的问题
+//
+// 检查语法、未知配置项、重复配置项、主题名称、枚举值和引用的配置档案。
+func ValidateData(data []byte) []Problem {
+	settings, problems, err := parseConfig(data)
+	if err != nil {
+		var syntaxErr *syntaxError
+		if errors.As(err, &syntaxErr) {
+			return []Problem{{Line: syntaxErr.line, Message: "YAML 语法错误: " + syntaxErr.msg}}
+		}
+		return []Problem{{Message: err.Error()}}
+	}
+
+	seen := map[string]int{}
+	profiles := map[string]bool{}
+	currentProfile := setting{}
+	for _, s := range settings {
+		if line, ok := seen[s.key]; ok {
+			problems = append(problems, Problem{Line: s.line, Key: s.key, Message: fmt.Sprintf("%s 重复设置（第 %d 行已设置）", s.key, line)})
+		}
+		seen[s.key] = s.line
+
+		name := s.key
+		if profile, field, ok := parseProfileKey(s.key); ok {
+			profiles[profile] = true
+			name = field
+		}
+		if s.key == "current_profile" {
+			currentProfile = s
+		}
+		spec, _ := lookupKey(name)
+		if err := spec.validateValue(s.value); err != nil {
+			problems = append(problems, Problem{Line: s.line, Key: s.key, Message: fmt.Sprintf("%s: %v", s.key, err)})
+		}
+	}
+	if v := currentProfile.value; v != "" && v != DefaultProfile && !profiles[v] {
+		problems = append(problems, Problem{Line: currentProfile.line, Key: "current_profile", Message: fmt.Sprintf("current_profile 引用的配置档案不存在: %s", v)})
+	}
+	return problems
+}
+
+// parseConfig 解析配置内容，自动识别 YAML 与旧版 key=value 格式
+func parseConfig(data []byte) ([]setting, []Problem, error) {
+	if isLegacyFormat(data) {
+		settings, problems := parseLegacy(data)
+		return settings, problems, nil
+	}
+	return parseYAML(data)
+}
+
+// isLegacyFormat 判断是否为旧版 key=value 格式（所有非注释行都是 key=value）
+func isLegacyFormat(data []byte) bool {
+	found := false
+	for _, line := range splitLines(data) {
+		line = trimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		key, _, ok := parseKeyValue(line)
+		if !ok || !isLegacyKey(key) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+func isLegacyKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseLegacy 解析旧版 key=value 格式
+func parseLegacy(data []byte) ([]setting, []Problem) {
+	var settings []setting
+	var problems []Problem
+	for i, line := range splitLines(data) {
+		line = trimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		key, value, _ := parseKeyValue(line)
+		if profile, field, ok := parseProfileKey(key); ok {
+			if spec, known := lookupKey(field); known && spec.profile && spec.name == field {
+				settings = append(settings, setting{key: "profile." + profile + "." + field, value: value, line: i + 1})
+				continue
+			}
+		} else if spec, ok := lookupKey(key); ok && spec.name == key {
+			settings = append(settings, setting{key: key, value: value, line: i + 1})
+			continue
+		}
+		problems = append(problems, Problem{Line: i + 1, Key: key, Message: "未知配置项 " + key})
+	}
+	return settings, problems
+}
+
+// syntaxError YAML 语法错误
+type syntaxError struct {
+	line int
+	msg  string
+}
+
+// Error 实现 error 接口
+func (e *syntaxError) Error() string {
+	return fmt.Sprintf("第 %d 行: %s", e.line, e.msg)
+}
+
+// yamlErrorLine 匹配 yaml.v3 错误信息中的行号
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// parseYAML 解析 YAML 格式
+func parseYAML(data []byte) ([]setting, []Problem, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			return nil, nil, &syntaxError{line: line, msg: m[2]}
+		}
+		return nil, nil, err
+	}
+	// 空文档
+	if len(doc.Content) == 0 {
+		return nil, nil, nil
+	}
+	root := resolveAlias(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, &syntaxError{line: root.Line, msg: "配置文件顶层必须是映射"}
+	}
+
+	w := &yamlWalker{}
+	w.walk(root, "", "")
+	return w.settings, w.problems, nil
+}
+
+// yamlWalker 遍历 YAML 节点收集配置项
+type yamlWalker struct {
+	settings []setting
+	problems []Problem
+}
+
+// walk 遍历映射，prefix 为当前路径，profile 非空时表示位于 profiles.<profile> 下
+func (w *yamlWalker) walk(node *yaml.Node, prefix, profile string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolveAlias(node.Content[i+1])
+		path := key.Value
+		if prefix != "" {
+			path = prefix + "." + key.Value
+		}
+
+		if path == "profiles" && profile == "" {
+			w.walkProfiles(key, value)
+			continue
+		}
+
+		if spec, ok := lookupKey(path); ok {
+			value, ok := scalarValue(value, spec.list)
+			if !ok {
+				w.problem(key.Line, path, fmt.Sprintf("%s 应为单个值", path))
+				continue
+			}
+			name := spec.name
+			if profile != "" {
+				if !spec.profile {
+					w.problem(key.Line, path, fmt.Sprintf("%s 不能在配置档案中设置", path))
+					continue
+				}
+				name = "profile." + profile + "." + spec.name
+			}
+			w.settings = append(w.settings, setting{key: name, value: value, line: key.Line})
+			continue
+		}
+
+		if isSection(path) {
+			if value.Kind == yaml.MappingNode {
+				w.walk(value, path, profile)
+			} else if !isNull(value) {
+				w.problem(key.Line, path, fmt.Sprintf("%s 应为映射", path))
+			}
+			continue
+		}
+		w.problem(key.Line, path, "未知配置项 "+path)
+	}
+}
+
+// walkProfiles 遍历 profiles 映射
+func (w *yamlWalker) walkProfiles(key, value *yaml.Node) {
+	if isNull(value) {
+		return
+	}
+	if value.Kind != yaml.MappingNode {
+		w.problem(key.Line, "profiles", "profiles 应为映射")
+		return
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		name, p := value.Content[i], resolveAlias(value.Content[i+1])
+		if err := validateProfileName(name.Value); err != nil {
+			w.problem(name.Line, "profiles."+name.Value, err.Error())
+			continue
+		}
+		switch {
+		case p.Kind == yaml.MappingNode:
+			// 空档案也要保留
+			w.settings = append(w.settings, setting{key: "profile." + name.Value + ".", line: name.Line})
+			w.walk(p, "", name.Value)
+		case isNull(p):
+			w.settings = append(w.settings, setting{key: "profile." + name.Value + ".", line: name.Line})
+		default:
+			w.problem(name.Line, "profiles."+name.Value, fmt.Sprintf("配置档案 %s 应为映射", name.Value))
+		}
+	}
+}
+
+func (w *yamlWalker) problem(line int, key, msg string) {
+	w.problems = append(w.problems, Problem{Line: line, Key: key, Message: msg})
+}
+
+// isSection 判断路径是否为配置分组（如 wechat、retry）
+func isSection(path string) bool {
+	for _, spec := range keySpecs {
+		if strings.HasPrefix(spec.path, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// scalarValue 返回标量值；list 为 true 时序列以 ", " 连接
+func scalarValue(n *yaml.Node, list bool) (string, bool) {
+	switch {
+	case n.Kind == yaml.ScalarNode:
+		if isNull(n) {
+			return "", true
+		}
+		return n.Value, true
+	case n.Kind == yaml.SequenceNode && list:
+		var items []string
+		for _, item := range n.Content {
+			item = resolveAlias(item)
+			if item.Kind == yaml.ScalarNode && !isNull(item) {
+				items = append(items, item.Value)
+			}
+		}
+		return strings.Join(items, ", "), true
+	}
+	return "", false
+}
+
+// isNull 判断节点是否为空值（如 "key:" 后无内容）
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
+
+// resolveAlias 返回别名 (*name) 指向的节点
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+// marshalYAML 将配置写为带说明头部的 YAML
+func marshalYAML(cfg *Config) string {
+	var b strings.Builder
+	b.WriteString(configHeader)
+	writeConfig(&b, cfg)
+	return b.String()
+}
+
+// writeConfig 按分组写出配置项和配置档案
+func writeConfig(b *strings.Builder, cfg *Config) {
+	writeSettings(b, "", func(spec keySpec) (string, bool) {
+		v := *cfg.ptr(spec.name)
+		return v, v != "" && v != spec.def
+	})
+
+	names := cfg.ProfileNames()
+	if len(names) > 0 {
+		b.WriteString("\nprofiles:\n")
+	}
+	for _, name := range names {
+		p := cfg.Profiles[name]
+		empty := true
+		for _, kv := range p.keyValues() {
+			empty = empty && kv[1] == ""
+		}
+		if empty {
+			fmt.Fprintf(b, "  %s: {}\n", name)
+			continue
+		}
+		fmt.Fprintf(b, "  %s:\n", name)
+		writeSettings(b, "    ", func(spec keySpec) (string, bool) {
+			if !spec.profile {
+				return "", false
+			}
+			v := *p.field(spec.name)
+			return v, v != ""
+		})
+	}
+}
+
+// writeSettings 按 keySpecs 顺序写出配置项，同一分组的配置项写在一起
+func writeSettings(b *strings.Builder, indent string, get func(keySpec) (string, bool)) {
+	section := ""
+	for _, spec := range keySpecs {
+		value, ok := get(spec)
+		if !ok {
+			continue
+		}
+		sec, leaf, nested := strings.Cut(spec.path, ".")
+		if !nested {
+			section = ""
+			fmt.Fprintf(b, "%s%s: %s\n", indent, spec.path, formatValue(spec, value))
+			continue
+		}
+		if sec != section {
+			fmt.Fprintf(b, "%s%s:\n", indent, sec)
+			section = sec
+		}
+		fmt.Fprintf(b, "%s  %s: %s\n", indent, leaf, formatValue(spec, value))
+	}
+}
+
+// formatValue 格式化 YAML 值，列表配置写为流式序列
+func formatValue(spec keySpec, value string) string {
+	if !spec.list {
+		return marshalInline(scalarNode(value))
+	}
+	list := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list.Content = append(list.Content, scalarNode(part))
+		}
+	}
+	return marshalInline(list)
+}
+
+// scalarNode 返回字符串标量节点，只在读回时会被解析为空值或含换行时加引号
+func scalarNode(s string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Value: s}
+	switch {
+	case strings.ContainsAny(s, "\n\r"):
+		node.Style = yaml.DoubleQuotedStyle
+	case s == "" || s == "~" || strings.EqualFold(s, "null"):
+		node.Tag = "!!str"
+	}
+	return node
+}
+
+// marshalInline 将节点写为单行 YAML
+func marshalInline(node *yaml.Node) string {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return strconv.Quote(node.Value)
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// configHeader 配置文件头部说明
+const configHeader = `# md2wx 配置文件（YAML）
+# 可通过环境变量覆盖（优先级更高），修改后可用 'md2wx config validate' 检查
+#
+# wechat:
+#   appid / appsecret      微信公众号 AppID / AppSecret（必填）
+# api:
+#   key                    md2wx API Key（必填，获取地址：https://www.md2wechat.cn/api-docs）
+#   base_url               API 基础 URL（默认：http://111.231.20.31:8080）
+# defaults:
+#   theme                  默认主题（md2wx themes list 查看）
+#   font_size              small / medium / large
+#   background_type        none / default / grid
+#   author / cover         文章默认作者 / 封面（图片 URL 或本地路径）
+# upload:
+#   local_images           是否上传 Markdown 中的本地图片（默认 true）
+#   cache                  是否使用图片上传缓存，相同图片不重复上传（默认 true）
+#   cache_ttl              缓存有效期，如 720h（默认不过期）
+#   cache_verify           使用缓存前检查图片地址是否仍可访问（默认 false）
+# retry:                   请求重试策略（默认不重试）
+#   max_attempts, base_delay, max_delay, jitter, statuses, codes, honor_retry_after
+# secret_backend           appsecret / api key 的存储方式：plain / file / keyring
+# current_profile          默认使用的配置档案
+# project:
+#   allow_secrets          是否允许项目配置（.md2wx.yaml）设置密钥和 api.base_url（默认 false）
+# profiles:                配置档案（多个公众号），可覆盖 wechat、api.key 和 defaults
+#   <name>:
+#     wechat:
+#       appid: ...
+
+`
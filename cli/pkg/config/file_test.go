@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRead_YAML(t *testing.T) {
+	useTempConfig(t)
+	os.MkdirAll(configDir, 0700)
+	os.WriteFile(configPath, []byte(`wechat:
+  appid: wx_yaml_appid
+api:
+  key: yaml_key
+  base_url: "https://api.example.com"
+defaults:
+  theme: apple
+upload:
+  local_images: false
+retry:
+  max_attempts: 3
+  statuses: [429, 503]
+current_profile: brand-b
+profiles:
+  brand-b:
+    wechat:
+      appid: wx_brand_b
+    defaults:
+      font_size: large
+  empty: {}
+`), 0600)
+
+	cfg, err := read()
+	if err != nil {
+		t.Fatalf("read() error = %v", err)
+	}
+	if cfg.WechatAppID != "wx_yaml_appid" || cfg.APIKey != "yaml_key" || cfg.APIBaseURL != "https://api.example.com" {
+		t.Errorf("read() = %+v", cfg)
+	}
+	if cfg.DefaultTheme != "apple" || cfg.UploadImages() || cfg.RetryMaxAttempts != "3" || cfg.RetryStatuses != "429, 503" {
+		t.Errorf("read() = %+v", cfg)
+	}
+	if cfg.CurrentProfile != "brand-b" || cfg.Profiles["brand-b"].WechatAppID != "wx_brand_b" || cfg.Profiles["brand-b"].DefaultFontSize != "large" {
+		t.Errorf("profiles = %+v", cfg.Profiles["brand-b"])
+	}
+	if _, ok := cfg.Profiles["empty"]; !ok {
+		t.Error("empty profile should be kept")
+	}
+
+	// 保存后再读取应保持一致
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	again, err := read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.RetryStatuses != cfg.RetryStatuses || again.UploadLocalImages != "false" || again.Profiles["brand-b"].DefaultFontSize != "large" || len(again.Profiles) != 2 {
+		t.Errorf("round trip = %+v", again)
+	}
+	if err := Validate(); err != nil {
+		t.Errorf("Validate() after Save = %v", err)
+	}
+}
+
+func TestSet_MigratesLegacy(t *testing.T) {
+	useTempConfig(t)
+	legacy := "# 旧格式\nwechat_appid=wx_legacy\napi_key=legacy_key\nretry_statuses=429,503\nprofile.b.font_size=small\n"
+	os.MkdirAll(configDir, 0700)
+	os.WriteFile(configPath, []byte(legacy), 0600)
+
+	cfg, err := Load()
+	if err != nil || cfg.WechatAppID != "wx_legacy" || cfg.Profiles["b"].DefaultFontSize != "small" {
+		t.Fatalf("Load(legacy) = %+v, %v", cfg, err)
+	}
+
+	if err := Set("default-theme", "apple"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if isLegacyFormat(data) || !strings.Contains(string(data), "wechat:\n  appid: wx_legacy\n") || !strings.Contains(string(data), "statuses: [429, 503]") {
+		t.Errorf("config not migrated:\n%s", data)
+	}
+	if bak, _ := os.ReadFile(configPath + ".bak"); string(bak) != legacy {
+		t.Errorf("backup = %q", bak)
+	}
+
+	cfg, _ = Load()
+	if cfg.APIKey != "legacy_key" || cfg.DefaultTheme != "apple" || cfg.Profiles["b"].DefaultFontSize != "small" {
+		t.Errorf("after migrate = %+v", cfg)
+	}
+}
+
+func TestSet_Validation(t *testing.T) {
+	useTempConfig(t)
+
+	for _, kv := range [][2]string{
+		{"default-theme", "no-such-theme"},
+		{"font-size", "huge"},
+		{"background-type", "dots"},
+		{"api-base", "ftp://example.com"},
+		{"upload-local-images", "maybe"},
+		{"current-profile", "b"},
+		{"no-such-key", "x"},
+	} {
+		if err := Set(kv[0], kv[1]); err == nil {
+			t.Errorf("Set(%s, %s) should fail", kv[0], kv[1])
+		}
+	}
+	if err := Set("upload.local_images", "false"); err != nil {
+		t.Errorf("Set(upload.local_images) error = %v", err)
+	}
+}
+
+func TestValidateData(t *testing.T) {
+	data := `wechat:
+  appid: wx_1
+  token: x
+defaults:
+  theme: no-such-theme
+  font_size: huge
+retry:
+  jitter: 1.5
+current_profile: missing
+profiles:
+  b:
+    api:
+      base_url: https://example.com
+`
+	problems := ValidateData([]byte(data))
+	want := map[int]string{
+		3:  "wechat.token",
+		5:  "default_theme",
+		6:  "font_size",
+		8:  "retry_jitter",
+		9:  "current_profile",
+		13: "api.base_url",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("ValidateData() = %v", problems)
+	}
+	for _, p := range problems {
+		if key, ok := want[p.Line]; !ok || !strings.Contains(p.Message, key) && !strings.Contains(p.Key, key) {
+			t.Errorf("unexpected problem %s (key %s)", p, p.Key)
+		}
+	}
+
+	problems = ValidateData([]byte("wechat:\n  appid: wx: x\n"))
+	if len(problems) != 1 || problems[0].Line != 2 {
+		t.Errorf("syntax error problems = %v", problems)
+	}
+
+	problems = ValidateData([]byte("wechat_appid=wx\nfont_size=huge\nfoo=bar\nwechat_appid=wx2\n"))
+	if len(problems) != 3 || problems[0].Line != 3 {
+		t.Errorf("legacy problems = %v", problems)
+	}
+}
+
+func TestValidate_Error(t *testing.T) {
+	useTempConfig(t)
+	if err := Validate(); err != nil {
+		t.Errorf("Validate() without file = %v", err)
+	}
+
+	os.MkdirAll(configDir, 0700)
+	os.WriteFile(configPath, []byte("defaults:\n  font_size: huge\n"), 0600)
+	var verr *ValidationError
+	if err := Validate(); !errors.As(err, &verr) || len(verr.Problems) != 1 || verr.Problems[0].Line != 2 {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if verr.ErrorCode() != "CONFIG_INVALID" || verr.ErrorDetails()["path"] != configPath {
+		t.Errorf("ValidationError = %s, %v", verr.ErrorCode(), verr.ErrorDetails())
+	}
+}
+
+func TestFormatValue_RoundTrip(t *testing.T) {
+	spec, _ := lookupKey("default_author")
+	for _, s := range []string{"plain", "http://example.com:8080/x", " padded", "a: b", "x #y", "- item", "#c", "[1]", "它说\"你好\"", "tab\there", "true", "null", "line1\nline2", "wx_1234"} {
+		q := formatValue(spec, s)
+		settings, problems, err := parseYAML([]byte("defaults:\n  author: " + q + "\n"))
+		if err != nil || len(problems) != 0 || len(settings) != 1 {
+			t.Errorf("formatValue(%q) = %s, parse = %v, %v, %v", s, q, settings, problems, err)
+			continue
+		}
+		if settings[0].value != s {
+			t.Errorf("formatValue(%q) = %s, round trip = %q", s, q, settings[0].value)
+		}
+	}
+	if q := formatValue(spec, "plain"); q != "plain" {
+		t.Errorf("formatValue(plain) = %s, should not add quotes", q)
+	}
+	list, _ := lookupKey("retry_statuses")
+	if q := formatValue(list, "429, 503"); q != "[429, 503]" {
+		t.Errorf("formatValue(list) = %s", q)
+	}
+}
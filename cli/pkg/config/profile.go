@@ -0,0 +1,277 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile 顶层配置对应的档案名称
+const DefaultProfile = "default"
+
+// ErrProfileNotFound 指定的配置档案不存在
+var ErrProfileNotFound = errors.New("配置档案不存在")
+
+// Profile 配置档案，用于管理多个公众号
+//
+// 为空的字段沿用顶层配置。
+type Profile struct {
+	WechatAppID           string `yaml:"wechat_appid" json:"wechat_appid,omitempty"`
+	WechatAppSecret       string `yaml:"wechat_appsecret" json:"wechat_appsecret,omitempty"`
+	APIKey                string `yaml:"api_key" json:"api_key,omitempty"`
+	DefaultTheme          string `yaml:"default_theme" json:"default_theme,omitempty"`
+	DefaultBackgroundType string `yaml:"background_type" json:"background_type,omitempty"`
+	DefaultFontSize       string `yaml:"font_size" json:"font_size,omitempty"`
+}
+
+// ProfileInfo 配置档案概要
+type ProfileInfo struct {
+	Name string `json:"name"`
+	// WechatAppID 掩码后的 AppID
+	WechatAppID string `json:"wechat_appid,omitempty"`
+	// Current 是否为 current_profile
+	Current bool `json:"current"`
+	// Active 是否为本次生效的档案（考虑 --profile 和 MD2WX_PROFILE）
+	Active bool `json:"active"`
+}
+
+// selectedProfile 命令行 --profile 指定的配置档案
+var selectedProfile string
+
+// SetProfile 指定本次使用的配置档案，优先于 MD2WX_PROFILE 和 current_profile
+func SetProfile(name string) {
+	selectedProfile = name
+}
+
+// ProfileNames 返回已定义的配置档案名称（不含 default），按名称排序
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListProfiles 列出所有配置档案，第一项为顶层配置 default
+func ListProfiles() ([]ProfileInfo, error) {
+	cfg, err := read()
+	if err != nil {
+		return nil, err
+	}
+	active, err := cfg.resolveProfile()
+	if err != nil {
+		return nil, err
+	}
+	current := cfg.CurrentProfile
+	if current == "" {
+		current = DefaultProfile
+	}
+
+	infos := []ProfileInfo{{Name: DefaultProfile, WechatAppID: maskIfSet(cfg.WechatAppID)}}
+	for _, name := range cfg.ProfileNames() {
+		infos = append(infos, ProfileInfo{Name: name, WechatAppID: maskIfSet(cfg.Profiles[name].WechatAppID)})
+	}
+	for i := range infos {
+		infos[i].Current = infos[i].Name == current
+		infos[i].Active = infos[i].Name == active
+	}
+	return infos, nil
+}
+
+// UseProfile 设置默认使用的配置档案
+func UseProfile(name string) error {
+	cfg, err := read()
+	if err != nil {
+		return err
+	}
+	if name == DefaultProfile {
+		cfg.CurrentProfile = ""
+		return Save(cfg)
+	}
+	if _, ok := cfg.Profiles[name]; !ok {
+		return profileNotFound(name)
+	}
+	cfg.CurrentProfile = name
+	return Save(cfg)
+}
+
+// DeleteProfile 删除配置档案，删除的是当前档案时恢复为 default
+func DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("不能删除 %s 档案", DefaultProfile)
+	}
+	cfg, err := read()
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Profiles[name]; !ok {
+		return profileNotFound(name)
+	}
+	cfg.deleteSecrets(cfg.Profiles[name], name)
+	delete(cfg.Profiles, name)
+	if cfg.CurrentProfile == name {
+		cfg.CurrentProfile = ""
+	}
+	return Save(cfg)
+}
+
+// resolveProfile 返回本次使用的档案名称：--profile > MD2WX_PROFILE > current_profile > default
+func (c *Config) resolveProfile() (string, error) {
+	return c.resolveProfileName(selectedProfile)
+}
+
+// resolveProfileName 返回使用的档案名称：selected > MD2WX_PROFILE > current_profile > default
+func (c *Config) resolveProfileName(selected string) (string, error) {
+	name := selected
+	if name == "" {
+		name = os.Getenv("MD2WX_PROFILE")
+	}
+	if name == "" {
+		name = c.CurrentProfile
+	}
+	if name == "" {
+		return DefaultProfile, nil
+	}
+	if err := validateProfileName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// applyProfile 将 selected（为空时按 MD2WX_PROFILE、current_profile）选择的档案覆盖到顶层配置
+func (c *Config) applyProfile(selected string) error {
+	name, err := c.resolveProfileName(selected)
+	if err != nil {
+		return err
+	}
+	c.Profile = name
+	if name == DefaultProfile {
+		return nil
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return profileNotFound(name)
+	}
+	for _, kv := range p.keyValues() {
+		if kv[1] != "" {
+			*c.field(kv[0]) = kv[1]
+			c.setOrigin(kv[0], fmt.Sprintf("%s (profile %s)", c.Origin("profile."+name+"."+kv[0]), name))
+		}
+	}
+	return nil
+}
+
+// profileOrigin 返回本次使用的档案来自哪里
+func (c *Config) profileOrigin() string {
+	switch {
+	case selectedProfile != "":
+		return "--profile"
+	case os.Getenv("MD2WX_PROFILE") != "":
+		return "env MD2WX_PROFILE"
+	}
+	return c.Origin("current_profile")
+}
+
+// profile 返回指定档案，create 为 true 时不存在则创建
+func (c *Config) profile(name string, create bool) *Profile {
+	p, ok := c.Profiles[name]
+	if !ok && create {
+		if c.Profiles == nil {
+			c.Profiles = make(map[string]*Profile)
+		}
+		p = &Profile{}
+		c.Profiles[name] = p
+	}
+	return p
+}
+
+// field 返回顶层配置中可被档案覆盖的字段指针
+func (c *Config) field(name string) *string {
+	switch name {
+	case "wechat_appid":
+		return &c.WechatAppID
+	case "wechat_appsecret":
+		return &c.WechatAppSecret
+	case "api_key":
+		return &c.APIKey
+	case "default_theme":
+		return &c.DefaultTheme
+	case "background_type":
+		return &c.DefaultBackgroundType
+	case "font_size":
+		return &c.DefaultFontSize
+	}
+	return nil
+}
+
+// field 返回档案字段指针，不支持的配置项返回 nil
+func (p *Profile) field(name string) *string {
+	switch name {
+	case "wechat_appid":
+		return &p.WechatAppID
+	case "wechat_appsecret":
+		return &p.WechatAppSecret
+	case "api_key":
+		return &p.APIKey
+	case "default_theme":
+		return &p.DefaultTheme
+	case "background_type":
+		return &p.DefaultBackgroundType
+	case "font_size":
+		return &p.DefaultFontSize
+	}
+	return nil
+}
+
+// keyValues 按固定顺序返回档案的键值对
+func (p *Profile) keyValues() [][2]string {
+	return [][2]string{
+		{"wechat_appid", p.WechatAppID},
+		{"wechat_appsecret", p.WechatAppSecret},
+		{"api_key", p.APIKey},
+		{"default_theme", p.DefaultTheme},
+		{"background_type", p.DefaultBackgroundType},
+		{"font_size", p.DefaultFontSize},
+	}
+}
+
+// parseProfileKey 解析 profile.<name>.<key> 形式的配置项
+func parseProfileKey(key string) (name, field string, ok bool) {
+	rest, ok := strings.CutPrefix(key, "profile.")
+	if !ok {
+		return "", "", false
+	}
+	name, field, ok = strings.Cut(rest, ".")
+	if !ok || validateProfileName(name) != nil {
+		return "", "", false
+	}
+	return name, field, true
+}
+
+// validateProfileName 档案名称只允许字母、数字、- 和 _
+func validateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("配置档案名称不能为空")
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("配置档案名称只能包含字母、数字、- 和 _: %s", name)
+		}
+	}
+	return nil
+}
+
+func profileNotFound(name string) error {
+	return fmt.Errorf("%w: %s（可用 'config profiles list' 查看）", ErrProfileNotFound, name)
+}
+
+func maskIfSet(s string) string {
+	if s == "" {
+		return ""
+	}
+	return maskSensitive(s)
+}
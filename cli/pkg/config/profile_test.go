@@ -0,0 +1,173 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useTempConfig 将配置文件指向临时目录，并清除档案选择
+func useTempConfig(t *testing.T) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	oldConfigDir, oldConfigPath := configDir, configPath
+	t.Cleanup(func() {
+		configDir, configPath = oldConfigDir, oldConfigPath
+		selectedProfile = ""
+	})
+	configDir = filepath.Join(tmpDir, ConfigDir)
+	configPath = filepath.Join(configDir, ConfigFile)
+	selectedProfile = ""
+	t.Setenv("MD2WX_PROFILE", "")
+	t.Setenv("MD2WX_PROJECT_CONFIG", "off")
+}
+
+func TestProfile_SetAndLoad(t *testing.T) {
+	useTempConfig(t)
+
+	if err := Set("wechat-appid", "wx_base_appid"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set("api-key", "base_key"); err != nil {
+		t.Fatal(err)
+	}
+
+	SetProfile("brand-b")
+	if err := Set("wechat-appid", "wx_brand_b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set("default-theme", "bytedance"); err != nil {
+		t.Fatal(err)
+	}
+	// 非档案配置项写入顶层
+	if err := Set("retry-max-attempts", "3"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Profile != "brand-b" || cfg.WechatAppID != "wx_brand_b" || cfg.DefaultTheme != "bytedance" {
+		t.Errorf("brand-b config = %+v", cfg)
+	}
+	// 档案未设置的项沿用顶层
+	if cfg.APIKey != "base_key" || cfg.RetryMaxAttempts != "3" {
+		t.Errorf("inherited values = %q, %q", cfg.APIKey, cfg.RetryMaxAttempts)
+	}
+
+	SetProfile("")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != DefaultProfile || cfg.WechatAppID != "wx_base_appid" || cfg.DefaultTheme != "" {
+		t.Errorf("default config = %+v", cfg)
+	}
+
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), "  brand-b:\n    wechat:\n      appid: wx_brand_b\n") {
+		t.Errorf("config file missing profile line:\n%s", data)
+	}
+}
+
+func TestSetAll_LoadUser(t *testing.T) {
+	useTempConfig(t)
+	t.Setenv("MD2WX_WECHAT_APPID", "wx_from_env")
+
+	SetProfile("brand-b")
+	err := SetAll([][2]string{{"wechat-appid", "wx_brand_b"}, {"font-size", "large"}})
+	if err != nil {
+		t.Fatalf("SetAll() error = %v", err)
+	}
+	// 任一项无效时不写入
+	if err := SetAll([][2]string{{"font-size", "small"}, {"font-size", "huge"}}); err == nil {
+		t.Error("SetAll() with invalid value should fail")
+	}
+
+	// 只读取用户配置，环境变量不参与
+	cfg, err := LoadUser()
+	if err != nil {
+		t.Fatalf("LoadUser() error = %v", err)
+	}
+	if cfg.Profile != "brand-b" || cfg.WechatAppID != "wx_brand_b" || cfg.DefaultFontSize != "large" {
+		t.Errorf("LoadUser() = %+v", cfg)
+	}
+	if cfg, _ := Load(); cfg.WechatAppID != "wx_from_env" {
+		t.Errorf("Load() appid = %q, want env override", cfg.WechatAppID)
+	}
+}
+
+func TestProfile_Selection(t *testing.T) {
+	useTempConfig(t)
+
+	SetProfile("a")
+	Set("wechat-appid", "wx_a")
+	SetProfile("b")
+	Set("wechat-appid", "wx_b")
+	SetProfile("")
+
+	if err := UseProfile("a"); err != nil {
+		t.Fatalf("UseProfile() error = %v", err)
+	}
+	if cfg, _ := Load(); cfg.WechatAppID != "wx_a" {
+		t.Errorf("current_profile: WechatAppID = %s, want wx_a", cfg.WechatAppID)
+	}
+
+	t.Setenv("MD2WX_PROFILE", "b")
+	if cfg, _ := Load(); cfg.WechatAppID != "wx_b" {
+		t.Errorf("MD2WX_PROFILE: WechatAppID = %s, want wx_b", cfg.WechatAppID)
+	}
+
+	SetProfile("a")
+	if cfg, _ := Load(); cfg.WechatAppID != "wx_a" {
+		t.Errorf("--profile: WechatAppID = %s, want wx_a", cfg.WechatAppID)
+	}
+
+	SetProfile("missing")
+	if _, err := Load(); err == nil {
+		t.Error("Load() with unknown profile should fail")
+	}
+	SetProfile("bad.name")
+	if _, err := Load(); err == nil {
+		t.Error("Load() with invalid profile name should fail")
+	}
+}
+
+func TestProfile_ListUseDelete(t *testing.T) {
+	useTempConfig(t)
+
+	SetProfile("brand-b")
+	Set("wechat-appid", "wx_brand_b_appid")
+	SetProfile("")
+	UseProfile("brand-b")
+
+	infos, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if len(infos) != 2 || infos[0].Name != DefaultProfile || infos[1].Name != "brand-b" {
+		t.Fatalf("ListProfiles() = %+v", infos)
+	}
+	if !infos[1].Current || !infos[1].Active || infos[1].WechatAppID != "wx_b***ppid" {
+		t.Errorf("brand-b info = %+v", infos[1])
+	}
+
+	if err := UseProfile("missing"); err == nil {
+		t.Error("UseProfile(missing) should fail")
+	}
+	if err := DeleteProfile(DefaultProfile); err == nil {
+		t.Error("DeleteProfile(default) should fail")
+	}
+	if err := DeleteProfile("brand-b"); err != nil {
+		t.Fatalf("DeleteProfile() error = %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() after delete error = %v", err)
+	}
+	if cfg.Profile != DefaultProfile || len(cfg.Profiles) != 0 {
+		t.Errorf("after delete = %+v", cfg)
+	}
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ProjectFile 项目配置文件名，从工作目录向上查找
+const ProjectFile = ".md2wx.yaml"
+
+// projectDir 查找项目配置的起始目录，为空时使用工作目录
+var projectDir string
+
+// SetProjectDir 指定查找项目配置的起始目录，为空表示工作目录
+func SetProjectDir(dir string) {
+	projectDir = dir
+}
+
+// FindProjectFile 返回生效的项目配置文件路径，没有时返回空字符串
+//
+// MD2WX_PROJECT_CONFIG 可指定文件路径，设为 off 时不使用项目配置。
+func FindProjectFile() (string, error) {
+	return findProjectFile(projectDir)
+}
+
+// findProjectFile 从 dir（为空时为工作目录）向上查找项目配置
+func findProjectFile(dir string) (string, error) {
+	switch env := os.Getenv("MD2WX_PROJECT_CONFIG"); env {
+	case "":
+	case "off":
+		return "", nil
+	default:
+		if _, err := os.Stat(env); err != nil {
+			return "", fmt.Errorf("MD2WX_PROJECT_CONFIG 指定的项目配置不可用: %w", err)
+		}
+		return filepath.Abs(env)
+	}
+
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", nil
+		}
+		dir = wd
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", nil
+	}
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// projectConfig 项目配置中的配置项
+type projectConfig struct {
+	path     string
+	settings []setting
+}
+
+// loadProject 从 dir 开始查找并解析项目配置，没有项目配置时返回 nil
+func (c *Config) loadProject(dir string) (*projectConfig, error) {
+	path, err := findProjectFile(dir)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取项目配置失败: %w", err)
+	}
+	settings, _, err := parseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析项目配置 %s 失败: %w", path, err)
+	}
+
+	p := &projectConfig{path: path}
+	trusted := c.trustProjectSecrets()
+	for _, s := range settings {
+		if allowed, needTrust := projectAllowed(s.key); !allowed || (needTrust && !trusted) {
+			continue
+		}
+		if s.key == "default_cover" {
+			s.value = resolveProjectPath(filepath.Dir(path), s.value)
+		}
+		p.settings = append(p.settings, s)
+	}
+	return p, nil
+}
+
+// apply 将 match 选中的项目配置合并到 c
+func (p *projectConfig) apply(c *Config, match func(key string) bool) {
+	if p == nil {
+		return
+	}
+	for _, s := range p.settings {
+		if !match(s.key) {
+			continue
+		}
+		if f := c.ptr(s.key); f != nil {
+			*f = s.value
+			c.setOrigin(s.key, fmt.Sprintf("%s:%d", p.path, s.line))
+		}
+	}
+}
+
+// projectAllowed 判断配置项能否来自项目配置，needTrust 表示需要用户配置 project.allow_secrets
+//
+// api_base_url 决定凭据发往哪里，与密钥一样需要显式信任；
+// 密钥后端、信任开关和配置档案只能在用户配置中设置。
+func projectAllowed(key string) (allowed, needTrust bool) {
+	switch {
+	case strings.HasPrefix(key, "profile."), key == "secret_backend", key == "project_allow_secrets":
+		return false, false
+	case IsSensitive(key), key == "api_base_url":
+		return true, true
+	}
+	return true, false
+}
+
+// trustProjectSecrets 用户配置是否允许项目配置设置密钥
+func (c *Config) trustProjectSecrets() bool {
+	v, _ := strconv.ParseBool(c.ProjectAllowSecrets)
+	return v
+}
+
+// resolveProjectPath 将项目配置中的相对路径转换为以项目配置所在目录为基准
+func resolveProjectPath(dir, value string) string {
+	if value == "" || filepath.IsAbs(value) || strings.Contains(value, "://") {
+		return value
+	}
+	return filepath.Join(dir, value)
+}
+
+// ValidateProject 校验项目配置文件，没有项目配置时返回 nil
+//
+// 除 ValidateData 的检查外，还会报告因安全原因被忽略的配置项。
+func ValidateProject() error {
+	path, err := FindProjectFile()
+	if err != nil || path == "" {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取项目配置失败: %w", err)
+	}
+	problems := ValidateData(data)
+
+	user, err := read()
+	if err != nil {
+		return err
+	}
+	if settings, _, err := parseConfig(data); err == nil {
+		for _, s := range settings {
+			if msg := projectIgnoredReason(s.key, user.trustProjectSecrets()); msg != "" {
+				key := s.key
+				if name, field, ok := parseProfileKey(s.key); ok && field == "" {
+					key = "profiles." + name
+				}
+				problems = append(problems, Problem{Line: s.line, Key: key, Message: msg})
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Path: path, Problems: problems}
+	}
+	return nil
+}
+
+// projectIgnoredReason 返回配置项在项目配置中被忽略的原因，不忽略时返回空字符串
+func projectIgnoredReason(key string, trusted bool) string {
+	allowed, needTrust := projectAllowed(key)
+	switch {
+	case strings.HasPrefix(key, "profile."):
+		if _, field, _ := parseProfileKey(key); field == "" {
+			return "项目配置不支持 profiles，请在用户配置中定义配置档案"
+		}
+		return ""
+	case !allowed:
+		return fmt.Sprintf("%s 只能在用户配置中设置", key)
+	case needTrust && !trusted:
+		return fmt.Sprintf("%s 在项目配置中默认忽略（需在用户配置中设置 project.allow_secrets: true）", key)
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useTempProject 在临时目录下创建项目配置，返回项目根目录和其下的子目录
+func useTempProject(t *testing.T, content string) (root, sub string) {
+	t.Helper()
+	t.Setenv("MD2WX_PROJECT_CONFIG", "")
+	root = t.TempDir()
+	sub = filepath.Join(root, "docs", "posts")
+	os.MkdirAll(sub, 0755)
+	os.WriteFile(filepath.Join(root, ProjectFile), []byte(content), 0644)
+	t.Cleanup(func() { SetProjectDir("") })
+	SetProjectDir(sub)
+	return root, sub
+}
+
+func TestLoad_ProjectConfig(t *testing.T) {
+	useTempConfig(t)
+	Set("api-key", "user_key")
+	Set("default-theme", "bytedance")
+	Set("font-size", "small")
+	root, _ := useTempProject(t, `defaults:
+  theme: apple
+  author: 团队
+  cover: assets/cover.png
+api:
+  key: repo_key
+  base_url: https://example.com
+upload:
+  local_images: false
+`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ProjectPath != filepath.Join(root, ProjectFile) {
+		t.Errorf("ProjectPath = %q", cfg.ProjectPath)
+	}
+	if cfg.DefaultTheme != "apple" || cfg.DefaultAuthor != "团队" || cfg.UploadImages() || cfg.DefaultFontSize != "small" {
+		t.Errorf("merged config = %+v", cfg)
+	}
+	if cfg.DefaultCover != filepath.Join(root, "assets", "cover.png") {
+		t.Errorf("DefaultCover = %q, want path relative to project file", cfg.DefaultCover)
+	}
+	// 密钥和 api_base_url 默认不合并
+	if cfg.APIKey != "user_key" || cfg.APIBaseURL != DefaultAPIBaseURL {
+		t.Errorf("secrets merged without trust: %q, %q", cfg.APIKey, cfg.APIBaseURL)
+	}
+	if origin := cfg.Origin("default_theme"); origin != filepath.Join(root, ProjectFile)+":2" {
+		t.Errorf("Origin(default_theme) = %q", origin)
+	}
+	if origin := cfg.Origin("font_size"); !strings.HasPrefix(origin, configPath+":") {
+		t.Errorf("Origin(font_size) = %q", origin)
+	}
+	if origin := cfg.Origin("background_type"); origin != "default" {
+		t.Errorf("Origin(background_type) = %q", origin)
+	}
+
+	t.Setenv("MD2WX_DEFAULT_THEME", "cyber")
+	cfg, _ = Load()
+	if cfg.DefaultTheme != "cyber" || cfg.Origin("default_theme") != "env MD2WX_DEFAULT_THEME" {
+		t.Errorf("env override = %q (%s)", cfg.DefaultTheme, cfg.Origin("default_theme"))
+	}
+
+	if err := Set("project.allow_secrets", "true"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _ = Load()
+	if cfg.APIKey != "repo_key" || cfg.APIBaseURL != "https://example.com" {
+		t.Errorf("trusted secrets = %q, %q", cfg.APIKey, cfg.APIBaseURL)
+	}
+
+	// config set 只写用户配置
+	data, _ := os.ReadFile(configPath)
+	if strings.Contains(string(data), "apple") {
+		t.Errorf("project values written to user config:\n%s", data)
+	}
+}
+
+func TestLoad_ProjectSelectsProfile(t *testing.T) {
+	useTempConfig(t)
+	Set("wechat-appid", "wx_base")
+	SetProfile("brand-b")
+	Set("wechat-appid", "wx_brand_b")
+	Set("default-theme", "bytedance")
+	SetProfile("")
+	useTempProject(t, "current_profile: brand-b\ndefaults:\n  theme: apple\n")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 项目配置选择档案，且项目中的主题优先于档案
+	if cfg.Profile != "brand-b" || cfg.WechatAppID != "wx_brand_b" || cfg.DefaultTheme != "apple" {
+		t.Errorf("config = %+v", cfg)
+	}
+	if origin := cfg.Origin("wechat_appid"); !strings.HasSuffix(origin, "(profile brand-b)") {
+		t.Errorf("Origin(wechat_appid) = %q", origin)
+	}
+}
+
+func TestFindProjectFile(t *testing.T) {
+	useTempConfig(t)
+	root, _ := useTempProject(t, "defaults:\n  theme: apple\n")
+
+	if path, err := FindProjectFile(); err != nil || path != filepath.Join(root, ProjectFile) {
+		t.Errorf("FindProjectFile() = %q, %v", path, err)
+	}
+
+	t.Setenv("MD2WX_PROJECT_CONFIG", "off")
+	if path, _ := FindProjectFile(); path != "" {
+		t.Errorf("FindProjectFile() with off = %q", path)
+	}
+	t.Setenv("MD2WX_PROJECT_CONFIG", filepath.Join(root, "missing.yaml"))
+	if _, err := FindProjectFile(); err == nil {
+		t.Error("FindProjectFile() with missing file should fail")
+	}
+}
+
+func TestValidateProject(t *testing.T) {
+	useTempConfig(t)
+	useTempProject(t, "defaults:\n  theme: apple\napi:\n  key: k\nsecret_backend: file\nprofiles:\n  x: {}\n")
+
+	var verr *ValidationError
+	if err := ValidateProject(); !errors.As(err, &verr) {
+		t.Fatalf("ValidateProject() = %v", err)
+	}
+	lines := map[int]string{}
+	for _, p := range verr.Problems {
+		lines[p.Line] = p.Key
+	}
+	if len(verr.Problems) != 3 || lines[4] != "api_key" || lines[5] != "secret_backend" || lines[7] != "profiles.x" {
+		t.Errorf("problems = %v", verr.Problems)
+	}
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/secret"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/themes"
+)
+
+// keySpec 配置项定义
+type keySpec struct {
+	// name 扁平名称，用于旧格式文件和 config set/get
+	name string
+	// path YAML 配置文件中的路径
+	path string
+	// profile 可在配置档案中覆盖
+	profile bool
+	// list 在 YAML 中写为序列，内部以逗号分隔保存
+	list bool
+	// def 默认值，等于默认值时不写入配置文件
+	def string
+	// validate 校验取值，为 nil 表示任意值
+	validate func(string) error
+}
+
+// keySpecs 所有配置项，顺序即配置文件的写出顺序
+var keySpecs = []keySpec{
+	{name: "wechat_appid", path: "wechat.appid", profile: true},
+	{name: "wechat_appsecret", path: "wechat.appsecret", profile: true},
+	{name: "api_key", path: "api.key", profile: true},
+	{name: "api_base_url", path: "api.base_url", def: DefaultAPIBaseURL, validate: validateURL},
+	{name: "default_theme", path: "defaults.theme", profile: true, def: "default", validate: validateTheme},
+	{name: "font_size", path: "defaults.font_size", profile: true, def: "medium", validate: oneOf("small", "medium", "large")},
+	{name: "background_type", path: "defaults.background_type", profile: true, def: "none", validate: oneOf("default", "grid", "none")},
+	{name: "default_author", path: "defaults.author"},
+	{name: "default_cover", path: "defaults.cover"},
+	{name: "upload_local_images", path: "upload.local_images", validate: validateBool},
+	{name: "upload_cache", path: "upload.cache", def: "true", validate: validateBool},
+	{name: "upload_cache_ttl", path: "upload.cache_ttl", validate: validateDuration},
+	{name: "upload_cache_verify", path: "upload.cache_verify", def: "false", validate: validateBool},
+	{name: "retry_max_attempts", path: "retry.max_attempts", validate: retryValidator("retry_max_attempts")},
+	{name: "retry_base_delay", path: "retry.base_delay", validate: retryValidator("retry_base_delay")},
+	{name: "retry_max_delay", path: "retry.max_delay", validate: retryValidator("retry_max_delay")},
+	{name: "retry_jitter", path: "retry.jitter", validate: retryValidator("retry_jitter")},
+	{name: "retry_statuses", path: "retry.statuses", list: true, validate: retryValidator("retry_statuses")},
+	{name: "retry_codes", path: "retry.codes", list: true, validate: retryValidator("retry_codes")},
+	{name: "retry_honor_retry_after", path: "retry.honor_retry_after", validate: retryValidator("retry_honor_retry_after")},
+	{name: "secret_backend", path: "secret_backend", validate: oneOf(secret.Backends...)},
+	{name: "current_profile", path: "current_profile", validate: validateProfileName},
+	{name: "project_allow_secrets", path: "project.allow_secrets", validate: validateBool},
+}
+
+// lookupKey 按扁平名称（- 与 _ 等价）或 YAML 路径查找配置项
+func lookupKey(key string) (keySpec, bool) {
+	key = strings.ReplaceAll(key, "-", "_")
+	for _, spec := range keySpecs {
+		if spec.name == key || spec.path == key {
+			return spec, true
+		}
+	}
+	// config set 的简写
+	if key == "api_base" {
+		return lookupKey("api_base_url")
+	}
+	return keySpec{}, false
+}
+
+// ptr 返回扁平名称对应的字段指针，支持 profile.<name>.<key>，未知配置项返回 nil
+func (c *Config) ptr(name string) *string {
+	if profile, field, ok := parseProfileKey(name); ok {
+		return c.profile(profile, true).field(field)
+	}
+	switch name {
+	case "api_base_url":
+		return &c.APIBaseURL
+	case "default_author":
+		return &c.DefaultAuthor
+	case "default_cover":
+		return &c.DefaultCover
+	case "upload_local_images":
+		return &c.UploadLocalImages
+	case "upload_cache":
+		return &c.UploadCache
+	case "upload_cache_ttl":
+		return &c.UploadCacheTTL
+	case "upload_cache_verify":
+		return &c.UploadCacheVerify
+	case "project_allow_secrets":
+		return &c.ProjectAllowSecrets
+	case "secret_backend":
+		return &c.SecretBackend
+	case "current_profile":
+		return &c.CurrentProfile
+	}
+	if strings.HasPrefix(name, "retry_") {
+		if spec, ok := lookupKey(name); ok {
+			return retryField(c, spec.name)
+		}
+		return nil
+	}
+	return c.field(name)
+}
+
+// validateValue 校验配置项取值，secret:// 引用和空值不校验
+func (spec keySpec) validateValue(value string) error {
+	if value == "" || spec.validate == nil || strings.HasPrefix(value, secretRefPrefix) {
+		return nil
+	}
+	return spec.validate(value)
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(v string) error {
+		if !slices.Contains(values, v) {
+			return fmt.Errorf("无效的取值 %q（可选: %s）", v, strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
+
+func validateTheme(v string) error {
+	if !themes.IsValidTheme(v) {
+		return fmt.Errorf("无效的主题 %q，使用 'md2wx themes list' 查看可用主题", v)
+	}
+	return nil
+}
+
+func validateURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("无效的 URL %q（需以 http:// 或 https:// 开头）", v)
+	}
+	return nil
+}
+
+func validateBool(v string) error {
+	if _, err := strconv.ParseBool(v); err != nil {
+		return fmt.Errorf("无效的布尔值 %q（可选: true, false）", v)
+	}
+	return nil
+}
+
+func validateDuration(v string) error {
+	if d, err := time.ParseDuration(v); err != nil || d < 0 {
+		return fmt.Errorf("无效的时长 %q（如 24h、720h）", v)
+	}
+	return nil
+}
+
+func retryValidator(name string) func(string) error {
+	return func(v string) error {
+		return validateRetryValue(name, v)
+	}
+}
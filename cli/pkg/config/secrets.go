@@ -0,0 +1,194 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/secret"
+)
+
+// secretRefPrefix 配置文件中指向密钥后端的值前缀，如 api_key=secret://api_key
+const secretRefPrefix = "secret://"
+
+// secretField 敏感配置项
+type secretField struct {
+	// key 在密钥后端中的名称，与配置文件中的键一致
+	key   string
+	value *string
+}
+
+// IsSensitive 判断配置项是否为敏感信息（通过密钥后端保存）
+func IsSensitive(key string) bool {
+	key = strings.ReplaceAll(key, "-", "_")
+	return key == "wechat_appsecret" || key == "api_key"
+}
+
+// secretFields 返回顶层和所有档案中的敏感配置项
+func (c *Config) secretFields() []secretField {
+	fields := []secretField{
+		{"wechat_appsecret", &c.WechatAppSecret},
+		{"api_key", &c.APIKey},
+	}
+	for _, name := range c.ProfileNames() {
+		p := c.Profiles[name]
+		fields = append(fields,
+			secretField{"profile." + name + ".wechat_appsecret", &p.WechatAppSecret},
+			secretField{"profile." + name + ".api_key", &p.APIKey},
+		)
+	}
+	return fields
+}
+
+// openSecretBackend 打开配置的密钥后端，plain 返回 nil
+//
+// 打开的后端缓存在配置中，file 后端在同一份配置的多次读写间只解密一次。
+func (c *Config) openSecretBackend() (secret.Backend, error) {
+	if c.secrets != nil && c.secretsName == c.SecretBackend {
+		return c.secrets, nil
+	}
+	b, err := secret.Open(c.SecretBackend, configDir)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	c.secrets, c.secretsName = b, c.SecretBackend
+	return b, nil
+}
+
+// resolveSecrets 将 secret:// 引用替换为密钥后端中的值
+func (c *Config) resolveSecrets() error {
+	var backend secret.Backend
+	for _, f := range c.secretFields() {
+		ref, ok := strings.CutPrefix(*f.value, secretRefPrefix)
+		if !ok {
+			continue
+		}
+		if backend == nil {
+			b, err := c.openSecretBackend()
+			if err != nil {
+				return err
+			}
+			if b == nil {
+				return fmt.Errorf("配置项 %s 引用了密钥存储，但未设置 secret_backend", f.key)
+			}
+			backend = b
+		}
+		value, err := backend.Get(ref)
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %w", f.key, err)
+		}
+		*f.value = value
+	}
+	return nil
+}
+
+// storeSecret 将敏感值保存到密钥后端，配置中只保留引用；plain 后端时保留明文
+func (c *Config) storeSecret(key string, value *string) error {
+	backend, err := c.openSecretBackend()
+	if err != nil || backend == nil {
+		return err
+	}
+	if strings.HasPrefix(*value, secretRefPrefix) {
+		return nil
+	}
+	if *value == "" {
+		return backend.Delete(key)
+	}
+	if err := backend.Set(key, *value); err != nil {
+		return err
+	}
+	*value = secretRefPrefix + key
+	return nil
+}
+
+// deleteSecrets 删除档案在密钥后端中的条目，失败时忽略
+func (c *Config) deleteSecrets(p *Profile, name string) {
+	backend, err := c.openSecretBackend()
+	if err != nil || backend == nil {
+		return
+	}
+	for _, v := range []string{p.WechatAppSecret, p.APIKey} {
+		if ref, ok := strings.CutPrefix(v, secretRefPrefix); ok && strings.HasPrefix(ref, "profile."+name+".") {
+			backend.Delete(ref)
+		}
+	}
+}
+
+// setSecretBackend 切换密钥后端，并把已有的敏感配置迁移到新后端
+func (c *Config) setSecretBackend(name string) error {
+	if name == "" {
+		name = secret.BackendPlain
+	}
+	if !slices.Contains(secret.Backends, name) {
+		return fmt.Errorf("不支持的密钥后端: %s（可选: %s）", name, strings.Join(secret.Backends, ", "))
+	}
+
+	// 先用旧后端解出明文
+	old, err := c.openSecretBackend()
+	if err != nil {
+		return err
+	}
+	if err := c.resolveSecrets(); err != nil {
+		return err
+	}
+
+	c.SecretBackend = name
+	if name == secret.BackendPlain {
+		c.SecretBackend = ""
+	}
+	backend, err := c.openSecretBackend()
+	if err != nil {
+		return err
+	}
+	var migrated []string
+	err = secret.Batch(backend, func() error {
+		for _, f := range c.secretFields() {
+			if *f.value == "" {
+				continue
+			}
+			if err := c.storeSecret(f.key, f.value); err != nil {
+				return err
+			}
+			migrated = append(migrated, f.key)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 清理旧后端中的条目
+	if old != nil && old.Name() != name {
+		secret.Batch(old, func() error {
+			for _, key := range migrated {
+				old.Delete(key)
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// storeSecrets 对 fields 中尚未是引用的值执行 storeSecret，file 后端只写入一次
+//
+// 返回是否有配置项被替换为引用。
+func (c *Config) storeSecrets(fields []secretField) (bool, error) {
+	backend, err := c.openSecretBackend()
+	if err != nil || backend == nil {
+		return false, err
+	}
+	changed := false
+	err = secret.Batch(backend, func() error {
+		for _, f := range fields {
+			if strings.HasPrefix(*f.value, secretRefPrefix) {
+				continue
+			}
+			if err := c.storeSecret(f.key, f.value); err != nil {
+				return err
+			}
+			changed = changed || strings.HasPrefix(*f.value, secretRefPrefix)
+		}
+		return nil
+	})
+	return changed, err
+}
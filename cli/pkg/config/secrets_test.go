@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/secret"
+)
+
+func TestSecretBackend_File(t *testing.T) {
+	useTempConfig(t)
+	t.Setenv("MD2WX_SECRET_PASSPHRASE", "")
+	t.Setenv("MD2WX_SECRET_KEY_FILE", "")
+
+	// 已有的明文配置在切换后端时迁移
+	if err := Set("api-key", "wme_plaintext_key"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set("secret-backend", "file"); err != nil {
+		t.Fatalf("Set(secret-backend) error = %v", err)
+	}
+	if err := Set("wechat-appsecret", "app_secret_value"); err != nil {
+		t.Fatal(err)
+	}
+	SetProfile("brand-b")
+	if err := Set("api-key", "brand_b_key"); err != nil {
+		t.Fatal(err)
+	}
+	SetProfile("")
+
+	data, _ := os.ReadFile(configPath)
+	content := string(data)
+	for _, plain := range []string{"wme_plaintext_key", "app_secret_value", "brand_b_key"} {
+		if strings.Contains(content, plain) {
+			t.Errorf("config file contains plaintext %q:\n%s", plain, content)
+		}
+	}
+	if !strings.Contains(content, "key: secret://api_key\n") || !strings.Contains(content, "key: secret://profile.brand-b.api_key\n") {
+		t.Errorf("config file missing secret references:\n%s", content)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, secret.StoreFile)); err != nil {
+		t.Errorf("secret store not created: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.APIKey != "wme_plaintext_key" || cfg.WechatAppSecret != "app_secret_value" {
+		t.Errorf("Load() secrets = %q, %q", cfg.APIKey, cfg.WechatAppSecret)
+	}
+	SetProfile("brand-b")
+	if cfg, _ := Load(); cfg.APIKey != "brand_b_key" {
+		t.Errorf("brand-b APIKey = %q", cfg.APIKey)
+	}
+	SetProfile("")
+
+	// 切回明文
+	if err := Set("secret-backend", "plain"); err != nil {
+		t.Fatalf("Set(secret-backend plain) error = %v", err)
+	}
+	data, _ = os.ReadFile(configPath)
+	if !strings.Contains(string(data), "key: wme_plaintext_key\n") || strings.Contains(string(data), "secret_backend:") {
+		t.Errorf("config file after plain:\n%s", data)
+	}
+}
+
+func TestSecretBackend_Errors(t *testing.T) {
+	useTempConfig(t)
+
+	if err := Set("secret-backend", "vault"); err == nil {
+		t.Error("Set(secret-backend vault) should fail")
+	}
+
+	// 引用了密钥存储但未设置后端
+	os.MkdirAll(configDir, 0700)
+	os.WriteFile(configPath, []byte("api_key=secret://api_key\n"), 0600)
+	if _, err := Load(); err == nil {
+		t.Error("Load() with dangling secret reference should fail")
+	}
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 导出时敏感配置的处理方式
+const (
+	// SecretsMask 掩码（默认），导入时跳过
+	SecretsMask = "mask"
+	// SecretsOmit 不导出
+	SecretsOmit = "omit"
+	// SecretsInclude 导出明文，需显式指定
+	SecretsInclude = "include"
+)
+
+// SecretModes 支持的敏感配置处理方式
+var SecretModes = []string{SecretsMask, SecretsOmit, SecretsInclude}
+
+// 导出格式
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// ExportOptions 导出选项
+type ExportOptions struct {
+	// Format yaml 或 json
+	Format string
+	// Secrets 敏感配置的处理方式，为空表示 mask
+	Secrets string
+}
+
+// secretMask --secrets mask 时敏感配置导出的占位符，导入时跳过
+const secretMask = "***"
+
+// Export 导出用户配置（不含项目配置和环境变量），结构与 YAML 配置文件一致
+//
+// 密钥后端中的敏感配置按 Secrets 掩码、省略或解密后导出；secret_backend 不导出。
+func Export(opts ExportOptions) ([]byte, error) {
+	if opts.Secrets == "" {
+		opts.Secrets = SecretsMask
+	}
+	if !slices.Contains(SecretModes, opts.Secrets) {
+		return nil, fmt.Errorf("不支持的 secrets 选项: %s（可选: %s）", opts.Secrets, strings.Join(SecretModes, ", "))
+	}
+
+	cfg, err := read()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Secrets == SecretsInclude {
+		if err := cfg.resolveSecrets(); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range cfg.secretFields() {
+		switch {
+		case *f.value == "":
+		case opts.Secrets == SecretsOmit:
+			*f.value = ""
+		case opts.Secrets == SecretsMask:
+			// 固定占位符，不泄露密钥的任何字符（包括密钥后端中的引用）
+			*f.value = secretMask
+		}
+	}
+	cfg.SecretBackend = ""
+
+	switch opts.Format {
+	case FormatYAML, "":
+		var b strings.Builder
+		fmt.Fprintf(&b, "# md2wx 配置导出（secrets: %s）\n\n", opts.Secrets)
+		writeConfig(&b, cfg)
+		return []byte(b.String()), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(exportTree(cfg), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+	return nil, fmt.Errorf("不支持的导出格式: %s（可选: yaml, json）", opts.Format)
+}
+
+// exportTree 将配置转换为与 YAML 配置文件结构相同的嵌套映射
+func exportTree(cfg *Config) map[string]any {
+	root := map[string]any{}
+	addSettings(root, func(spec keySpec) string {
+		if v := *cfg.ptr(spec.name); v != spec.def {
+			return v
+		}
+		return ""
+	})
+	if names := cfg.ProfileNames(); len(names) > 0 {
+		profiles := map[string]any{}
+		for _, name := range names {
+			p := cfg.Profiles[name]
+			tree := map[string]any{}
+			addSettings(tree, func(spec keySpec) string {
+				if !spec.profile {
+					return ""
+				}
+				return *p.field(spec.name)
+			})
+			profiles[name] = tree
+		}
+		root["profiles"] = profiles
+	}
+	return root
+}
+
+// addSettings 按 YAML 路径把非空配置项写入嵌套映射，列表配置写为数组
+func addSettings(tree map[string]any, get func(keySpec) string) {
+	for _, spec := range keySpecs {
+		value := get(spec)
+		if value == "" {
+			continue
+		}
+		var v any = value
+		if spec.list {
+			var items []string
+			for _, part := range strings.Split(value, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					items = append(items, part)
+				}
+			}
+			v = items
+		}
+		node := tree
+		parts := strings.Split(spec.path, ".")
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = v
+	}
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	// Imported 导入的配置项数量
+	Imported int `json:"imported"`
+	// Skipped 跳过的配置项（掩码的密钥、密钥引用和 secret_backend）
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Import 导入 Export 生成的 JSON 或 YAML（也接受旧版 key=value 格式）
+//
+// 默认合并到现有配置，replace 为 true 时替换全部配置（保留本机的 secret_backend）。
+// 内容有问题时不做任何修改，返回 *ValidationError；敏感配置按本机的密钥后端保存。
+func Import(data []byte, source string, replace bool) (*ImportResult, error) {
+	settings, problems, err := parseImport(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", source, err)
+	}
+	for _, s := range settings {
+		name := s.key
+		if _, field, ok := parseProfileKey(s.key); ok {
+			name = field
+		}
+		spec, _ := lookupKey(name)
+		if err := spec.validateValue(s.value); err != nil {
+			problems = append(problems, Problem{Line: s.line, Key: s.key, Message: fmt.Sprintf("%s: %v", s.key, err)})
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Path: source, Problems: problems}
+	}
+
+	cfg, err := read()
+	if err != nil {
+		return nil, err
+	}
+	if replace {
+		cfg = &Config{APIBaseURL: DefaultAPIBaseURL, SecretBackend: cfg.SecretBackend}
+	}
+
+	result := &ImportResult{}
+	var secrets []secretField
+	for _, s := range settings {
+		_, field, isProfile := parseProfileKey(s.key)
+		name := s.key
+		if isProfile {
+			name = field
+		}
+		switch {
+		case s.key == "secret_backend":
+			result.Skipped = append(result.Skipped, s.key)
+			continue
+		case IsSensitive(name) && (strings.Contains(s.value, secretMask) || strings.HasPrefix(s.value, secretRefPrefix)):
+			result.Skipped = append(result.Skipped, s.key)
+			continue
+		}
+		f := cfg.ptr(s.key)
+		if f == nil {
+			// 空档案
+			continue
+		}
+		*f = s.value
+		if IsSensitive(name) {
+			secrets = append(secrets, secretField{s.key, f})
+		}
+		result.Imported++
+	}
+	if _, err := cfg.storeSecrets(secrets); err != nil {
+		return nil, err
+	}
+	if err := Save(cfg); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseImport 解析导入内容，以 { 开头时按 JSON 解析
+func parseImport(data []byte) ([]setting, []Problem, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		return parseConfig(data)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, nil, err
+	}
+	if _, ok := v.(map[string]any); !ok {
+		return nil, nil, fmt.Errorf("顶层必须是对象")
+	}
+	// 转换为 YAML 节点，复用 YAML 配置的遍历逻辑
+	var root yaml.Node
+	if err := root.Encode(v); err != nil {
+		return nil, nil, err
+	}
+	w := &yamlWalker{}
+	w.walk(&root, "", "")
+	return w.settings, w.problems, nil
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// setupTransferConfig 写入包含档案和密钥的配置
+func setupTransferConfig(t *testing.T) {
+	t.Helper()
+	useTempConfig(t)
+	t.Setenv("MD2WX_SECRET_PASSPHRASE", "")
+	t.Setenv("MD2WX_SECRET_KEY_FILE", "")
+	Set("wechat-appid", "wx_transfer_appid")
+	Set("api-key", "wme_transfer_secret")
+	Set("retry-statuses", "429, 503")
+	Set("secret-backend", "file")
+	SetProfile("b")
+	Set("default-theme", "apple")
+	SetProfile("")
+}
+
+func TestExport_Secrets(t *testing.T) {
+	setupTransferConfig(t)
+
+	for _, tt := range []struct {
+		secrets string
+		want    string
+		absent  string
+	}{
+		{SecretsMask, "key: '***'", "wme_"},
+		{SecretsOmit, "appid: wx_transfer_appid", "api:"},
+		{SecretsInclude, "key: wme_transfer_secret", "secret://"},
+	} {
+		data, err := Export(ExportOptions{Format: FormatYAML, Secrets: tt.secrets})
+		if err != nil {
+			t.Fatalf("Export(%s) error = %v", tt.secrets, err)
+		}
+		if !strings.Contains(string(data), tt.want) || strings.Contains(string(data), tt.absent) {
+			t.Errorf("Export(%s) =\n%s", tt.secrets, data)
+		}
+		if strings.Contains(string(data), "secret_backend") {
+			t.Errorf("Export(%s) should not contain secret_backend", tt.secrets)
+		}
+	}
+
+	if _, err := Export(ExportOptions{Secrets: "plain"}); err == nil {
+		t.Error("Export(secrets=plain) should fail")
+	}
+}
+
+func TestExportImport_JSONRoundTrip(t *testing.T) {
+	setupTransferConfig(t)
+	data, err := Export(ExportOptions{Format: FormatJSON, Secrets: SecretsInclude})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		t.Fatalf("Export(json) is not valid JSON: %v\n%s", err, data)
+	}
+
+	// 导入到另一台机器（明文后端）
+	useTempConfig(t)
+	result, err := Import(data, "export.json", false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Imported != 4 || len(result.Skipped) != 0 {
+		t.Errorf("Import() = %+v", result)
+	}
+	cfg, err := read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIKey != "wme_transfer_secret" || cfg.WechatAppID != "wx_transfer_appid" || cfg.RetryStatuses != "429, 503" || cfg.Profiles["b"].DefaultTheme != "apple" {
+		t.Errorf("imported config = %+v", cfg)
+	}
+}
+
+func TestImport_MergeReplaceAndSkip(t *testing.T) {
+	useTempConfig(t)
+	Set("wechat-appid", "wx_local")
+	Set("font-size", "small")
+
+	masked := "api:\n  key: wme_***cret\ndefaults:\n  theme: apple\nsecret_backend: keyring\n"
+	result, err := Import([]byte(masked), "masked.yaml", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 1 || strings.Join(result.Skipped, ",") != "api_key,secret_backend" {
+		t.Errorf("Import(masked) = %+v", result)
+	}
+	cfg, _ := read()
+	if cfg.WechatAppID != "wx_local" || cfg.DefaultTheme != "apple" || cfg.APIKey != "" || cfg.SecretBackend != "" {
+		t.Errorf("merged config = %+v", cfg)
+	}
+
+	if _, err := Import([]byte("defaults:\n  font_size: large\n"), "replace.yaml", true); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _ = read()
+	if cfg.WechatAppID != "" || cfg.DefaultFontSize != "large" {
+		t.Errorf("replaced config = %+v", cfg)
+	}
+
+	// 校验失败时不修改
+	before, _ := os.ReadFile(configPath)
+	var verr *ValidationError
+	if _, err := Import([]byte(`{"defaults": {"font_size": "huge"}, "foo": 1}`), "bad.json", false); !errors.As(err, &verr) || len(verr.Problems) != 2 {
+		t.Errorf("Import(bad) error = %v", err)
+	}
+	if after, _ := os.ReadFile(configPath); string(after) != string(before) {
+		t.Error("invalid import modified the config file")
+	}
+}
+
+func TestUnset(t *testing.T) {
+	setupTransferConfig(t)
+
+	if err := Unset("api-key"); err != nil {
+		t.Fatalf("Unset(api-key) error = %v", err)
+	}
+	if err := Unset("api-key"); err == nil {
+		t.Error("Unset(api-key) twice should fail")
+	}
+	if err := Unset("api-base"); err == nil {
+		t.Error("Unset(api-base) without value should fail")
+	}
+	if err := Unset("no-such-key"); err == nil {
+		t.Error("Unset(no-such-key) should fail")
+	}
+	SetProfile("b")
+	if err := Unset("default-theme"); err != nil {
+		t.Fatalf("Unset(default-theme) in profile error = %v", err)
+	}
+	SetProfile("")
+	if err := Unset("secret-backend"); err != nil {
+		t.Fatalf("Unset(secret-backend) error = %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIKey != "" || cfg.SecretBackend != "" || cfg.Profiles["b"].DefaultTheme != "" || cfg.WechatAppID != "wx_transfer_appid" {
+		t.Errorf("after unset = %+v", cfg)
+	}
+}
+
+func TestApplyEdit(t *testing.T) {
+	setupTransferConfig(t)
+
+	content, err := EditableContent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var verr *ValidationError
+	if err := ApplyEdit([]byte(string(content) + "unknown: 1\n")); !errors.As(err, &verr) {
+		t.Fatalf("ApplyEdit(invalid) = %v", err)
+	}
+
+	// 编辑中填入的明文密钥转存到密钥后端
+	edited := strings.Replace(string(content), "appid: wx_transfer_appid", "appid: wx_transfer_appid\n  appsecret: typed_plain_secret", 1)
+	if err := ApplyEdit([]byte(edited)); err != nil {
+		t.Fatalf("ApplyEdit() error = %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if strings.Contains(string(data), "typed_plain_secret") {
+		t.Errorf("plaintext secret left in config:\n%s", data)
+	}
+	if cfg, _ := Load(); cfg.WechatAppSecret != "typed_plain_secret" {
+		t.Errorf("WechatAppSecret = %q", cfg.WechatAppSecret)
+	}
+}
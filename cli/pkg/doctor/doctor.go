@@ -0,0 +1,289 @@
+// Package doctor 提供 md2wx doctor 的诊断检查项和报告。
+//
+// 每个检查项给出 pass / warn / fail / skip 状态、说明和诊断详情，
+// 报告汇总各状态数量，整体状态取最严重的一项。检查本身不访问网络，
+// 由调用方传入探测结果（见 api.ProbeResult）和凭据校验错误。
+package doctor
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+)
+
+// Status 检查状态
+type Status string
+
+const (
+	// Pass 检查通过
+	Pass Status = "pass"
+	// Warn 存在隐患，不影响使用
+	Warn Status = "warn"
+	// Fail 检查失败，相关命令无法正常工作
+	Fail Status = "fail"
+	// Skip 前置检查失败，未执行
+	Skip Status = "skip"
+)
+
+// severity 状态的严重程度，用于计算整体状态
+func (s Status) severity() int {
+	switch s {
+	case Warn:
+		return 1
+	case Fail:
+		return 2
+	}
+	return 0
+}
+
+// 诊断阈值
+const (
+	// ClockSkewWarn 时钟偏差超过该值时警告
+	ClockSkewWarn = 30 * time.Second
+	// ClockSkewFail 时钟偏差超过该值时失败（定时发布和证书校验会受影响）
+	ClockSkewFail = 5 * time.Minute
+	// CertExpiryWarn 证书在该时间内过期时警告
+	CertExpiryWarn = 14 * 24 * time.Hour
+	// SlowLatency 请求耗时超过该值时警告
+	SlowLatency = 3 * time.Second
+)
+
+// Check 单个检查项的结果
+type Check struct {
+	Name    string         `json:"name"`
+	Status  Status         `json:"status"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// Report 诊断报告
+type Report struct {
+	// Status 整体状态，取各检查项中最严重的状态
+	Status  Status         `json:"status"`
+	Summary map[Status]int `json:"summary"`
+	Checks  []Check        `json:"checks"`
+}
+
+// NewReport 创建空报告
+func NewReport() *Report {
+	return &Report{Status: Pass, Summary: map[Status]int{Pass: 0, Warn: 0, Fail: 0, Skip: 0}}
+}
+
+// Add 添加检查项并更新汇总
+func (r *Report) Add(checks ...Check) {
+	for _, c := range checks {
+		r.Checks = append(r.Checks, c)
+		r.Summary[c.Status]++
+		if c.Status.severity() > r.Status.severity() {
+			r.Status = c.Status
+		}
+	}
+}
+
+// Failed 是否有检查项失败
+func (r *Report) Failed() bool {
+	return r.Status == Fail
+}
+
+// statusMarks 文本报告中各状态的标记
+var statusMarks = map[Status]string{Pass: "✓", Warn: "!", Fail: "✗", Skip: "-"}
+
+// WriteText 以人类可读的格式输出报告
+func (r *Report) WriteText(w io.Writer) {
+	width := 0
+	for _, c := range r.Checks {
+		width = max(width, len(c.Name))
+	}
+	for _, c := range r.Checks {
+		fmt.Fprintf(w, "%s %-*s  %s\n", statusMarks[c.Status], width, c.Name, c.Message)
+		if hint, ok := c.Details["hint"].(string); ok {
+			fmt.Fprintf(w, "  %-*s  → %s\n", width, "", hint)
+		}
+	}
+	fmt.Fprintf(w, "\n%d 通过，%d 警告，%d 失败，%d 跳过\n",
+		r.Summary[Pass], r.Summary[Warn], r.Summary[Fail], r.Summary[Skip])
+}
+
+// Permissions 检查包含敏感信息的文件是否只有所有者可读写，不存在的文件忽略
+//
+// Windows 不使用 Unix 权限位，返回 skip。
+func Permissions(name string, paths ...string) Check {
+	if runtime.GOOS == "windows" {
+		return Check{Name: name, Status: Skip, Message: "Windows 不检查文件权限"}
+	}
+	var checked, loose []string
+	files := map[string]string{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Check{Name: name, Status: Fail, Message: fmt.Sprintf("无法读取 %s: %v", path, err)}
+		}
+		mode := info.Mode().Perm()
+		files[path] = fmt.Sprintf("%04o", mode)
+		checked = append(checked, path)
+		if mode&0o077 != 0 {
+			loose = append(loose, path)
+		}
+	}
+	if len(checked) == 0 {
+		return Check{Name: name, Status: Skip, Message: "没有本地配置文件，未检查"}
+	}
+	details := map[string]any{"files": files}
+	if len(loose) > 0 {
+		details["hint"] = "chmod 600 " + strings.Join(loose, " ")
+		msg := make([]string, len(loose))
+		for i, path := range loose {
+			msg[i] = fmt.Sprintf("%s (%s)", path, files[path])
+		}
+		return Check{Name: name, Status: Warn, Message: "其他用户可读取: " + strings.Join(msg, ", "), Details: details}
+	}
+	return Check{Name: name, Status: Pass, Message: fmt.Sprintf("%d 个文件仅所有者可读写", len(checked)), Details: details}
+}
+
+// Reachability 根据探测结果检查 API 服务是否可达
+func Reachability(result *api.ProbeResult, err error) Check {
+	if err != nil {
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			// 能完成 TLS 握手说明网络可达，证书问题由 TLS 检查报告
+			return Check{Name: "api_reachable", Status: Pass, Message: "API 服务可达（证书校验失败，见 tls）"}
+		}
+		return Check{Name: "api_reachable", Status: Fail, Message: err.Error(), Details: map[string]any{
+			"hint": "检查网络、代理（HTTPS_PROXY）和 api_base_url 配置",
+		}}
+	}
+	details := map[string]any{
+		"url":         result.URL,
+		"http_status": result.StatusCode,
+		"latency_ms":  result.Latency.Milliseconds(),
+	}
+	msg := fmt.Sprintf("%s 可达（HTTP %d，%d ms）", result.URL, result.StatusCode, result.Latency.Milliseconds())
+	switch {
+	case result.StatusCode >= 500:
+		return Check{Name: "api_reachable", Status: Warn, Message: msg + "，服务端错误", Details: details}
+	case result.Latency > SlowLatency:
+		return Check{Name: "api_reachable", Status: Warn, Message: msg + "，响应较慢", Details: details}
+	}
+	return Check{Name: "api_reachable", Status: Pass, Message: msg, Details: details}
+}
+
+// TLS 检查 API 服务的 HTTPS 证书，now 用于计算证书剩余有效期
+func TLS(result *api.ProbeResult, err error, now time.Time) Check {
+	if err != nil {
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			return Check{Name: "tls", Status: Fail, Message: fmt.Sprintf("证书校验失败: %v", certErr.Err), Details: map[string]any{
+				"hint": "检查系统时间和根证书，或代理是否替换了证书",
+			}}
+		}
+		return Check{Name: "tls", Status: Skip, Message: "API 服务不可达，未检查"}
+	}
+	if result.TLSVersion == "" {
+		return Check{Name: "tls", Status: Warn, Message: "未使用 HTTPS，凭据将以明文传输", Details: map[string]any{"url": result.URL}}
+	}
+	remaining := result.CertExpiry.Sub(now)
+	details := map[string]any{
+		"version":     result.TLSVersion,
+		"subject":     result.CertSubject,
+		"cert_expiry": result.CertExpiry.Format(time.RFC3339),
+	}
+	msg := fmt.Sprintf("%s，证书 %s 有效期至 %s", result.TLSVersion, result.CertSubject, result.CertExpiry.Format("2006-01-02"))
+	if remaining < CertExpiryWarn {
+		return Check{Name: "tls", Status: Warn, Message: msg + "，即将过期", Details: details}
+	}
+	return Check{Name: "tls", Status: Pass, Message: msg, Details: details}
+}
+
+// ClockSkew 根据响应 Date 头检查本地时钟偏差
+func ClockSkew(result *api.ProbeResult, err error) Check {
+	if err != nil {
+		return Check{Name: "clock_skew", Status: Skip, Message: "API 服务不可达，未检查"}
+	}
+	skew, ok := result.ClockSkew()
+	if !ok {
+		return Check{Name: "clock_skew", Status: Warn, Message: "响应缺少 Date 头，无法检查时钟偏差"}
+	}
+	abs := skew.Abs().Round(time.Second)
+	details := map[string]any{"skew_seconds": skew.Round(time.Second).Seconds()}
+	direction := "快"
+	if skew < 0 {
+		direction = "慢"
+	}
+	msg := fmt.Sprintf("本地时钟比服务端%s %s", direction, abs)
+	switch {
+	case abs > ClockSkewFail:
+		details["hint"] = "同步系统时间（如启用 NTP）"
+		return Check{Name: "clock_skew", Status: Fail, Message: msg, Details: details}
+	case abs > ClockSkewWarn:
+		details["hint"] = "同步系统时间（如启用 NTP）"
+		return Check{Name: "clock_skew", Status: Warn, Message: msg, Details: details}
+	}
+	return Check{Name: "clock_skew", Status: Pass, Message: fmt.Sprintf("时钟偏差 %s", abs), Details: details}
+}
+
+// Credentials 根据凭据校验结果（api.Client.VerifyCredentialsContext）给出
+// API Key、公众号凭据和 IP 白名单三项检查
+//
+// 服务端在前一项失败时不会继续校验后面的项，这些项标记为 skip。
+func Credentials(err error) []Check {
+	apiKey := Check{Name: "api_key", Status: Pass, Message: "API Key 有效"}
+	wechat := Check{Name: "wechat_credentials", Status: Pass, Message: "AppID 和 AppSecret 有效"}
+	whitelist := Check{Name: "ip_whitelist", Status: Pass, Message: "当前 IP 在公众号白名单中"}
+	if err == nil {
+		return []Check{apiKey, wechat, whitelist}
+	}
+
+	var apiErr *api.Error
+	if !errors.As(err, &apiErr) {
+		// 网络错误由 api_reachable 报告
+		reason := "API 请求失败"
+		return []Check{skipped(apiKey.Name, reason), skipped(wechat.Name, reason), skipped(whitelist.Name, reason)}
+	}
+	details := apiErr.ErrorDetails()
+	details["error_code"] = apiErr.ErrorCode()
+
+	switch {
+	case errors.Is(err, api.ErrUnauthorized):
+		apiKey = Check{Name: "api_key", Status: Fail, Message: "API Key 无效或缺失", Details: withHint(details, "md2wx config set api-key <key>")}
+		return []Check{apiKey, skipped(wechat.Name, "API Key 无效"), skipped(whitelist.Name, "API Key 无效")}
+	case errors.Is(err, api.ErrIPNotWhitelisted):
+		whitelist = Check{Name: "ip_whitelist", Status: Fail, Message: "当前 IP 不在公众号白名单中: " + apiErr.Message,
+			Details: withHint(details, "在公众号后台「设置与开发 → 基本配置 → IP 白名单」中添加服务出口 IP")}
+		return []Check{apiKey, skipped(wechat.Name, "IP 白名单校验未通过"), whitelist}
+	case errors.Is(err, api.ErrInvalidAppID), errors.Is(err, api.ErrInvalidAppSecret), errors.Is(err, api.ErrInvalidCredential):
+		wechat = Check{Name: "wechat_credentials", Status: Fail, Message: fmt.Sprintf("%v: %s", apiErr.Unwrap(), apiErr.Message),
+			Details: withHint(details, "md2wx config set wechat-appid / wechat-appsecret")}
+		return []Check{apiKey, wechat, skipped(whitelist.Name, "公众号凭据无效")}
+	case errors.Is(err, api.ErrQuotaExceeded), errors.Is(err, api.ErrRateLimited):
+		wechat = Check{Name: "wechat_credentials", Status: Warn, Message: fmt.Sprintf("%v，稍后再试", apiErr.Unwrap()), Details: details}
+		return []Check{apiKey, wechat, skipped(whitelist.Name, "调用受限")}
+	case apiErr.StatusCode != http.StatusOK:
+		apiKey = Check{Name: "api_key", Status: Warn, Message: "服务端返回错误，无法确认: " + apiErr.Error(), Details: details}
+		return []Check{apiKey, skipped(wechat.Name, "服务端错误"), skipped(whitelist.Name, "服务端错误")}
+	}
+	wechat = Check{Name: "wechat_credentials", Status: Fail, Message: apiErr.Error(), Details: details}
+	return []Check{apiKey, wechat, skipped(whitelist.Name, "公众号凭据校验未通过")}
+}
+
+// skipped 返回因前置检查失败而跳过的检查项
+func skipped(name, reason string) Check {
+	return Check{Name: name, Status: Skip, Message: reason + "，未检查"}
+}
+
+// withHint 在诊断详情中加入修复建议
+func withHint(details map[string]any, hint string) map[string]any {
+	details["hint"] = hint
+	return details
+}
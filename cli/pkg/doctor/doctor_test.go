@@ -0,0 +1,139 @@
+package doctor
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+)
+
+func TestReport(t *testing.T) {
+	r := NewReport()
+	r.Add(Check{Name: "a", Status: Pass, Message: "ok"})
+	if r.Status != Pass || r.Failed() {
+		t.Errorf("Status = %s", r.Status)
+	}
+	r.Add(Check{Name: "b", Status: Skip}, Check{Name: "long_name", Status: Warn, Message: "hm", Details: map[string]any{"hint": "do x"}})
+	if r.Status != Warn {
+		t.Errorf("Status = %s, want warn (skip does not count)", r.Status)
+	}
+	r.Add(Check{Name: "c", Status: Fail, Message: "bad"}, Check{Name: "d", Status: Warn})
+	if r.Status != Fail || !r.Failed() {
+		t.Errorf("Status = %s, want fail", r.Status)
+	}
+	if r.Summary[Pass] != 1 || r.Summary[Warn] != 2 || r.Summary[Fail] != 1 || r.Summary[Skip] != 1 {
+		t.Errorf("Summary = %v", r.Summary)
+	}
+
+	var b bytes.Buffer
+	r.WriteText(&b)
+	text := b.String()
+	for _, want := range []string{"✓ a          ok", "! long_name  hm", "→ do x", "✗ c", "1 通过，2 警告，1 失败，1 跳过"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("WriteText() missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix 权限位")
+	}
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.yaml")
+	key := filepath.Join(dir, "secret.key")
+
+	if c := Permissions("config_permissions", config, key); c.Status != Skip {
+		t.Errorf("missing files = %+v, want skip", c)
+	}
+	os.WriteFile(config, []byte("x"), 0600)
+	if c := Permissions("config_permissions", config, key); c.Status != Pass {
+		t.Errorf("0600 = %+v, want pass", c)
+	}
+	os.WriteFile(key, []byte("x"), 0600)
+	os.Chmod(key, 0644)
+	c := Permissions("config_permissions", config, key)
+	if c.Status != Warn || c.Details["hint"] != "chmod 600 "+key || !strings.Contains(c.Message, "(0644)") {
+		t.Errorf("0644 = %+v, want warn with hint", c)
+	}
+}
+
+func TestProbeChecks(t *testing.T) {
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	plain := &api.ProbeResult{URL: "http://localhost", StatusCode: 404, Latency: 20 * time.Millisecond, ServerTime: now, LocalTime: now}
+	secure := &api.ProbeResult{URL: "https://example.com", StatusCode: 200, Latency: 20 * time.Millisecond,
+		TLSVersion: "TLS 1.3", CertSubject: "example.com", CertExpiry: now.Add(90 * 24 * time.Hour), ServerTime: now, LocalTime: now}
+
+	tests := []struct {
+		name   string
+		check  Check
+		status Status
+	}{
+		{"reachable", Reachability(plain, nil), Pass},
+		{"unreachable", Reachability(nil, errors.New("connection refused")), Fail},
+		{"server error", Reachability(&api.ProbeResult{StatusCode: 502}, nil), Warn},
+		{"slow", Reachability(&api.ProbeResult{StatusCode: 200, Latency: 5 * time.Second}, nil), Warn},
+		{"cert error reachable", Reachability(nil, fmt.Errorf("请求失败: %w", &tls.CertificateVerificationError{Err: errors.New("x509")})), Pass},
+		{"tls plain", TLS(plain, nil, now), Warn},
+		{"tls ok", TLS(secure, nil, now), Pass},
+		{"tls expiring", TLS(secure, nil, now.Add(80*24*time.Hour)), Warn},
+		{"tls cert error", TLS(nil, fmt.Errorf("请求失败: %w", &tls.CertificateVerificationError{Err: errors.New("x509")}), now), Fail},
+		{"tls unreachable", TLS(nil, errors.New("timeout"), now), Skip},
+		{"skew ok", ClockSkew(plain, nil), Pass},
+		{"skew warn", ClockSkew(&api.ProbeResult{ServerTime: now, LocalTime: now.Add(-time.Minute)}, nil), Warn},
+		{"skew fail", ClockSkew(&api.ProbeResult{ServerTime: now, LocalTime: now.Add(10 * time.Minute)}, nil), Fail},
+		{"skew no date", ClockSkew(&api.ProbeResult{LocalTime: now}, nil), Warn},
+		{"skew unreachable", ClockSkew(nil, errors.New("timeout")), Skip},
+	}
+	for _, tt := range tests {
+		if tt.check.Status != tt.status {
+			t.Errorf("%s: %+v, want %s", tt.name, tt.check, tt.status)
+		}
+	}
+
+	if c := ClockSkew(&api.ProbeResult{ServerTime: now, LocalTime: now.Add(-time.Minute)}, nil); !strings.Contains(c.Message, "慢 1m0s") {
+		t.Errorf("ClockSkew message = %q", c.Message)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want [3]Status // api_key, wechat_credentials, ip_whitelist
+	}{
+		{"ok", nil, [3]Status{Pass, Pass, Pass}},
+		{"network", errors.New("请求失败: connection refused"), [3]Status{Skip, Skip, Skip}},
+		{"unauthorized", &api.Error{StatusCode: 401}, [3]Status{Fail, Skip, Skip}},
+		{"whitelist", &api.Error{StatusCode: 200, Code: 40164, Message: "invalid ip 1.2.3.4"}, [3]Status{Pass, Skip, Fail}},
+		{"appsecret", &api.Error{StatusCode: 200, Code: 40125}, [3]Status{Pass, Fail, Skip}},
+		{"appid", &api.Error{StatusCode: 200, Code: 40013}, [3]Status{Pass, Fail, Skip}},
+		{"quota", &api.Error{StatusCode: 200, Code: 45009}, [3]Status{Pass, Warn, Skip}},
+		{"server", &api.Error{StatusCode: 500}, [3]Status{Warn, Skip, Skip}},
+		{"unknown code", &api.Error{StatusCode: 200, Code: 99999}, [3]Status{Pass, Fail, Skip}},
+	}
+	for _, tt := range tests {
+		checks := Credentials(tt.err)
+		if len(checks) != 3 {
+			t.Fatalf("%s: %d checks", tt.name, len(checks))
+		}
+		for i, name := range []string{"api_key", "wechat_credentials", "ip_whitelist"} {
+			if checks[i].Name != name || checks[i].Status != tt.want[i] {
+				t.Errorf("%s: checks[%d] = %+v, want %s %s", tt.name, i, checks[i], name, tt.want[i])
+			}
+		}
+	}
+
+	checks := Credentials(&api.Error{StatusCode: 200, Code: 40164, Message: "invalid ip 1.2.3.4"})
+	if !strings.Contains(checks[2].Message, "1.2.3.4") || checks[2].Details["error_code"] != "IP_NOT_WHITELISTED" {
+		t.Errorf("whitelist check = %+v", checks[2])
+	}
+}
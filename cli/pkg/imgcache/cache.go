@@ -0,0 +1,233 @@
+// Package imgcache 缓存已上传到微信素材库的图片，避免重复上传消耗素材配额。
+//
+// 本地文件以内容的 SHA-256 为键（文件改名或移动后仍能命中），公网图片以 URL
+// 为键。不同公众号的素材互不通用，记录按 AppID 区分。缓存保存在
+// <dir>/images.json，多个进程通过 images.lock 文件互斥访问，写入采用
+// 临时文件 + 重命名。
+package imgcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/internal/fsutil"
+)
+
+// Entry 已上传图片的缓存记录
+type Entry struct {
+	// Account 上传到的公众号 AppID
+	Account string `json:"account"`
+	// Key 缓存键，见 FileKey 和 URLKey
+	Key string `json:"key"`
+	// Source 最近一次上传时的文件路径或 URL
+	Source  string `json:"source"`
+	URL     string `json:"url"`
+	MediaID string `json:"media_id,omitempty"`
+	Size    int64  `json:"size,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ErrUnavailable 缓存的图片地址已不可访问（素材可能已在公众号后台删除）
+var ErrUnavailable = errors.New("图片地址不可访问")
+
+// lockTimeout 等待锁的最长时间，lockStale 之前未释放的锁视为进程异常退出遗留
+const (
+	lockTimeout = 5 * time.Second
+	lockStale   = 30 * time.Second
+)
+
+// Cache 图片上传缓存
+type Cache struct {
+	dir string
+	now func() time.Time
+}
+
+// New 创建以 dir 为存储目录的缓存
+func New(dir string) *Cache {
+	return &Cache{dir: dir, now: time.Now}
+}
+
+// Path 返回缓存文件路径
+func (c *Cache) Path() string {
+	return filepath.Join(c.dir, "images.json")
+}
+
+// FileKey 返回本地文件内容的缓存键（sha256:<hex>）
+func FileKey(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// URLKey 返回公网图片的缓存键（url:<地址>）
+func URLKey(url string) string {
+	return "url:" + url
+}
+
+// Expired 判断记录在 ttl 有效期内是否已过期，ttl 为 0 表示不过期
+func (e Entry) Expired(ttl time.Duration, now time.Time) bool {
+	return ttl > 0 && now.Sub(e.CreatedAt) > ttl
+}
+
+// Get 返回公众号下 key 对应的记录，超过 ttl 的记录视为不存在
+func (c *Cache) Get(account, key string, ttl time.Duration) (Entry, bool, error) {
+	entries, err := c.load()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	for _, e := range entries {
+		if e.Account == account && e.Key == key {
+			if e.Expired(ttl, c.now()) {
+				return Entry{}, false, nil
+			}
+			return e, true, nil
+		}
+	}
+	return Entry{}, false, nil
+}
+
+// Put 新增或替换记录，创建时间更新为当前时间
+func (c *Cache) Put(entry Entry) error {
+	if entry.Account == "" || entry.Key == "" || entry.URL == "" {
+		return fmt.Errorf("缓存记录缺少 account、key 或 url")
+	}
+	entry.CreatedAt = c.now()
+	return c.update(func(entries []Entry) []Entry {
+		for i, e := range entries {
+			if e.Account == entry.Account && e.Key == entry.Key {
+				entries[i] = entry
+				return entries
+			}
+		}
+		return append(entries, entry)
+	})
+}
+
+// List 返回缓存记录，按创建时间倒序；account 为空时返回全部公众号的记录
+func (c *Cache) List(account string) ([]Entry, error) {
+	entries, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	var result []Entry
+	for _, e := range entries {
+		if account == "" || e.Account == account {
+			result = append(result, e)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result, nil
+}
+
+// Remove 删除 drop 返回 true 的记录（account 为空时检查全部公众号），返回被删除的记录
+func (c *Cache) Remove(account string, drop func(Entry) bool) ([]Entry, error) {
+	var removed []Entry
+	err := c.update(func(entries []Entry) []Entry {
+		kept := entries[:0]
+		for _, e := range entries {
+			if (account == "" || e.Account == account) && drop(e) {
+				removed = append(removed, e)
+				continue
+			}
+			kept = append(kept, e)
+		}
+		return kept
+	})
+	return removed, err
+}
+
+// Delete 删除一条记录，记录不存在时不报错
+func (c *Cache) Delete(account, key string) error {
+	_, err := c.Remove(account, func(e Entry) bool { return e.Key == key })
+	return err
+}
+
+// Prune 删除超过 ttl 的记录，返回被删除的记录
+func (c *Cache) Prune(account string, ttl time.Duration) ([]Entry, error) {
+	now := c.now()
+	return c.Remove(account, func(e Entry) bool { return e.Expired(ttl, now) })
+}
+
+// Verify 检查缓存的图片地址是否仍可访问
+//
+// 地址返回非 2xx 状态时返回包装 ErrUnavailable 的错误；网络错误原样返回，
+// 调用方可据此区分素材失效和暂时无法访问。
+func Verify(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("访问图片地址失败: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: HTTP %d", ErrUnavailable, resp.StatusCode)
+	}
+	return nil
+}
+
+// update 加锁读取、修改并写回
+func (c *Cache) update(fn func([]Entry) []Entry) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := c.load()
+	if err != nil {
+		return err
+	}
+	return c.write(fn(entries))
+}
+
+// load 读取缓存文件，文件不存在时返回空列表
+func (c *Cache) load() ([]Entry, error) {
+	data, err := os.ReadFile(c.Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取图片缓存失败: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("解析图片缓存失败（可使用 'md2wx cache clear' 清空）: %w", err)
+	}
+	return entries, nil
+}
+
+// write 原子写入缓存文件
+func (c *Cache) write(entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFile(c.Path(), data, 0600); err != nil {
+		return fmt.Errorf("写入图片缓存失败: %w", err)
+	}
+	return nil
+}
+
+// lock 锁定缓存文件，其他进程持有锁时等待
+func (c *Cache) lock() (func(), error) {
+	unlock, err := fsutil.Lock(filepath.Join(c.dir, "images.lock"), lockTimeout, lockStale)
+	if err != nil {
+		return nil, fmt.Errorf("锁定图片缓存失败: %w", err)
+	}
+	return unlock, nil
+}
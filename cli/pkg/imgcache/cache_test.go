@@ -0,0 +1,130 @@
+package imgcache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCache(t *testing.T, now time.Time) *Cache {
+	t.Helper()
+	c := New(t.TempDir())
+	c.now = func() time.Time { return now }
+	return c
+}
+
+func TestCache_PutGet(t *testing.T) {
+	created := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	c := newTestCache(t, created)
+	key := FileKey([]byte("logo"))
+
+	if _, found, err := c.Get("wx1", key, 0); found || err != nil {
+		t.Fatalf("Get() on empty cache = %v, %v", found, err)
+	}
+	if err := c.Put(Entry{Account: "wx1", Key: key, Source: "a/logo.png", URL: "https://mmbiz.qpic.cn/1", MediaID: "m1"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	e, found, err := c.Get("wx1", key, 0)
+	if err != nil || !found || e.URL != "https://mmbiz.qpic.cn/1" || !e.CreatedAt.Equal(created) {
+		t.Errorf("Get() = %+v, %v, %v", e, found, err)
+	}
+	// 素材不能跨公众号使用
+	if _, found, _ := c.Get("wx2", key, 0); found {
+		t.Error("Get() found entry of another account")
+	}
+
+	// 超过有效期视为未命中
+	c.now = func() time.Time { return created.Add(2 * time.Hour) }
+	if _, found, _ := c.Get("wx1", key, time.Hour); found {
+		t.Error("Get() returned expired entry")
+	}
+	if _, found, _ := c.Get("wx1", key, 3*time.Hour); !found {
+		t.Error("Get() within ttl not found")
+	}
+
+	// 重新上传后替换记录
+	if err := c.Put(Entry{Account: "wx1", Key: key, Source: "b/logo.png", URL: "https://mmbiz.qpic.cn/2"}); err != nil {
+		t.Fatal(err)
+	}
+	list, err := c.List("")
+	if err != nil || len(list) != 1 || list[0].URL != "https://mmbiz.qpic.cn/2" {
+		t.Errorf("List() after replace = %+v, %v", list, err)
+	}
+}
+
+func TestCache_PutInvalid(t *testing.T) {
+	c := newTestCache(t, time.Now())
+	if err := c.Put(Entry{Account: "wx1", Key: URLKey("https://example.com/a.png")}); err == nil {
+		t.Error("Put() without url should fail")
+	}
+}
+
+func TestCache_PruneRemove(t *testing.T) {
+	base := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	c := newTestCache(t, base)
+	for i, account := range []string{"wx1", "wx1", "wx2"} {
+		c.now = func() time.Time { return base.Add(time.Duration(i) * time.Hour) }
+		if err := c.Put(Entry{Account: account, Key: URLKey(string(rune('a' + i))), URL: "https://mmbiz.qpic.cn/x"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list, _ := c.List("wx1")
+	if len(list) != 2 || list[0].Key != "url:b" {
+		t.Errorf("List(wx1) = %+v, want newest first", list)
+	}
+
+	// 只清理指定公众号中过期的记录
+	c.now = func() time.Time { return base.Add(3 * time.Hour) }
+	removed, err := c.Prune("wx1", 150*time.Minute)
+	if err != nil || len(removed) != 1 || removed[0].Key != "url:a" {
+		t.Errorf("Prune() = %+v, %v", removed, err)
+	}
+	if removed, _ := c.Prune("", 0); len(removed) != 0 {
+		t.Errorf("Prune(ttl=0) removed %+v", removed)
+	}
+
+	if err := c.Delete("wx2", "url:c"); err != nil {
+		t.Fatal(err)
+	}
+	removed, err = c.Remove("", func(Entry) bool { return true })
+	if err != nil || len(removed) != 1 || removed[0].Key != "url:b" {
+		t.Errorf("Remove(all) = %+v, %v", removed, err)
+	}
+	data, _ := os.ReadFile(c.Path())
+	if strings.TrimSpace(string(data)) != "[]" {
+		t.Errorf("cache file after clear = %q", data)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	if err := Verify(ctx, server.Client(), server.URL+"/ok"); err != nil {
+		t.Errorf("Verify(ok) error = %v", err)
+	}
+	if err := Verify(ctx, server.Client(), server.URL+"/gone"); !errors.Is(err, ErrUnavailable) || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Verify(gone) error = %v, want ErrUnavailable with 404", err)
+	}
+
+	// 网络错误不视为素材失效
+	server.Close()
+	if err := Verify(ctx, server.Client(), server.URL+"/ok"); err == nil || errors.Is(err, ErrUnavailable) {
+		t.Errorf("Verify(closed server) error = %v, want network error", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Package fsutil 提供多个进程共享状态文件时使用的锁文件和原子写入。
+//
+// 锁以 O_EXCL 创建的锁文件实现，持有期间定期更新修改时间；进程异常退出后
+// 锁文件不再更新，超过 stale 后由其他进程接管。写入采用临时文件 + 重命名，
+// 读取方不会看到写了一半的文件。
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrBusy 锁被其他进程持有且等待超时
+var ErrBusy = errors.New("被其他进程占用")
+
+// pollInterval 等待锁时的检查间隔
+const pollInterval = 50 * time.Millisecond
+
+// Lock 创建锁文件 path，其他进程持有锁时最多等待 timeout（0 表示不等待）
+//
+// 修改时间早于 stale 的锁视为异常退出遗留，直接接管。持有期间每 stale/3
+// 更新一次修改时间，长时间持有的锁不会被误判为遗留。返回的 unlock 释放锁，
+// 可重复调用。
+func Lock(path string, timeout, stale time.Duration) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return hold(path, stale), nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("创建锁文件失败: %w", err)
+		}
+
+		// 接管异常退出遗留的锁
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > stale {
+			if takeStale(path, info, stale) {
+				continue
+			}
+		}
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("%s %w", path, ErrBusy)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// takeStale 删除判定为遗留的锁文件 path，info 为判定时的状态
+//
+// 判定和删除之间其他进程可能已接管并重新创建了锁，直接删除会误删有效的锁。
+// 这里先把锁文件重命名为唯一的临时名（重命名是原子的，同一个文件只有一个
+// 进程能成功），再确认拿到的仍是判定为遗留的那个文件：是则删除，否则说明
+// 拿到的是新锁，原样放回。返回 false 表示锁有效或已被其他进程接管。
+func takeStale(path string, info os.FileInfo, stale time.Duration) bool {
+	tmp := fmt.Sprintf("%s.stale.%d.%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, tmp); err != nil {
+		// 已被其他进程接管或释放，重新尝试创建
+		return os.IsNotExist(err)
+	}
+	got, err := os.Stat(tmp)
+	if err == nil && (!os.SameFile(info, got) || time.Since(got.ModTime()) <= stale) {
+		// 放回有效的锁；Link 在 path 已存在时失败，不会覆盖其他进程新建的锁
+		os.Link(tmp, path)
+		os.Remove(tmp)
+		return false
+	}
+	os.Remove(tmp)
+	return true
+}
+
+// hold 定期更新锁文件的修改时间，返回停止更新并删除锁文件的函数
+func hold(path string, stale time.Duration) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(max(stale/3, pollInterval))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				os.Chtimes(path, now, now)
+			}
+		}
+	}()
+
+	released := false
+	return func() {
+		if released {
+			return
+		}
+		released = true
+		close(stop)
+		<-done
+		os.Remove(path)
+	}
+}
+
+// WriteFile 通过临时文件 + 重命名原子写入 path，必要时创建目录
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
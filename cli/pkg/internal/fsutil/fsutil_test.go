@@ -0,0 +1,108 @@
+package fsutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLock_Exclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "a.lock")
+	unlock, err := Lock(path, 0, time.Minute)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if _, err := Lock(path, 100*time.Millisecond, time.Minute); !errors.Is(err, ErrBusy) {
+		t.Errorf("second Lock() error = %v, want ErrBusy", err)
+	}
+
+	unlock()
+	unlock()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("lock file after unlock: %v", err)
+	}
+	again, err := Lock(path, 0, time.Minute)
+	if err != nil {
+		t.Fatalf("Lock() after unlock error = %v", err)
+	}
+	again()
+}
+
+func TestLock_Stale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.lock")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(path, old, old)
+
+	// 异常退出遗留的锁被接管
+	unlock, err := Lock(path, 0, time.Minute)
+	if err != nil {
+		t.Fatalf("Lock(stale) error = %v", err)
+	}
+	defer unlock()
+}
+
+func TestTakeStale_Replaced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.lock")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(path, old, old)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 判定为遗留后，其他进程先一步接管并创建了新锁
+	os.Remove(path)
+	if err := os.WriteFile(path, []byte("other"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if takeStale(path, info, time.Minute) {
+		t.Fatal("takeStale() removed a lock created after the stale check")
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "other" {
+		t.Errorf("lock file = %q, %v; want the new lock restored", data, err)
+	}
+	if matches, _ := filepath.Glob(path + ".stale.*"); len(matches) != 0 {
+		t.Errorf("temp files left behind: %v", matches)
+	}
+}
+
+func TestLock_Refresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.lock")
+	stale := 300 * time.Millisecond
+	unlock, err := Lock(path, 0, stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	// 持有时间超过 stale 时锁仍然有效
+	time.Sleep(2 * stale)
+	if _, err := Lock(path, 0, stale); !errors.Is(err, ErrBusy) {
+		t.Errorf("Lock() on held lock error = %v, want ErrBusy", err)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dir", "a.json")
+	if err := WriteFile(path, []byte("1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "2" {
+		t.Errorf("ReadFile() = %q, %v", data, err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+// Package manifest 解析多图文草稿的清单文件。
+//
+// 清单为 YAML 格式，顶层字段作为所有文章的默认值，articles 按顺序列出文章：
+//
+//	theme: default
+//	author: 编辑部
+//	articles:
+//	  - file: posts/a.md
+//	    theme: bytedance
+//	    cover: ./img/a.png
+//	  - file: posts/b.md
+//	    title: 第二篇
+//
+// file 和本地 cover 路径以清单文件所在目录为基准。
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Article 清单中的一篇文章，空值表示未指定
+type Article struct {
+	File           string
+	Theme          string
+	FontSize       string
+	BackgroundType string
+	Cover          string
+	Title          string
+	Author         string
+	Digest         string
+	SourceURL      string
+	Crop235        string
+	Crop11         string
+
+	NeedOpenComment    *bool
+	OnlyFansCanComment *bool
+}
+
+// SyntaxError 清单内容错误，Line 为所在行号（从 1 开始）
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+// Error 实现 error 接口
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("第 %d 行: %s", e.Line, e.Msg)
+}
+
+// Manifest 清单文件
+type Manifest struct {
+	// Path 清单文件路径
+	Path string
+	// Defaults 顶层字段，作为每篇文章的默认值
+	Defaults Article
+	// Articles 文章列表，File 和本地 Cover 已解析为相对当前目录的路径
+	Articles []Article
+}
+
+// articleKeys 文章支持的字段
+var articleKeys = map[string]func(a *Article) *string{
+	"file":            func(a *Article) *string { return &a.File },
+	"theme":           func(a *Article) *string { return &a.Theme },
+	"font_size":       func(a *Article) *string { return &a.FontSize },
+	"background_type": func(a *Article) *string { return &a.BackgroundType },
+	"cover":           func(a *Article) *string { return &a.Cover },
+	"title":           func(a *Article) *string { return &a.Title },
+	"author":          func(a *Article) *string { return &a.Author },
+	"digest":          func(a *Article) *string { return &a.Digest },
+	"source_url":      func(a *Article) *string { return &a.SourceURL },
+	"crop_235":        func(a *Article) *string { return &a.Crop235 },
+	"crop_1_1":        func(a *Article) *string { return &a.Crop11 },
+}
+
+// articleBoolKeys 文章支持的布尔字段
+var articleBoolKeys = map[string]func(a *Article) **bool{
+	"need_open_comment":     func(a *Article) **bool { return &a.NeedOpenComment },
+	"only_fans_can_comment": func(a *Article) **bool { return &a.OnlyFansCanComment },
+}
+
+// Load 读取并解析清单文件
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取清单文件失败: %w", err)
+	}
+	m, err := Parse(data, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	m.Path = path
+	return m, nil
+}
+
+// Parse 解析清单内容，baseDir 为相对路径的基准目录
+func Parse(data []byte, baseDir string) (*Manifest, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("清单缺少 articles 列表")
+	}
+	root := resolveAlias(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return nil, &SyntaxError{Line: root.Line, Msg: "清单必须是键值对"}
+	}
+
+	m := &Manifest{}
+	var articles *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], resolveAlias(root.Content[i+1])
+		switch key.Value {
+		case "articles":
+			articles = value
+		case "file":
+			return nil, &SyntaxError{Line: key.Line, Msg: "file 只能出现在 articles 中"}
+		default:
+			if err := setField(&m.Defaults, key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if articles == nil || articles.Kind != yaml.SequenceNode || len(articles.Content) == 0 {
+		return nil, fmt.Errorf("清单缺少 articles 列表")
+	}
+
+	for i, item := range articles.Content {
+		item = resolveAlias(item)
+		var a Article
+		switch item.Kind {
+		case yaml.ScalarNode:
+			// 简写：- posts/a.md
+			a.File = scalarValue(item)
+		case yaml.MappingNode:
+			for j := 0; j+1 < len(item.Content); j += 2 {
+				if err := setField(&a, item.Content[j], resolveAlias(item.Content[j+1])); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, &SyntaxError{Line: item.Line, Msg: "文章必须是文件路径或键值对"}
+		}
+
+		if a.File == "" {
+			return nil, &SyntaxError{Line: item.Line, Msg: fmt.Sprintf("第 %d 篇文章缺少 file", i+1)}
+		}
+		a.File = resolve(baseDir, a.File)
+		a.Cover = resolveCover(baseDir, a.Cover)
+		m.Articles = append(m.Articles, a)
+	}
+	m.Defaults.Cover = resolveCover(baseDir, m.Defaults.Cover)
+
+	return m, nil
+}
+
+// setField 将键值对写入文章字段，未知字段报错
+func setField(a *Article, key, value *yaml.Node) error {
+	if field, ok := articleKeys[key.Value]; ok {
+		if value.Kind != yaml.ScalarNode {
+			return &SyntaxError{Line: key.Line, Msg: fmt.Sprintf("%s 必须是字符串", key.Value)}
+		}
+		*field(a) = strings.TrimSpace(scalarValue(value))
+		return nil
+	}
+	if field, ok := articleBoolKeys[key.Value]; ok {
+		var b bool
+		if value.Kind != yaml.ScalarNode || value.Decode(&b) != nil {
+			return &SyntaxError{Line: key.Line, Msg: fmt.Sprintf("%s: 无效的布尔值: %q", key.Value, value.Value)}
+		}
+		*field(a) = &b
+		return nil
+	}
+	return &SyntaxError{Line: key.Line, Msg: fmt.Sprintf("未知字段: %s", key.Value)}
+}
+
+// scalarValue 返回标量的字符串值，空值（~、null）返回空字符串
+func scalarValue(n *yaml.Node) string {
+	if n.Tag == "!!null" {
+		return ""
+	}
+	return n.Value
+}
+
+// resolveAlias 返回别名 (*name) 指向的节点
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+// resolve 将相对路径解析为以 baseDir 为基准的路径
+func resolve(baseDir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+// resolveCover 本地封面路径以 baseDir 为基准，远程地址原样返回
+func resolveCover(baseDir, cover string) string {
+	lower := strings.ToLower(cover)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(cover, "//") {
+		return cover
+	}
+	return resolve(baseDir, cover)
+}
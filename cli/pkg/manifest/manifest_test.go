@@ -0,0 +1,102 @@
+package manifest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	data := []byte(`theme: default
+author: 编辑部
+need_open_comment: true
+articles:
+  - file: posts/a.md
+    theme: bytedance
+    cover: ./img/a.png
+  - file: /abs/b.md
+    title: 第二篇
+    cover: https://cdn.example.com/b.jpg
+    only_fans_can_comment: no
+  - posts/c.md
+`)
+	m, err := Parse(data, "digest")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if m.Defaults.Theme != "default" || m.Defaults.Author != "编辑部" {
+		t.Errorf("Defaults = %+v", m.Defaults)
+	}
+	if m.Defaults.NeedOpenComment == nil || !*m.Defaults.NeedOpenComment {
+		t.Error("Defaults.NeedOpenComment should be true")
+	}
+	if len(m.Articles) != 3 {
+		t.Fatalf("len(Articles) = %d, want 3", len(m.Articles))
+	}
+
+	a := m.Articles[0]
+	if a.File != filepath.Join("digest", "posts/a.md") || a.Cover != filepath.Join("digest", "img/a.png") || a.Theme != "bytedance" {
+		t.Errorf("Articles[0] = %+v", a)
+	}
+	b := m.Articles[1]
+	if b.File != "/abs/b.md" || b.Cover != "https://cdn.example.com/b.jpg" || b.Title != "第二篇" {
+		t.Errorf("Articles[1] = %+v", b)
+	}
+	if b.OnlyFansCanComment == nil || *b.OnlyFansCanComment {
+		t.Error("Articles[1].OnlyFansCanComment should be false")
+	}
+	if m.Articles[2].File != filepath.Join("digest", "posts/c.md") {
+		t.Errorf("Articles[2].File = %s", m.Articles[2].File)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		line int
+	}{
+		{"unknown field", "articles:\n  - file: a.md\n    colour: red\n", 3},
+		{"missing file", "articles:\n  - title: x\n", 2},
+		{"top-level file", "file: a.md\narticles:\n  - a.md\n", 1},
+		{"bad bool", "need_open_comment: maybe\narticles:\n  - a.md\n", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data), ".")
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse() error = %v, want SyntaxError", err)
+			}
+			if syntaxErr.Line != tt.line {
+				t.Errorf("Line = %d, want %d", syntaxErr.Line, tt.line)
+			}
+		})
+	}
+
+	if _, err := Parse([]byte("theme: default\n"), "."); err == nil {
+		t.Error("manifest without articles should fail")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "digest.yaml")
+	if err := os.WriteFile(path, []byte("articles:\n  - a.md\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if m.Path != path || m.Articles[0].File != filepath.Join(dir, "a.md") {
+		t.Errorf("Load() = %+v", m)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Load() missing file should fail")
+	}
+}
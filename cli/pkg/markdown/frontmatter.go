@@ -0,0 +1,207 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter 文章头部元数据
+//
+// 支持 YAML（--- 包裹）和 TOML（+++ 包裹）两种格式。
+type FrontMatter struct {
+	Title          string `json:"title,omitempty"`
+	Author         string `json:"author,omitempty"`
+	Digest         string `json:"digest,omitempty"`
+	Cover          string `json:"cover,omitempty"`
+	Theme          string `json:"theme,omitempty"`
+	FontSize       string `json:"font_size,omitempty"`
+	BackgroundType string `json:"background_type,omitempty"`
+	SourceURL      string `json:"source_url,omitempty"`
+
+	// 评论设置，未出现时为 nil
+	NeedOpenComment    *bool `json:"need_open_comment,omitempty"`
+	OnlyFansCanComment *bool `json:"only_fans_can_comment,omitempty"`
+}
+
+// frontMatterKeys 字段别名，兼容 Hugo/Hexo 等常见写法
+var frontMatterKeys = map[string][]string{
+	"title":           {"title"},
+	"author":          {"author"},
+	"digest":          {"digest", "description", "summary"},
+	"cover":           {"cover", "cover_image", "coverImage", "image"},
+	"theme":           {"theme"},
+	"font_size":       {"font_size", "fontSize"},
+	"background_type": {"background_type", "backgroundType"},
+	"source_url":      {"source_url", "sourceUrl", "content_source_url", "original_url"},
+
+	"need_open_comment":     {"need_open_comment", "open_comment"},
+	"only_fans_can_comment": {"only_fans_can_comment", "fans_only_comment"},
+}
+
+// SplitFrontMatter 拆分 front matter 和正文
+//
+// 没有 front matter 时返回 nil 和原文。--- 后紧跟空行时视为 Markdown 分隔线
+// 而不是 front matter。
+func SplitFrontMatter(md string) (*FrontMatter, string, error) {
+	content := strings.TrimPrefix(md, "\ufeff")
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+
+	var delim string
+	switch {
+	case strings.HasPrefix(normalized, "---\n"):
+		delim = "---"
+	case strings.HasPrefix(normalized, "+++\n"):
+		delim = "+++"
+	default:
+		return nil, md, nil
+	}
+
+	lines := strings.SplitAfter(normalized, "\n")
+	if delim == "---" && (len(lines) < 2 || strings.TrimSpace(lines[1]) == "") {
+		return nil, md, nil
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t\n") == delim {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, "", fmt.Errorf("front matter 缺少结束标记 %s", delim)
+	}
+
+	header := strings.Join(lines[1:end], "")
+	body := strings.TrimLeft(strings.Join(lines[end+1:], ""), "\n")
+
+	var fields map[string]any
+	var err error
+	if delim == "---" {
+		fields, err = parseYAML(header)
+	} else {
+		fields, err = parseTOML(header)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("解析 front matter 失败: %w", err)
+	}
+
+	fm, err := newFrontMatter(fields)
+	if err != nil {
+		return nil, "", fmt.Errorf("解析 front matter 失败: %w", err)
+	}
+	return fm, body, nil
+}
+
+// newFrontMatter 从解析结果中提取已知字段，未知字段忽略
+func newFrontMatter(fields map[string]any) (*FrontMatter, error) {
+	lookup := func(field string) any {
+		for _, key := range frontMatterKeys[field] {
+			if v, ok := fields[key]; ok {
+				return v
+			}
+		}
+		return nil
+	}
+
+	fm := &FrontMatter{}
+	for field, dst := range map[string]*string{
+		"title":           &fm.Title,
+		"author":          &fm.Author,
+		"digest":          &fm.Digest,
+		"cover":           &fm.Cover,
+		"theme":           &fm.Theme,
+		"font_size":       &fm.FontSize,
+		"background_type": &fm.BackgroundType,
+		"source_url":      &fm.SourceURL,
+	} {
+		s, err := scalarString(lookup(field))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		*dst = strings.TrimSpace(s)
+	}
+
+	for field, dst := range map[string]**bool{
+		"need_open_comment":     &fm.NeedOpenComment,
+		"only_fans_can_comment": &fm.OnlyFansCanComment,
+	} {
+		v := lookup(field)
+		if v == nil {
+			continue
+		}
+		b, err := parseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		*dst = &b
+	}
+	return fm, nil
+}
+
+// scalarString 将标量值转换为字符串，nil 返回空字符串
+func scalarString(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339), nil
+	}
+	return "", fmt.Errorf("必须是字符串")
+}
+
+// parseBool 解析布尔值，兼容 yes/no/on/off 写法
+func parseBool(v any) (bool, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	s, _ := scalarString(v)
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("无效的布尔值: %v", v)
+}
+
+// parseYAML 解析 YAML front matter，顶层必须是映射
+func parseYAML(s string) (map[string]any, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(s), &node); err != nil {
+		return nil, err
+	}
+	// 空文档
+	if len(node.Content) == 0 {
+		return map[string]any{}, nil
+	}
+	if node.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("front matter 必须是键值对")
+	}
+	fields := map[string]any{}
+	if err := node.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// parseTOML 解析 TOML front matter
+func parseTOML(s string) (map[string]any, error) {
+	fields := map[string]any{}
+	if _, err := toml.Decode(s, &fields); err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("第 %d 行: %s", parseErr.Position.Line, parseErr.Message)
+		}
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,106 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitFrontMatter_YAML(t *testing.T) {
+	md := "---\ntitle: \"你好: 世界\"\nauthor: 张三\ndescription: 摘要\ncover: ./cover.png\ntheme: bytedance\nfontSize: large\ntags:\n  - go\n  - wechat\nsource_url: https://example.com/post\nunknown: x\n---\n\n# 正文\n"
+	fm, body, err := SplitFrontMatter(md)
+	if err != nil {
+		t.Fatalf("SplitFrontMatter() error = %v", err)
+	}
+
+	want := &FrontMatter{
+		Title:     "你好: 世界",
+		Author:    "张三",
+		Digest:    "摘要",
+		Cover:     "./cover.png",
+		Theme:     "bytedance",
+		FontSize:  "large",
+		SourceURL: "https://example.com/post",
+	}
+	if !reflect.DeepEqual(fm, want) {
+		t.Errorf("FrontMatter = %+v, want %+v", fm, want)
+	}
+	if body != "# 正文\n" {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestSplitFrontMatter_TOML(t *testing.T) {
+	md := "+++\ntitle = \"Hello\" # 注释\ntags = [\"a\", \"b\"]\nbackground_type = 'grid'\n\n[extra]\nx = 1\n+++\nbody\n"
+	fm, body, err := SplitFrontMatter(md)
+	if err != nil {
+		t.Fatalf("SplitFrontMatter() error = %v", err)
+	}
+	if fm.Title != "Hello" || fm.BackgroundType != "grid" {
+		t.Errorf("FrontMatter = %+v", fm)
+	}
+	if body != "body\n" {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestSplitFrontMatter_None(t *testing.T) {
+	for _, md := range []string{
+		"# 标题\n\n---\n",
+		"----\ntitle: x\n----\n",
+		"",
+		// 以分隔线开头的文档
+		"---\n\n正文: 第一段\n\n---\n\n第二段\n",
+		"---\n",
+	} {
+		fm, body, err := SplitFrontMatter(md)
+		if err != nil || fm != nil || body != md {
+			t.Errorf("SplitFrontMatter(%q) = %v, %q, %v", md, fm, body, err)
+		}
+	}
+}
+
+func TestSplitFrontMatter_Errors(t *testing.T) {
+	tests := map[string]string{
+		"unterminated": "---\ntitle: x\n# 正文\n",
+		"bad yaml":     "---\ntitle x\n---\n",
+		"bad toml":     "+++\ntitle: x\n+++\n",
+		"list":         "---\n- a\n---\n",
+		"bad bool":     "---\nopen_comment: maybe\n---\n",
+	}
+	for name, md := range tests {
+		if _, _, err := SplitFrontMatter(md); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	_, _, err := SplitFrontMatter("+++\ntitle = \"a\"\nauthor = \"b\n+++\n")
+	if err == nil || !strings.Contains(err.Error(), "第 2 行") {
+		t.Errorf("TOML error should carry line number, got %v", err)
+	}
+}
+
+func TestSplitFrontMatter_CRLF(t *testing.T) {
+	fm, body, err := SplitFrontMatter("\ufeff---\r\ntitle: x\r\n---\r\nbody\r\n")
+	if err != nil || fm == nil || fm.Title != "x" || body != "body\n" {
+		t.Errorf("SplitFrontMatter() = %+v, %q, %v", fm, body, err)
+	}
+}
+
+func TestSplitFrontMatter_Comments(t *testing.T) {
+	fm, _, err := SplitFrontMatter("---\nopen_comment: yes\nonly_fans_can_comment: false\n---\nbody\n")
+	if err != nil {
+		t.Fatalf("SplitFrontMatter() error = %v", err)
+	}
+	if fm.NeedOpenComment == nil || !*fm.NeedOpenComment {
+		t.Errorf("NeedOpenComment = %v, want true", fm.NeedOpenComment)
+	}
+	if fm.OnlyFansCanComment == nil || *fm.OnlyFansCanComment {
+		t.Errorf("OnlyFansCanComment = %v, want false", fm.OnlyFansCanComment)
+	}
+
+	fm, _, _ = SplitFrontMatter("---\ntitle: x\n---\n")
+	if fm.NeedOpenComment != nil || fm.OnlyFansCanComment != nil {
+		t.Error("comment settings should be nil when absent")
+	}
+}
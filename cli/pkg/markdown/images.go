@@ -0,0 +1,133 @@
+// Package markdown 提供 Markdown 文本的轻量处理功能。
+//
+// 不做完整的 Markdown 解析，只识别发布流程需要的结构：
+//   - 图片引用：![alt](path "title") 和 <img src="path">
+//   - 文件开头的 YAML / TOML front matter
+//
+// 围栏代码块（``` 或 ~~~）中的内容会被跳过。
+package markdown
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// mdImagePattern Markdown 图片语法，第 1 组为尖括号地址，第 2 组为普通地址
+	mdImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(\s*(?:<([^>]+)>|([^)\s]+))(?:\s+(?:"[^"]*"|'[^']*'))?\s*\)`)
+	// htmlImagePattern HTML img 标签的 src 属性，第 1/2 组分别为双引号/单引号地址
+	htmlImagePattern = regexp.MustCompile(`(?i)<img\b[^>]*?\bsrc\s*=\s*(?:"([^"]+)"|'([^']+)')`)
+)
+
+// ImageURLs 返回 Markdown 中引用的所有图片地址（去重，保持出现顺序）
+func ImageURLs(md string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	eachImage(md, func(u string) string {
+		if !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+		return u
+	})
+	return urls
+}
+
+// LocalImages 返回 Markdown 中引用的本地图片路径（去重，保持出现顺序）
+func LocalImages(md string) []string {
+	var paths []string
+	for _, u := range ImageURLs(md) {
+		if IsLocalPath(u) {
+			paths = append(paths, u)
+		}
+	}
+	return paths
+}
+
+// RewriteImages 按 mapping 替换图片地址，未出现在 mapping 中的地址保持不变
+func RewriteImages(md string, mapping map[string]string) string {
+	return eachImage(md, func(u string) string {
+		if replaced, ok := mapping[u]; ok {
+			return replaced
+		}
+		return u
+	})
+}
+
+// IsLocalPath 判断图片地址是否为本地文件路径
+//
+// http(s)、协议相对地址 (//cdn...) 和 data URI 视为远程地址。
+func IsLocalPath(u string) bool {
+	lower := strings.ToLower(u)
+	switch {
+	case u == "":
+		return false
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		return false
+	case strings.HasPrefix(u, "//"), strings.HasPrefix(lower, "data:"):
+		return false
+	}
+	return true
+}
+
+// LocalFilePath 将本地图片地址转换为文件路径
+//
+// 去除 file:// 前缀、查询参数和锚点，并解码 URL 转义（如 %20）。
+func LocalFilePath(u string) string {
+	p := strings.TrimPrefix(u, "file://")
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	if decoded, err := url.PathUnescape(p); err == nil {
+		p = decoded
+	}
+	return p
+}
+
+// eachImage 逐个处理围栏代码块以外的图片地址，用 fn 的返回值替换原地址
+func eachImage(md string, fn func(u string) string) string {
+	lines := strings.SplitAfter(md, "\n")
+	var fence string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		line = replaceSubmatch(mdImagePattern, line, fn)
+		line = replaceSubmatch(htmlImagePattern, line, fn)
+		lines[i] = line
+	}
+	return strings.Join(lines, "")
+}
+
+// replaceSubmatch 替换每个匹配中第一个非空的捕获组
+func replaceSubmatch(re *regexp.Regexp, s string, fn func(string) string) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		for g := 1; g*2+1 < len(m); g++ {
+			start, end := m[g*2], m[g*2+1]
+			if start < 0 {
+				continue
+			}
+			sb.WriteString(s[last:start])
+			sb.WriteString(fn(s[start:end]))
+			last = end
+			break
+		}
+	}
+	sb.WriteString(s[last:])
+	return sb.String()
+}
@@ -0,0 +1,94 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+const sampleMarkdown = `# 标题
+
+![封面](./img/cover.png)
+
+正文 ![logo](<img/my logo.png> "Logo") 与远程图 ![r](https://cdn.example.com/r.jpg)
+
+<img src="./img/banner.jpg" width="100">
+
+` + "```markdown\n![忽略](./img/in-code.png)\n```" + `
+
+再次引用 ![封面](./img/cover.png)
+`
+
+func TestImageURLs(t *testing.T) {
+	got := ImageURLs(sampleMarkdown)
+	want := []string{
+		"./img/cover.png",
+		"img/my logo.png",
+		"https://cdn.example.com/r.jpg",
+		"./img/banner.jpg",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ImageURLs() = %v, want %v", got, want)
+	}
+}
+
+func TestLocalImages(t *testing.T) {
+	got := LocalImages(sampleMarkdown)
+	want := []string{
+		"./img/cover.png",
+		"img/my logo.png",
+		"./img/banner.jpg",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LocalImages() = %v, want %v", got, want)
+	}
+}
+
+func TestRewriteImages(t *testing.T) {
+	md := "![a](./a.png) ![b](<b c.png> \"t\")\n<img src='./d.jpg'>\n```\n![a](./a.png)\n```\n"
+	got := RewriteImages(md, map[string]string{
+		"./a.png": "https://mmbiz.qpic.cn/a",
+		"b c.png": "https://mmbiz.qpic.cn/b",
+		"./d.jpg": "https://mmbiz.qpic.cn/d",
+	})
+	want := "![a](https://mmbiz.qpic.cn/a) ![b](<https://mmbiz.qpic.cn/b> \"t\")\n<img src='https://mmbiz.qpic.cn/d'>\n```\n![a](./a.png)\n```\n"
+	if got != want {
+		t.Errorf("RewriteImages() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestIsLocalPath(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"./a.png", true},
+		{"img/a.png", true},
+		{"/abs/a.png", true},
+		{"file:///tmp/a.png", true},
+		{"https://cdn.example.com/a.png", false},
+		{"HTTP://cdn.example.com/a.png", false},
+		{"//cdn.example.com/a.png", false},
+		{"data:image/png;base64,xxx", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsLocalPath(tt.url); got != tt.want {
+			t.Errorf("IsLocalPath(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestLocalFilePath(t *testing.T) {
+	tests := map[string]string{
+		"./img/a.png":          "./img/a.png",
+		"img/my%20logo.png":    "img/my logo.png",
+		"file:///tmp/a.png":    "/tmp/a.png",
+		"./a.png?v=2#fragment": "./a.png",
+	}
+	for in, want := range tests {
+		if got := LocalFilePath(in); got != want {
+			t.Errorf("LocalFilePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -1,13 +1,18 @@
 // Package output 提供统一的输出格式化功能。
 //
 // 所有输出为 JSON 格式，包含 success 字段表示操作是否成功。
-// 成功时包含 data 字段，失败时包含 error 字段。
+// 成功时包含 data 字段，失败时包含 error 字段；错误实现 CodedError
+// 时额外输出稳定的 code 字段，便于脚本按错误类型分支处理。
+// 批量处理的命令用 Line 逐条输出单行 JSON（NDJSON）。
 package output
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
 )
 
 // SuccessResponse 成功响应
@@ -18,9 +23,22 @@ type SuccessResponse struct {
 
 // ErrorResponse 错误响应
 type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
-	Code    string `json:"code,omitempty"`
+	Success bool           `json:"success"`
+	Error   string         `json:"error,omitempty"`
+	Code    string         `json:"code,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// CodedError 可提供机器可读错误码的错误
+type CodedError interface {
+	error
+	ErrorCode() string
+}
+
+// DetailedError 可提供附加诊断信息的错误
+type DetailedError interface {
+	error
+	ErrorDetails() map[string]any
 }
 
 // Success 输出成功响应
@@ -33,13 +51,30 @@ func Success(data interface{}) {
 }
 
 // Error 输出错误响应
+//
+// 错误链中包含 CodedError / DetailedError 时输出对应的 code 和 details。
 func Error(err error) {
+	printJSON(NewErrorResponse(err))
+	os.Exit(1)
+}
+
+// NewErrorResponse 根据 err 构建错误响应，不输出
+//
+// 用于在逐行输出（Line）的记录中嵌入错误信息。
+func NewErrorResponse(err error) ErrorResponse {
 	resp := ErrorResponse{
 		Success: false,
 		Error:   err.Error(),
 	}
-	printJSON(resp)
-	os.Exit(1)
+	var coded CodedError
+	if errors.As(err, &coded) {
+		resp.Code = coded.ErrorCode()
+	}
+	var detailed DetailedError
+	if errors.As(err, &detailed) {
+		resp.Details = detailed.ErrorDetails()
+	}
+	return resp
 }
 
 // ErrorWithCode 输出带错误码的错误响应
@@ -53,6 +88,25 @@ func ErrorWithCode(code, message string) {
 	os.Exit(1)
 }
 
+// lineMu 保证并发调用 Line 时每条记录完整输出
+var lineMu sync.Mutex
+
+// Line 以单行 JSON 输出一条记录（NDJSON），可被多个 goroutine 并发调用
+//
+// 用于批量处理时逐条输出结果，调用方可以边处理边读取。
+func Line(v interface{}) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "JSON 编码错误: %v\n", err)
+		return
+	}
+	lineMu.Lock()
+	defer lineMu.Unlock()
+	os.Stdout.Write(buf.Bytes())
+}
+
 // printJSON 打印 JSON
 func printJSON(v interface{}) {
 	encoder := json.NewEncoder(os.Stdout)
@@ -3,6 +3,8 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"testing"
 )
@@ -103,3 +105,58 @@ func TestPrintErrorMessage(t *testing.T) {
 		t.Errorf("output = %q, want 'error message: value\\n'", output)
 	}
 }
+
+// testCodedError 用于测试的带错误码错误
+type testCodedError struct{}
+
+func (testCodedError) Error() string                { return "invalid media_id" }
+func (testCodedError) ErrorCode() string            { return "INVALID_MEDIA_ID" }
+func (testCodedError) ErrorDetails() map[string]any { return map[string]any{"api_code": 40007} }
+
+func TestNewErrorResponse(t *testing.T) {
+	resp := NewErrorResponse(errors.New("plain error"))
+	if resp.Success {
+		t.Error("success = true, want false")
+	}
+	if resp.Error != "plain error" {
+		t.Errorf("error = %q, want 'plain error'", resp.Error)
+	}
+	if resp.Code != "" || resp.Details != nil {
+		t.Errorf("plain error should have no code/details, got %q %v", resp.Code, resp.Details)
+	}
+}
+
+func TestNewErrorResponse_Coded(t *testing.T) {
+	err := fmt.Errorf("创建草稿失败: %w", testCodedError{})
+	resp := NewErrorResponse(err)
+
+	if resp.Code != "INVALID_MEDIA_ID" {
+		t.Errorf("code = %q, want INVALID_MEDIA_ID", resp.Code)
+	}
+	if resp.Details["api_code"] != 40007 {
+		t.Errorf("details = %v, want api_code 40007", resp.Details)
+	}
+	if resp.Error != "创建草稿失败: invalid media_id" {
+		t.Errorf("error = %q", resp.Error)
+	}
+}
+
+func TestLine(t *testing.T) {
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	Line(map[string]any{"file": "a.md", "html": "<p>"})
+	Line(map[string]any{"file": "b.md"})
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+
+	want := "{\"file\":\"a.md\",\"html\":\"<p>\"}\n{\"file\":\"b.md\"}\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
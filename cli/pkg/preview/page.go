@@ -0,0 +1,58 @@
+package preview
+
+import "html/template"
+
+// pageData 预览页面模板数据
+type pageData struct {
+	Title     string
+	Content   template.HTML
+	Error     string
+	Version   int
+	UpdatedAt string
+}
+
+// pageTemplate 预览页面，模拟微信手机端文章阅读界面
+var pageTemplate = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} - md2wx 预览</title>
+<style>
+  body { margin: 0; background: #ededed; font-family: -apple-system, BlinkMacSystemFont, "PingFang SC", "Microsoft YaHei", sans-serif; }
+  .toolbar { text-align: center; padding: 12px; color: #888; font-size: 13px; }
+  .phone { width: 375px; height: 760px; margin: 0 auto 24px; background: #fff; border: 12px solid #1f1f1f; border-radius: 40px; overflow: hidden; display: flex; flex-direction: column; box-shadow: 0 12px 40px rgba(0, 0, 0, .25); }
+  .statusbar { height: 24px; background: #f7f7f7; font-size: 12px; line-height: 24px; padding: 0 16px; display: flex; justify-content: space-between; color: #111; }
+  .navbar { height: 44px; background: #f7f7f7; border-bottom: 1px solid #e5e5e5; line-height: 44px; text-align: center; font-size: 16px; color: #111; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; padding: 0 40px; }
+  .content { flex: 1; overflow-y: auto; padding: 20px 16px; font-size: 16px; line-height: 1.75; color: #333; word-wrap: break-word; }
+  .content img { max-width: 100%; height: auto; }
+  .error { background: #fdecea; color: #c0392b; padding: 8px 12px; font-size: 13px; border-bottom: 1px solid #f5c6cb; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<div class="toolbar">md2wx 实时预览 · 版本 {{.Version}} · 更新于 {{.UpdatedAt}}</div>
+<div class="phone">
+  <div class="statusbar"><span>9:41</span><span>●●● 100%</span></div>
+  <div class="navbar">{{.Title}}</div>
+  {{if .Error}}<div class="error">渲染失败：{{.Error}}</div>{{end}}
+  <div class="content" id="content">{{.Content}}</div>
+</div>
+<script>
+(function () {
+  var version = {{.Version}};
+  var scrollKey = "md2wx-preview-scroll";
+  var content = document.getElementById("content");
+  var saved = sessionStorage.getItem(scrollKey);
+  if (saved) { content.scrollTop = parseInt(saved, 10); }
+  var source = new EventSource("/events");
+  source.addEventListener("reload", function (e) {
+    if (parseInt(e.data, 10) !== version) {
+      sessionStorage.setItem(scrollKey, content.scrollTop);
+      location.reload();
+    }
+  });
+})();
+</script>
+</body>
+</html>
+`))
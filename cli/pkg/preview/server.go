@@ -0,0 +1,207 @@
+// Package preview 提供本地预览服务器。
+//
+// 服务器在模拟微信手机端的页面框架中展示渲染后的文章 HTML，并通过
+// Server-Sent Events 通知浏览器在内容更新后自动刷新。
+//
+// 路由：
+//   - /        预览页面（手机框架 + 文章内容）
+//   - /events  实时刷新事件流 (text/event-stream)
+//   - /html    渲染后的原始文章 HTML
+//   - /files/  文章引用的本地图片（只能访问通过 FileURL 登记的文件）
+//
+// 使用方法：
+//
+//	srv := preview.NewServer("article.md", renderFunc)
+//	srv.Refresh(ctx)
+//	http.ListenAndServe(addr, srv.Handler())
+package preview
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RenderFunc 渲染文章，返回文章 HTML
+type RenderFunc func(ctx context.Context) (string, error)
+
+// Server 预览服务器
+type Server struct {
+	title  string
+	render RenderFunc
+
+	mu          sync.RWMutex
+	html        string
+	renderErr   error
+	version     int
+	updatedAt   time.Time
+	subscribers map[chan int]struct{}
+	// files 本地文件的访问地址 → 文件路径
+	files map[string]string
+}
+
+// NewServer 创建预览服务器，title 显示在页面标题栏
+func NewServer(title string, render RenderFunc) *Server {
+	return &Server{
+		title:       title,
+		render:      render,
+		subscribers: make(map[chan int]struct{}),
+		files:       make(map[string]string),
+	}
+}
+
+// FileURL 登记本地文件 path，返回浏览器中访问它的地址
+//
+// 文章中的本地图片替换为该地址后才能在预览页面中显示。同一文件的地址不变，
+// 未登记的文件不能访问。
+func (s *Server) FileURL(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	sum := sha256.Sum256([]byte(path))
+	u := "/files/" + hex.EncodeToString(sum[:8]) + filepath.Ext(path)
+
+	s.mu.Lock()
+	s.files[u] = path
+	s.mu.Unlock()
+	return u
+}
+
+// Refresh 重新渲染文章并通知已连接的浏览器刷新
+//
+// 渲染失败时保留上一次成功的内容，并在页面上显示错误信息。
+func (s *Server) Refresh(ctx context.Context) error {
+	html, err := s.render(ctx)
+
+	s.mu.Lock()
+	if err == nil {
+		s.html = html
+	}
+	s.renderErr = err
+	s.version++
+	s.updatedAt = time.Now()
+	version := s.version
+	subscribers := make([]chan int, 0, len(s.subscribers))
+	for ch := range s.subscribers {
+		subscribers = append(subscribers, ch)
+	}
+	s.mu.Unlock()
+
+	for _, ch := range subscribers {
+		// 订阅者只关心最新版本，通道已满时丢弃旧通知
+		select {
+		case ch <- version:
+		default:
+		}
+	}
+	return err
+}
+
+// Version 返回当前内容版本号，每次 Refresh 递增
+func (s *Server) Version() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// Handler 返回预览服务器的 HTTP 处理器
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handlePage)
+	mux.HandleFunc("/html", s.handleHTML)
+	mux.HandleFunc("/events", s.handleEvents)
+	mux.HandleFunc("/files/", s.handleFile)
+	return mux
+}
+
+// handlePage 输出预览页面
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.RLock()
+	data := pageData{
+		Title:     s.title,
+		Content:   template.HTML(s.html),
+		Version:   s.version,
+		UpdatedAt: s.updatedAt.Format("15:04:05"),
+	}
+	if s.renderErr != nil {
+		data.Error = s.renderErr.Error()
+	}
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := pageTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleHTML 输出原始文章 HTML
+func (s *Server) handleHTML(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	html := s.html
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	fmt.Fprint(w, html)
+}
+
+// handleFile 输出登记过的本地文件
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	path, ok := s.files[r.URL.Path]
+	s.mu.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	// 图片可能随时修改，不缓存
+	w.Header().Set("Cache-Control", "no-store")
+	http.ServeFile(w, r, path)
+}
+
+// handleEvents 推送内容更新事件
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan int, 1)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	fmt.Fprintf(w, "retry: 1000\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case version := <-ch:
+			fmt.Fprintf(w, "event: reload\ndata: %d\n\n", version)
+			flusher.Flush()
+		}
+	}
+}
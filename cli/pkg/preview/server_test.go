@@ -0,0 +1,172 @@
+package preview
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRefresh_RendersContent(t *testing.T) {
+	srv := NewServer("article.md", func(ctx context.Context) (string, error) {
+		return "<h1>Hello</h1>", nil
+	})
+
+	if err := srv.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() failed: %v", err)
+	}
+	if srv.Version() != 1 {
+		t.Errorf("Version() = %d, want 1", srv.Version())
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "<h1>Hello</h1>") {
+		t.Error("page should contain rendered html")
+	}
+	if !strings.Contains(body, "article.md") {
+		t.Error("page should contain title")
+	}
+	if !strings.Contains(body, `new EventSource("/events")`) {
+		t.Error("page should subscribe to live reload events")
+	}
+}
+
+func TestRefresh_KeepsLastContentOnError(t *testing.T) {
+	fail := false
+	srv := NewServer("article.md", func(ctx context.Context) (string, error) {
+		if fail {
+			return "", errors.New("invalid theme")
+		}
+		return "<p>ok</p>", nil
+	})
+
+	srv.Refresh(context.Background())
+	fail = true
+	if err := srv.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() should return render error")
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "<p>ok</p>") {
+		t.Error("page should keep last successful content")
+	}
+	if !strings.Contains(body, "invalid theme") {
+		t.Error("page should show render error")
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/html", nil))
+	if rec.Body.String() != "<p>ok</p>" {
+		t.Errorf("/html = %q, want <p>ok</p>", rec.Body.String())
+	}
+}
+
+func TestHandler_NotFound(t *testing.T) {
+	srv := NewServer("article.md", func(ctx context.Context) (string, error) {
+		return "", nil
+	})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestHandler_Files(t *testing.T) {
+	srv := NewServer("article.md", func(ctx context.Context) (string, error) {
+		return "", nil
+	})
+	path := filepath.Join(t.TempDir(), "a.png")
+	if err := os.WriteFile(path, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u := srv.FileURL(path)
+	if u != srv.FileURL(path) || !strings.HasSuffix(u, ".png") {
+		t.Errorf("FileURL() = %q, want a stable .png url", u)
+	}
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", u, nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "png" {
+		t.Errorf("GET %s = %d %q", u, rec.Code, rec.Body.String())
+	}
+
+	// 未登记的文件不能访问
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/files/a.png", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unregistered file status = %d, want 404", rec.Code)
+	}
+}
+
+func TestEvents_NotifiesOnRefresh(t *testing.T) {
+	srv := NewServer("article.md", func(ctx context.Context) (string, error) {
+		return "<p>v</p>", nil
+	})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatalf("GET /events failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %s, want text/event-stream", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	// 跳过初始的 retry 指令
+	if _, err := reader.ReadString('\n'); err != nil {
+		t.Fatalf("read retry line: %v", err)
+	}
+
+	// 等待订阅注册完成后触发刷新
+	deadline := time.Now().Add(time.Second)
+	for {
+		srv.mu.RLock()
+		n := len(srv.subscribers)
+		srv.mu.RUnlock()
+		if n > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	srv.Refresh(context.Background())
+
+	lines := make(chan string, 1)
+	go func() {
+		var sb strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			sb.WriteString(line)
+			if err != nil || strings.HasPrefix(line, "data:") {
+				lines <- sb.String()
+				return
+			}
+		}
+	}()
+
+	select {
+	case got := <-lines:
+		if !strings.Contains(got, "event: reload") || !strings.Contains(got, "data: 1") {
+			t.Errorf("event = %q, want reload with data 1", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("reload event not received")
+	}
+}
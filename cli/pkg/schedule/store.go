@@ -0,0 +1,360 @@
+// Package schedule 提供定时发布任务的本地持久化队列。
+//
+// 任务保存在 <dir>/jobs.json，每个任务的执行记录追加写入
+// <dir>/logs/<id>.log（每行一个 JSON）。多个进程（CLI 与调度器）
+// 通过 jobs.lock 文件互斥访问队列，写入采用临时文件 + 重命名，
+// 进程中途退出不会损坏队列。同一队列只允许一个调度器运行，
+// 调度器运行期间持有 scheduler.lock（见 LockScheduler）。
+package schedule
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/internal/fsutil"
+)
+
+// Status 任务状态
+type Status string
+
+const (
+	// StatusPending 等待执行
+	StatusPending Status = "pending"
+	// StatusRunning 执行中
+	StatusRunning Status = "running"
+	// StatusDone 已完成
+	StatusDone Status = "done"
+	// StatusFailed 重试耗尽后失败
+	StatusFailed Status = "failed"
+	// StatusCancelled 已取消
+	StatusCancelled Status = "cancelled"
+)
+
+// Finished 是否为结束状态
+func (s Status) Finished() bool {
+	return s == StatusDone || s == StatusFailed || s == StatusCancelled
+}
+
+// Job 定时发布任务
+type Job struct {
+	ID string `json:"id"`
+	// MediaID 要发布的草稿；从文件创建的任务在草稿创建成功后填入
+	MediaID string `json:"media_id,omitempty"`
+	// File 发布前先用该 Markdown 文件创建草稿（绝对路径）
+	File string `json:"file,omitempty"`
+	// Profile 添加任务时生效的配置档案，执行时使用该档案的账号
+	Profile string `json:"profile,omitempty"`
+	// At 计划执行时间；失败重试时推迟为下次重试时间
+	At          time.Time `json:"at"`
+	Status      Status    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"max_attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	PublishID   string    `json:"publish_id,omitempty"`
+	ArticleURLs []string  `json:"article_urls,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// LogEntry 任务执行记录
+type LogEntry struct {
+	Time    time.Time `json:"time"`
+	JobID   string    `json:"job_id"`
+	Attempt int       `json:"attempt"`
+	Event   string    `json:"event"`
+	Message string    `json:"message,omitempty"`
+}
+
+// ErrNotFound 任务不存在
+var ErrNotFound = errors.New("任务不存在")
+
+// lockTimeout 等待队列锁的最长时间，lockStale 之前未释放的锁视为进程异常退出遗留
+const (
+	lockTimeout = 5 * time.Second
+	lockStale   = 30 * time.Second
+)
+
+// Store 任务队列
+type Store struct {
+	dir string
+	now func() time.Time
+}
+
+// NewStore 创建以 dir 为存储目录的任务队列
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, now: time.Now}
+}
+
+// Dir 返回存储目录
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// LogPath 返回任务的执行记录文件路径
+func (s *Store) LogPath(id string) string {
+	return filepath.Join(s.dir, "logs", id+".log")
+}
+
+// Add 添加任务，自动生成 ID 并设置初始状态
+func (s *Store) Add(job Job) (Job, error) {
+	if job.MediaID == "" && job.File == "" {
+		return Job{}, fmt.Errorf("必须指定 media_id 或文件")
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = 3
+	}
+	now := s.now()
+	job.ID = newID()
+	job.Status = StatusPending
+	job.Attempts = 0
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	err := s.update(func(jobs []Job) ([]Job, error) {
+		return append(jobs, job), nil
+	})
+	if err != nil {
+		return Job{}, err
+	}
+	s.AppendLog(LogEntry{JobID: job.ID, Event: "added", Message: fmt.Sprintf("计划时间 %s", job.At.Format(time.RFC3339))})
+	return job, nil
+}
+
+// List 返回所有任务，按计划时间排序
+func (s *Store) List() ([]Job, error) {
+	jobs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].At.Before(jobs[j].At) })
+	return jobs, nil
+}
+
+// Get 返回指定任务
+func (s *Store) Get(id string) (Job, error) {
+	jobs, err := s.load()
+	if err != nil {
+		return Job{}, err
+	}
+	for _, j := range jobs {
+		if j.ID == id {
+			return j, nil
+		}
+	}
+	return Job{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Cancel 取消未结束的任务
+func (s *Store) Cancel(id string) (Job, error) {
+	var cancelled Job
+	err := s.modify(id, func(j *Job) error {
+		if j.Status.Finished() {
+			return fmt.Errorf("任务 %s 已%s，无法取消", id, j.Status)
+		}
+		if j.Status == StatusRunning {
+			return fmt.Errorf("任务 %s 正在执行，无法取消", id)
+		}
+		j.Status = StatusCancelled
+		cancelled = *j
+		return nil
+	})
+	if err != nil {
+		return Job{}, err
+	}
+	s.AppendLog(LogEntry{JobID: id, Event: "cancelled"})
+	return cancelled, nil
+}
+
+// ClaimDue 将到期的等待任务标记为执行中并返回，保证同一任务只被领取一次
+func (s *Store) ClaimDue(now time.Time) ([]Job, error) {
+	var claimed []Job
+	err := s.update(func(jobs []Job) ([]Job, error) {
+		for i := range jobs {
+			if jobs[i].Status == StatusPending && !jobs[i].At.After(now) {
+				jobs[i].Status = StatusRunning
+				jobs[i].Attempts++
+				jobs[i].UpdatedAt = s.now()
+				claimed = append(claimed, jobs[i])
+			}
+		}
+		return jobs, nil
+	})
+	return claimed, err
+}
+
+// ErrSchedulerRunning 已有调度器在使用同一队列
+var ErrSchedulerRunning = errors.New("已有调度器在运行")
+
+// LockScheduler 获取调度器锁，返回释放锁的函数
+//
+// 锁在持有期间定期刷新，调度器异常退出后超过 lockStale 即可重新获取。
+// 已有调度器运行时立即返回包装 ErrSchedulerRunning 的错误。
+func (s *Store) LockScheduler() (func(), error) {
+	path := filepath.Join(s.dir, "scheduler.lock")
+	unlock, err := fsutil.Lock(path, 0, lockStale)
+	if errors.Is(err, fsutil.ErrBusy) {
+		return nil, fmt.Errorf("%w（锁文件 %s）", ErrSchedulerRunning, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("锁定调度器失败: %w", err)
+	}
+	return unlock, nil
+}
+
+// Recover 将执行中的任务恢复为等待状态，用于调度器异常退出后重启
+//
+// 调用方须持有 LockScheduler 的锁，否则会把其他调度器正在执行的任务重新排队。
+func (s *Store) Recover() ([]Job, error) {
+	var recovered []Job
+	err := s.update(func(jobs []Job) ([]Job, error) {
+		for i := range jobs {
+			if jobs[i].Status == StatusRunning {
+				jobs[i].Status = StatusPending
+				jobs[i].UpdatedAt = s.now()
+				recovered = append(recovered, jobs[i])
+			}
+		}
+		return jobs, nil
+	})
+	for _, j := range recovered {
+		s.AppendLog(LogEntry{JobID: j.ID, Attempt: j.Attempts, Event: "recovered", Message: "调度器重启，任务重新排队"})
+	}
+	return recovered, err
+}
+
+// Save 保存任务的最新状态
+func (s *Store) Save(job Job) error {
+	return s.modify(job.ID, func(j *Job) error {
+		*j = job
+		return nil
+	})
+}
+
+// AppendLog 追加任务执行记录，写入失败不影响任务执行
+func (s *Store) AppendLog(entry LogEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = s.now()
+	}
+	path := s.LogPath(entry.JobID)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(entry)
+}
+
+// ReadLog 读取任务执行记录
+func (s *Store) ReadLog(id string) ([]LogEntry, error) {
+	data, err := os.ReadFile(s.LogPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []LogEntry
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var e LogEntry
+		if err := dec.Decode(&e); err != nil {
+			return entries, fmt.Errorf("解析执行记录失败: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// modify 在锁内修改单个任务
+func (s *Store) modify(id string, fn func(j *Job) error) error {
+	return s.update(func(jobs []Job) ([]Job, error) {
+		for i := range jobs {
+			if jobs[i].ID == id {
+				if err := fn(&jobs[i]); err != nil {
+					return nil, err
+				}
+				jobs[i].UpdatedAt = s.now()
+				return jobs, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	})
+}
+
+// update 加锁读取、修改并写回队列
+func (s *Store) update(fn func([]Job) ([]Job, error)) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	jobs, err := s.load()
+	if err != nil {
+		return err
+	}
+	jobs, err = fn(jobs)
+	if err != nil {
+		return err
+	}
+	return s.write(jobs)
+}
+
+func (s *Store) jobsPath() string {
+	return filepath.Join(s.dir, "jobs.json")
+}
+
+// load 读取队列，文件不存在时返回空队列
+func (s *Store) load() ([]Job, error) {
+	data, err := os.ReadFile(s.jobsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取任务队列失败: %w", err)
+	}
+	var jobs []Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("解析任务队列失败: %w", err)
+	}
+	return jobs, nil
+}
+
+// write 原子写入队列
+func (s *Store) write(jobs []Job) error {
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFile(s.jobsPath(), data, 0600); err != nil {
+		return fmt.Errorf("写入任务队列失败: %w", err)
+	}
+	return nil
+}
+
+// lock 锁定队列，其他进程持有锁时等待
+func (s *Store) lock() (func(), error) {
+	unlock, err := fsutil.Lock(filepath.Join(s.dir, "jobs.lock"), lockTimeout, lockStale)
+	if err != nil {
+		return nil, fmt.Errorf("锁定任务队列失败: %w", err)
+	}
+	return unlock, nil
+}
+
+// newID 生成 8 位十六进制任务 ID
+func newID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,154 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T, now time.Time) *Store {
+	t.Helper()
+	s := NewStore(t.TempDir())
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestStore_AddListGet(t *testing.T) {
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	s := newTestStore(t, now)
+
+	later, err := s.Add(Job{MediaID: "m2", At: now.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	sooner, err := s.Add(Job{File: "/tmp/a.md", At: now.Add(time.Hour), MaxAttempts: 5})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if len(later.ID) != 8 || later.Status != StatusPending || later.MaxAttempts != 3 {
+		t.Errorf("Add() = %+v", later)
+	}
+
+	jobs, err := s.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != sooner.ID || jobs[1].ID != later.ID {
+		t.Errorf("List() should be sorted by At, got %+v", jobs)
+	}
+
+	got, err := s.Get(sooner.ID)
+	if err != nil || got.MaxAttempts != 5 || got.File != "/tmp/a.md" {
+		t.Errorf("Get() = %+v, %v", got, err)
+	}
+	if _, err := s.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Add(Job{At: now}); err == nil {
+		t.Error("Add() without media_id or file should fail")
+	}
+}
+
+func TestStore_Cancel(t *testing.T) {
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	s := newTestStore(t, now)
+
+	job, _ := s.Add(Job{MediaID: "m1", At: now.Add(time.Hour)})
+	cancelled, err := s.Cancel(job.ID)
+	if err != nil || cancelled.Status != StatusCancelled {
+		t.Fatalf("Cancel() = %+v, %v", cancelled, err)
+	}
+	if _, err := s.Cancel(job.ID); err == nil {
+		t.Error("Cancel() on cancelled job should fail")
+	}
+	if _, err := s.Cancel("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Cancel(missing) error = %v, want ErrNotFound", err)
+	}
+
+	due, _ := s.ClaimDue(now.Add(2 * time.Hour))
+	if len(due) != 0 {
+		t.Errorf("cancelled job should not be claimed, got %+v", due)
+	}
+}
+
+func TestStore_ClaimDueAndRecover(t *testing.T) {
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	s := newTestStore(t, now)
+
+	due, _ := s.Add(Job{MediaID: "m1", At: now.Add(-time.Minute)})
+	s.Add(Job{MediaID: "m2", At: now.Add(time.Hour)})
+
+	claimed, err := s.ClaimDue(now)
+	if err != nil {
+		t.Fatalf("ClaimDue() error = %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != due.ID || claimed[0].Status != StatusRunning || claimed[0].Attempts != 1 {
+		t.Fatalf("ClaimDue() = %+v", claimed)
+	}
+	if again, _ := s.ClaimDue(now); len(again) != 0 {
+		t.Errorf("running job should not be claimed twice, got %+v", again)
+	}
+	if _, err := s.Cancel(due.ID); err == nil {
+		t.Error("Cancel() on running job should fail")
+	}
+
+	// 模拟调度器异常退出后重启
+	recovered, err := s.Recover()
+	if err != nil || len(recovered) != 1 || recovered[0].Status != StatusPending {
+		t.Fatalf("Recover() = %+v, %v", recovered, err)
+	}
+	claimed, _ = s.ClaimDue(now)
+	if len(claimed) != 1 || claimed[0].Attempts != 2 {
+		t.Errorf("ClaimDue() after Recover = %+v", claimed)
+	}
+
+	job := claimed[0]
+	job.Status = StatusDone
+	job.PublishID = "p1"
+	if err := s.Save(job); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, _ := s.Get(job.ID)
+	if got.Status != StatusDone || got.PublishID != "p1" {
+		t.Errorf("Get() after Save = %+v", got)
+	}
+}
+
+func TestStore_Log(t *testing.T) {
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	s := newTestStore(t, now)
+
+	job, _ := s.Add(Job{MediaID: "m1", At: now})
+	s.AppendLog(LogEntry{JobID: job.ID, Attempt: 1, Event: "failed", Message: "timeout"})
+
+	entries, err := s.ReadLog(job.ID)
+	if err != nil {
+		t.Fatalf("ReadLog() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Event != "added" || entries[1].Message != "timeout" || !entries[1].Time.Equal(now) {
+		t.Errorf("ReadLog() = %+v", entries)
+	}
+	if entries, err := s.ReadLog("missing"); err != nil || entries != nil {
+		t.Errorf("ReadLog(missing) = %+v, %v", entries, err)
+	}
+}
+
+func TestStore_LockScheduler(t *testing.T) {
+	s := newTestStore(t, time.Now())
+
+	unlock, err := s.LockScheduler()
+	if err != nil {
+		t.Fatalf("LockScheduler() error = %v", err)
+	}
+	// 同一队列不能同时运行两个调度器
+	if _, err := s.LockScheduler(); !errors.Is(err, ErrSchedulerRunning) {
+		t.Errorf("second LockScheduler() error = %v, want ErrSchedulerRunning", err)
+	}
+	unlock()
+
+	unlock, err = s.LockScheduler()
+	if err != nil {
+		t.Fatalf("LockScheduler() after unlock error = %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,96 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// weekdays 星期名称（英文全称、缩写与中文）
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday, "周日": time.Sunday, "周天": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday, "周一": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday, "周二": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday, "周三": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday, "周四": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday, "周五": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday, "周六": time.Saturday,
+}
+
+// absoluteLayouts 支持的绝对时间格式（按本地时区解析）
+var absoluteLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006/01/02 15:04",
+}
+
+// ParseTime 解析计划时间，相对时间以 now 为基准
+//
+// 支持的格式：
+//   - RFC3339，如 2024-06-01T08:00:00+08:00
+//   - 本地时间，如 "2024-06-01 08:00"、2024-06-01T08:00
+//   - 当天时刻，如 08:00（已过则为次日）
+//   - 星期 + 时刻，如 "fri 08:00"、"周五 08:00"（下一个该星期几）
+//   - 相对时间，如 +2h、"in 30m"
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("时间不能为空")
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	lower := strings.ToLower(s)
+	if rel, ok := strings.CutPrefix(lower, "+"); ok {
+		return parseRelative(s, rel, now)
+	}
+	if rel, ok := strings.CutPrefix(lower, "in "); ok {
+		return parseRelative(s, strings.TrimSpace(rel), now)
+	}
+
+	if clock, err := time.Parse("15:04", lower); err == nil {
+		t := atClock(now, clock)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	if fields := strings.Fields(lower); len(fields) == 2 {
+		day, ok := weekdays[fields[0]]
+		clock, err := time.Parse("15:04", fields[1])
+		if ok && err == nil {
+			t := atClock(now, clock)
+			days := (int(day) - int(now.Weekday()) + 7) % 7
+			t = t.AddDate(0, 0, days)
+			if !t.After(now) {
+				t = t.AddDate(0, 0, 7)
+			}
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("无法解析时间 %q（支持 RFC3339、\"2006-01-02 15:04\"、15:04、\"fri 08:00\"、+2h）", s)
+}
+
+func parseRelative(orig, rel string, now time.Time) (time.Time, error) {
+	d, err := time.ParseDuration(rel)
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("无法解析相对时间 %q", orig)
+	}
+	return now.Add(d), nil
+}
+
+// atClock 返回 now 当天的指定时刻
+func atClock(now, clock time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+}
@@ -0,0 +1,49 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-06-05 是星期三
+	now := time.Date(2024, 6, 5, 10, 30, 0, 0, loc)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2024-06-07T08:00:00Z", time.Date(2024, 6, 7, 8, 0, 0, 0, time.UTC)},
+		{"2024-06-07 08:00", time.Date(2024, 6, 7, 8, 0, 0, 0, loc)},
+		{"2024-06-07T08:00", time.Date(2024, 6, 7, 8, 0, 0, 0, loc)},
+		{"12:00", time.Date(2024, 6, 5, 12, 0, 0, 0, loc)},
+		{"09:00", time.Date(2024, 6, 6, 9, 0, 0, 0, loc)},
+		{"fri 08:00", time.Date(2024, 6, 7, 8, 0, 0, 0, loc)},
+		{"Friday 08:00", time.Date(2024, 6, 7, 8, 0, 0, 0, loc)},
+		{"周五 08:00", time.Date(2024, 6, 7, 8, 0, 0, 0, loc)},
+		{"wed 09:00", time.Date(2024, 6, 12, 9, 0, 0, 0, loc)},
+		{"wed 11:00", time.Date(2024, 6, 5, 11, 0, 0, 0, loc)},
+		{"+2h", now.Add(2 * time.Hour)},
+		{"in 30m", now.Add(30 * time.Minute)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseTime(%q) error = %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseTime_Invalid(t *testing.T) {
+	now := time.Now()
+	for _, in := range []string{"", "tomorrow", "+abc", "+-1h", "xyz 08:00", "25:00"} {
+		if _, err := ParseTime(in, now); err == nil {
+			t.Errorf("ParseTime(%q) should fail", in)
+		}
+	}
+}
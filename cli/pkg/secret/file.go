@@ -0,0 +1,286 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/internal/fsutil"
+)
+
+const (
+	kdfPBKDF2  = "pbkdf2-sha256"
+	kdfKeyFile = "keyfile"
+
+	// defaultIterations PBKDF2 迭代次数
+	defaultIterations = 600000
+)
+
+// aad 绑定到密文的附加数据，防止与其他格式的文件混用
+var aad = []byte("md2wx-secrets-v1")
+
+// FileStore 本地加密文件存储
+//
+// 所有密钥作为一个 JSON 对象整体加密，每次写入都重新生成盐和随机数。
+// 文件在首次访问时解密一次，之后的读写使用内存中的副本；Batch 中的
+// 多次修改只加密写入一次。
+type FileStore struct {
+	path       string
+	passphrase string
+	keyFile    string
+	iterations int
+
+	// secrets 解密后的全部密钥，首次访问时读取
+	secrets map[string]string
+	// batching Batch 执行中，修改推迟到结束时写入；dirty 表示有未写入的修改
+	batching bool
+	dirty    bool
+}
+
+// encryptedFile 加密文件格式
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations,omitempty"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// NewFileStore 创建加密文件存储，passphrase 非空时使用口令，否则使用密钥文件
+func NewFileStore(path, passphrase, keyFile string) *FileStore {
+	return &FileStore{path: path, passphrase: passphrase, keyFile: keyFile, iterations: defaultIterations}
+}
+
+// Name 返回后端名称
+func (s *FileStore) Name() string {
+	return BackendFile
+}
+
+// Get 读取密钥
+func (s *FileStore) Get(key string) (string, error) {
+	secrets, err := s.loaded()
+	if err != nil {
+		return "", err
+	}
+	value, ok := secrets[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return value, nil
+}
+
+// Set 保存密钥
+func (s *FileStore) Set(key, value string) error {
+	secrets, err := s.loaded()
+	if err != nil {
+		return err
+	}
+	secrets[key] = value
+	return s.changed()
+}
+
+// Delete 删除密钥
+func (s *FileStore) Delete(key string) error {
+	secrets, err := s.loaded()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[key]; !ok {
+		return nil
+	}
+	delete(secrets, key)
+	return s.changed()
+}
+
+// Batch 执行 fn，其中的 Set 和 Delete 在 fn 成功返回后一次写入
+//
+// fn 返回错误时丢弃未写入的修改，下次访问重新读取文件。嵌套调用时由最外层写入。
+func (s *FileStore) Batch(fn func() error) error {
+	if s.batching {
+		return fn()
+	}
+	s.batching = true
+	err := fn()
+	s.batching = false
+	dirty := s.dirty
+	s.dirty = false
+	if err != nil {
+		if dirty {
+			s.secrets = nil
+		}
+		return err
+	}
+	if dirty {
+		return s.flush()
+	}
+	return nil
+}
+
+// loaded 返回内存中的密钥，首次访问时读取并解密文件
+func (s *FileStore) loaded() (map[string]string, error) {
+	if s.secrets == nil {
+		secrets, err := s.load()
+		if err != nil {
+			return nil, err
+		}
+		s.secrets = secrets
+	}
+	return s.secrets, nil
+}
+
+// changed 写入修改，Batch 中推迟到 Batch 结束
+func (s *FileStore) changed() error {
+	if s.batching {
+		s.dirty = true
+		return nil
+	}
+	return s.flush()
+}
+
+// flush 写入内存中的密钥，失败时丢弃内存副本
+func (s *FileStore) flush() error {
+	if err := s.save(s.secrets); err != nil {
+		s.secrets = nil
+		return err
+	}
+	return nil
+}
+
+// load 读取并解密全部密钥，文件不存在时返回空集合
+func (s *FileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("读取密钥文件失败: %w", err)
+	}
+
+	var f encryptedFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("解析密钥文件失败: %w", err)
+	}
+	if f.Version != 1 {
+		return nil, fmt.Errorf("不支持的密钥文件版本: %d", f.Version)
+	}
+
+	var key []byte
+	switch f.KDF {
+	case kdfPBKDF2:
+		if s.passphrase == "" {
+			return nil, fmt.Errorf("%s 使用口令加密，请设置环境变量 MD2WX_SECRET_PASSPHRASE", s.path)
+		}
+		key, err = pbkdf2.Key(sha256.New, s.passphrase, f.Salt, f.Iterations, 32)
+	case kdfKeyFile:
+		key, err = s.readKeyFile(false)
+	default:
+		err = fmt.Errorf("不支持的密钥派生方式: %s", f.KDF)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, f.Nonce, f.Ciphertext, aad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("解析密钥内容失败: %w", err)
+	}
+	return secrets, nil
+}
+
+// save 加密并原子写入全部密钥
+func (s *FileStore) save(secrets map[string]string) error {
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	f := encryptedFile{Version: 1}
+	var key []byte
+	if s.passphrase != "" {
+		f.KDF = kdfPBKDF2
+		f.Iterations = s.iterations
+		f.Salt = make([]byte, 16)
+		if _, err := rand.Read(f.Salt); err != nil {
+			return err
+		}
+		key, err = pbkdf2.Key(sha256.New, s.passphrase, f.Salt, f.Iterations, 32)
+	} else {
+		f.KDF = kdfKeyFile
+		key, err = s.readKeyFile(true)
+	}
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	f.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return err
+	}
+	f.Ciphertext = gcm.Seal(nil, f.Nonce, plain, aad)
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("写入密钥文件失败: %w", err)
+	}
+	return nil
+}
+
+// readKeyFile 读取 32 字节十六进制密钥，create 为 true 且文件不存在时生成
+func (s *FileStore) readKeyFile(create bool) ([]byte, error) {
+	data, err := os.ReadFile(s.keyFile)
+	if os.IsNotExist(err) && create {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(s.keyFile), 0700); err != nil {
+			return nil, fmt.Errorf("创建密钥目录失败: %w", err)
+		}
+		if err := os.WriteFile(s.keyFile, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+			return nil, fmt.Errorf("写入密钥文件失败: %w", err)
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥文件失败: %w", err)
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("密钥文件格式错误：应为 64 位十六进制字符")
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,129 @@
+package secret
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestFileStore(dir, passphrase string) *FileStore {
+	s := NewFileStore(filepath.Join(dir, StoreFile), passphrase, filepath.Join(dir, KeyFile))
+	s.iterations = 1000
+	return s
+}
+
+func TestFileStore_KeyFile(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestFileStore(dir, "")
+
+	if _, err := s.Get("api_key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() on empty store error = %v, want ErrNotFound", err)
+	}
+	if err := s.Set("api_key", "wme_plain_value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := s.Set("profile.b.api_key", "other"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// 密文中不应出现明文
+	data, _ := os.ReadFile(filepath.Join(dir, StoreFile))
+	if strings.Contains(string(data), "wme_plain_value") {
+		t.Error("store file contains plaintext")
+	}
+	info, err := os.Stat(filepath.Join(dir, KeyFile))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("key file = %v, %v", info, err)
+	}
+
+	// 重新打开后可读取
+	s = newTestFileStore(dir, "")
+	if v, err := s.Get("api_key"); err != nil || v != "wme_plain_value" {
+		t.Errorf("Get() = %q, %v", v, err)
+	}
+	if err := s.Delete("api_key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get("api_key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete error = %v", err)
+	}
+	if v, _ := s.Get("profile.b.api_key"); v != "other" {
+		t.Errorf("other key = %q", v)
+	}
+
+	// 更换密钥文件后无法解密（已打开的存储使用解密后的副本）
+	os.WriteFile(filepath.Join(dir, KeyFile), []byte(strings.Repeat("ab", 32)), 0600)
+	if _, err := newTestFileStore(dir, "").Get("profile.b.api_key"); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Get() with wrong key error = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestFileStore_Passphrase(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestFileStore(dir, "correct horse")
+	if err := s.Set("wechat_appsecret", "s3cret"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, KeyFile)); !os.IsNotExist(err) {
+		t.Error("passphrase mode should not create a key file")
+	}
+
+	if v, err := newTestFileStore(dir, "correct horse").Get("wechat_appsecret"); err != nil || v != "s3cret" {
+		t.Errorf("Get() = %q, %v", v, err)
+	}
+	if _, err := newTestFileStore(dir, "wrong").Get("wechat_appsecret"); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Get() with wrong passphrase error = %v, want ErrDecrypt", err)
+	}
+	if _, err := newTestFileStore(dir, "").Get("wechat_appsecret"); err == nil || !strings.Contains(err.Error(), "MD2WX_SECRET_PASSPHRASE") {
+		t.Errorf("Get() without passphrase error = %v", err)
+	}
+}
+
+func TestFileStore_Batch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, StoreFile)
+	s := newTestFileStore(dir, "pass")
+
+	err := Batch(s, func() error {
+		s.Set("api_key", "a")
+		s.Set("wechat_appsecret", "b")
+		// 结束前不写入文件
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Error("store file written before Batch returned")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+	if v, err := newTestFileStore(dir, "pass").Get("wechat_appsecret"); err != nil || v != "b" {
+		t.Errorf("Get() after Batch = %q, %v", v, err)
+	}
+
+	// 失败时丢弃修改
+	err = Batch(s, func() error {
+		s.Set("api_key", "changed")
+		return errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("Batch() should return fn error")
+	}
+	if v, _ := s.Get("api_key"); v != "a" {
+		t.Errorf("Get() after failed Batch = %q, want a", v)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if b, err := Open(BackendPlain, t.TempDir()); b != nil || err != nil {
+		t.Errorf("Open(plain) = %v, %v", b, err)
+	}
+	b, err := Open(BackendFile, t.TempDir())
+	if err != nil || b.Name() != BackendFile {
+		t.Errorf("Open(file) = %v, %v", b, err)
+	}
+	if _, err := Open("vault", t.TempDir()); err == nil {
+		t.Error("Open(unknown) should fail")
+	}
+}
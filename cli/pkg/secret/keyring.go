@@ -0,0 +1,158 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// runFunc 执行外部命令，stdin 非空时写入标准输入，返回标准输出
+type runFunc func(stdin, name string, args ...string) (string, error)
+
+// Keyring 系统密钥环存储
+//
+// Linux 通过 secret-tool（libsecret）访问 Secret Service，macOS 通过 security 访问钥匙串。
+type Keyring struct {
+	service string
+	goos    string
+	run     runFunc
+}
+
+// NewKeyring 创建系统密钥环存储，当前系统不支持或缺少命令行工具时返回错误
+func NewKeyring(service string) (*Keyring, error) {
+	k := &Keyring{service: service, goos: runtime.GOOS, run: runCommand}
+	tool := k.tool()
+	if tool == "" {
+		return nil, fmt.Errorf("当前系统 (%s) 不支持 keyring 后端，请使用 file 后端", k.goos)
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return nil, fmt.Errorf("keyring 后端需要 %s 命令: %w", tool, err)
+	}
+	return k, nil
+}
+
+// Name 返回后端名称
+func (k *Keyring) Name() string {
+	return BackendKeyring
+}
+
+// Get 读取密钥
+func (k *Keyring) Get(key string) (string, error) {
+	var out string
+	var err error
+	if k.goos == "darwin" {
+		out, err = k.run("", "security", "find-generic-password", "-s", k.service, "-a", key, "-w")
+	} else {
+		out, err = k.run("", "secret-tool", "lookup", "service", k.service, "key", key)
+	}
+	if err != nil {
+		if k.notFound(err) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return "", fmt.Errorf("读取系统密钥环失败: %w", err)
+	}
+	value := strings.TrimSuffix(out, "\n")
+	if value == "" {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return value, nil
+}
+
+// Set 保存密钥
+//
+// 密码通过标准输入传递，不出现在命令参数中（参数对本机其他进程可见）。
+// macOS 上以 security -i 从标准输入读取命令，密码按十六进制（-X）传入。
+func (k *Keyring) Set(key, value string) error {
+	if k.goos == "darwin" {
+		return k.setDarwin(key, value)
+	}
+	_, err := k.run(value, "secret-tool", "store", "--label", k.service+" "+key, "service", k.service, "key", key)
+	if err != nil {
+		return fmt.Errorf("写入系统密钥环失败: %w", err)
+	}
+	return nil
+}
+
+// setDarwin 通过 security -i 写入钥匙串
+//
+// 交互模式下单条命令失败时 security 不一定以非零状态退出，写入后读回确认。
+func (k *Keyring) setDarwin(key, value string) error {
+	for _, s := range []string{k.service, key} {
+		if s == "" || strings.ContainsAny(s, " \t\n\"'\\") {
+			return fmt.Errorf("钥匙串条目名称 %q 不能包含空白或引号，请使用 file 后端", s)
+		}
+	}
+	line := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n", k.service, key, hex.EncodeToString([]byte(value)))
+	if _, err := k.run(line, "security", "-i"); err != nil {
+		return fmt.Errorf("写入系统密钥环失败: %w", err)
+	}
+	got, err := k.Get(key)
+	if err != nil {
+		return fmt.Errorf("写入系统密钥环失败: %w", err)
+	}
+	// 非文本密码由 security 以十六进制输出
+	if got != value && got != hex.EncodeToString([]byte(value)) {
+		return fmt.Errorf("写入系统密钥环失败: 读回的 %s 与写入的值不一致", key)
+	}
+	return nil
+}
+
+// Delete 删除密钥
+func (k *Keyring) Delete(key string) error {
+	var err error
+	if k.goos == "darwin" {
+		_, err = k.run("", "security", "delete-generic-password", "-s", k.service, "-a", key)
+	} else {
+		_, err = k.run("", "secret-tool", "clear", "service", k.service, "key", key)
+	}
+	if err != nil && !k.notFound(err) {
+		return fmt.Errorf("删除系统密钥环条目失败: %w", err)
+	}
+	return nil
+}
+
+// tool 返回当前系统使用的命令行工具
+func (k *Keyring) tool() string {
+	switch k.goos {
+	case "darwin":
+		return "security"
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "secret-tool"
+	}
+	return ""
+}
+
+// notFound 判断命令失败是否表示条目不存在
+//
+// secret-tool 找不到条目时退出码为 1，security 为 44。
+func (k *Keyring) notFound(err error) bool {
+	var exitErr interface{ ExitCode() int }
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	if k.goos == "darwin" {
+		return exitErr.ExitCode() == 44
+	}
+	return exitErr.ExitCode() == 1
+}
+
+func runCommand(stdin, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w", msg, err)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,109 @@
+package secret
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type exitError int
+
+func (e exitError) Error() string { return "exit status" }
+func (e exitError) ExitCode() int { return int(e) }
+
+// fakeSecretTool 模拟 secret-tool 的 store/lookup/clear
+func fakeSecretTool(store map[string]string, calls *[]string) runFunc {
+	return func(stdin, name string, args ...string) (string, error) {
+		*calls = append(*calls, name+" "+strings.Join(args, " "))
+		key := args[len(args)-1]
+		switch args[0] {
+		case "store":
+			store[key] = stdin
+		case "lookup":
+			v, ok := store[key]
+			if !ok {
+				return "", exitError(1)
+			}
+			return v, nil
+		case "clear":
+			delete(store, key)
+		}
+		return "", nil
+	}
+}
+
+func TestKeyring_Linux(t *testing.T) {
+	store := map[string]string{}
+	var calls []string
+	k := &Keyring{service: KeyringService, goos: "linux", run: fakeSecretTool(store, &calls)}
+
+	if err := k.Set("api_key", "wme_value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	// 密码通过标准输入传递，不出现在命令参数中
+	if strings.Contains(calls[0], "wme_value") {
+		t.Errorf("secret leaked into args: %s", calls[0])
+	}
+	if v, err := k.Get("api_key"); err != nil || v != "wme_value" {
+		t.Errorf("Get() = %q, %v", v, err)
+	}
+	if err := k.Delete("api_key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := k.Get("api_key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestKeyring_Darwin(t *testing.T) {
+	var got []string
+	var stored, stdinGot string
+	k := &Keyring{service: KeyringService, goos: "darwin", run: func(stdin, name string, args ...string) (string, error) {
+		got = append(got, name+" "+strings.Join(args, " "))
+		switch args[0] {
+		case "find-generic-password":
+			if stored == "" {
+				return "", exitError(44)
+			}
+			return stored + "\n", nil
+		case "-i":
+			stdinGot = stdin
+			stored = "wme_value"
+		}
+		return "", nil
+	}}
+
+	if _, err := k.Get("api_key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+	if err := k.Set("api_key", "wme_value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	// 密码通过标准输入传递，不出现在命令参数中
+	if got[1] != "security -i" {
+		t.Errorf("Set() ran %q, want security -i", got[1])
+	}
+	if want := "add-generic-password -U -s md2wx -a api_key -X 776d655f76616c7565\n"; stdinGot != want {
+		t.Errorf("Set() stdin = %q, want %q", stdinGot, want)
+	}
+	if err := k.Set("api key", "v"); err == nil {
+		t.Error("Set() with space in key should fail")
+	}
+
+	// 交互模式下命令失败但退出码为 0 时，读回不一致视为失败
+	stored = ""
+	k.run = func(stdin, name string, args ...string) (string, error) {
+		if args[0] == "find-generic-password" {
+			return "", exitError(44)
+		}
+		return "", nil
+	}
+	if err := k.Set("api_key", "v"); err == nil {
+		t.Error("Set() should fail when the value cannot be read back")
+	}
+
+	k.run = func(stdin, name string, args ...string) (string, error) { return "", exitError(51) }
+	if _, err := k.Get("api_key"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() with keychain error = %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+// Package secret 提供敏感配置（AppSecret、API Key）的存储后端。
+//
+// 支持的后端:
+//   - file: 本地加密文件（AES-256-GCM），密钥来自口令（PBKDF2）或密钥文件
+//   - keyring: 系统密钥环（Linux Secret Service 的 secret-tool，macOS 钥匙串的 security）
+//
+// 配置文件中只保存 secret://<key> 形式的引用，读取配置时由后端解密。
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Backend 密钥存储后端
+type Backend interface {
+	// Name 返回后端名称
+	Name() string
+	// Get 读取密钥，不存在时返回 ErrNotFound
+	Get(key string) (string, error)
+	// Set 保存密钥
+	Set(key, value string) error
+	// Delete 删除密钥，不存在时不报错
+	Delete(key string) error
+}
+
+// Batcher 可以将多次修改合并为一次写入的后端
+type Batcher interface {
+	// Batch 执行 fn，fn 中的 Set 和 Delete 在 fn 成功返回后一次写入，fn 失败时丢弃
+	Batch(fn func() error) error
+}
+
+// Batch 在 b 支持时合并 fn 中对 b 的修改，否则直接执行 fn
+func Batch(b Backend, fn func() error) error {
+	if batcher, ok := b.(Batcher); ok {
+		return batcher.Batch(fn)
+	}
+	return fn()
+}
+
+const (
+	// BackendPlain 明文保存在配置文件中（默认）
+	BackendPlain = "plain"
+	// BackendFile 本地加密文件
+	BackendFile = "file"
+	// BackendKeyring 系统密钥环
+	BackendKeyring = "keyring"
+
+	// StoreFile 加密文件名
+	StoreFile = "secrets.enc"
+	// KeyFile 默认密钥文件名
+	KeyFile = "secret.key"
+	// KeyringService 系统密钥环中的服务名
+	KeyringService = "md2wx"
+)
+
+// Backends 支持的后端名称
+var Backends = []string{BackendPlain, BackendFile, BackendKeyring}
+
+var (
+	// ErrNotFound 密钥不存在
+	ErrNotFound = errors.New("密钥不存在")
+	// ErrDecrypt 解密失败（口令或密钥文件不正确，或文件已损坏）
+	ErrDecrypt = errors.New("解密失败：口令或密钥文件不正确")
+)
+
+// Open 打开 dir 下的密钥后端，plain 返回 nil
+//
+// file 后端优先使用环境变量 MD2WX_SECRET_PASSPHRASE 中的口令，
+// 否则使用 MD2WX_SECRET_KEY_FILE 或 <dir>/secret.key 密钥文件（首次写入时自动生成）。
+func Open(name, dir string) (Backend, error) {
+	switch name {
+	case "", BackendPlain:
+		return nil, nil
+	case BackendFile:
+		keyFile := os.Getenv("MD2WX_SECRET_KEY_FILE")
+		if keyFile == "" {
+			keyFile = filepath.Join(dir, KeyFile)
+		}
+		return NewFileStore(filepath.Join(dir, StoreFile), os.Getenv("MD2WX_SECRET_PASSPHRASE"), keyFile), nil
+	case BackendKeyring:
+		return NewKeyring(KeyringService)
+	default:
+		return nil, fmt.Errorf("不支持的密钥后端: %s（可选: plain, file, keyring）", name)
+	}
+}
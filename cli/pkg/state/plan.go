@@ -0,0 +1,40 @@
+package state
+
+// Action 同步动作
+type Action string
+
+const (
+	// ActionCreate 没有记录，创建新草稿
+	ActionCreate Action = "create"
+	// ActionUpdate 内容变化，更新原草稿
+	ActionUpdate Action = "update"
+	// ActionSkip 内容未变化，跳过
+	ActionSkip Action = "skip"
+	// ActionDelete 源文件已删除，删除草稿
+	ActionDelete Action = "delete"
+)
+
+// Decide 根据已有记录和当前内容哈希决定文件的同步动作
+func Decide(entry Entry, found bool, hash string) Action {
+	switch {
+	case !found:
+		return ActionCreate
+	case entry.Hash == hash:
+		return ActionSkip
+	default:
+		return ActionUpdate
+	}
+}
+
+// Orphans 返回源文件已不存在的记录：不在 current 中且 inScope 返回 true
+//
+// inScope 限定本次同步覆盖的范围（如 --dir 目录和 --glob），范围外的记录保持不变。
+func Orphans(entries []Entry, current map[string]bool, inScope func(file string) bool) []Entry {
+	var orphans []Entry
+	for _, e := range entries {
+		if !current[e.File] && inScope(e.File) {
+			orphans = append(orphans, e)
+		}
+	}
+	return orphans
+}
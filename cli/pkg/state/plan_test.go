@@ -0,0 +1,34 @@
+package state
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecide(t *testing.T) {
+	e := Entry{Hash: "h1", MediaID: "m1"}
+	if a := Decide(Entry{}, false, "h1"); a != ActionCreate {
+		t.Errorf("Decide(not found) = %s", a)
+	}
+	if a := Decide(e, true, "h1"); a != ActionSkip {
+		t.Errorf("Decide(same hash) = %s", a)
+	}
+	if a := Decide(e, true, "h2"); a != ActionUpdate {
+		t.Errorf("Decide(changed) = %s", a)
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	entries := []Entry{
+		{File: "/posts/a.md"},
+		{File: "/posts/gone.md"},
+		{File: "/other/gone.md"},
+	}
+	current := map[string]bool{"/posts/a.md": true}
+	inPosts := func(file string) bool { return strings.HasPrefix(file, "/posts/") }
+
+	orphans := Orphans(entries, current, inPosts)
+	if len(orphans) != 1 || orphans[0].File != "/posts/gone.md" {
+		t.Errorf("Orphans() = %+v", orphans)
+	}
+}
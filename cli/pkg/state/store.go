@@ -0,0 +1,194 @@
+// Package state 记录 Markdown 文件与已创建草稿的对应关系，用于幂等同步。
+//
+// 每条记录以（配置档案, 文件绝对路径）为键，保存文章内容哈希和草稿 media_id，
+// 存储在 <dir>/drafts.json。重复同步时内容未变的文件跳过，内容变化的文件
+// 更新原草稿，源文件删除后可清理对应草稿（见 Decide 和 Orphans）。多个进程通过
+// drafts.lock 文件互斥访问，写入采用临时文件 + 重命名。
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/internal/fsutil"
+)
+
+// Entry 文件对应的草稿记录
+type Entry struct {
+	// Profile 创建草稿时使用的配置档案（不同公众号的草稿互不影响）
+	Profile string `json:"profile"`
+	// File Markdown 文件绝对路径
+	File string `json:"file"`
+	// Hash 文章内容哈希，见 Hash
+	Hash    string `json:"hash"`
+	MediaID string `json:"media_id"`
+	Title   string `json:"title,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// lockTimeout 等待锁的最长时间，lockStale 之前未释放的锁视为进程异常退出遗留
+const (
+	lockTimeout = 5 * time.Second
+	lockStale   = 30 * time.Second
+	// fileLockTimeout 等待其他进程完成同一文件同步的最长时间（含上传图片和创建草稿）
+	fileLockTimeout = 5 * time.Minute
+)
+
+// Store 草稿状态存储
+type Store struct {
+	dir string
+	now func() time.Time
+}
+
+// NewStore 创建以 dir 为存储目录的状态存储
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, now: time.Now}
+}
+
+// Path 返回状态文件路径
+func (s *Store) Path() string {
+	return filepath.Join(s.dir, "drafts.json")
+}
+
+// Hash 返回内容的 SHA-256 哈希（sha256:<hex>）
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// LockFile 锁定档案中单个文件的同步，返回释放锁的函数
+//
+// 调用方在读取记录、调用接口和写回记录期间持有该锁，避免两个进程同时
+// 同步同一文件时都创建草稿。其他进程持有锁时最多等待 5 分钟。
+func (s *Store) LockFile(profile, file string) (func(), error) {
+	sum := sha256.Sum256([]byte(profile + "\x00" + file))
+	path := filepath.Join(s.dir, "locks", hex.EncodeToString(sum[:8])+".lock")
+	unlock, err := fsutil.Lock(path, fileLockTimeout, lockStale)
+	if err != nil {
+		return nil, fmt.Errorf("锁定 %s 的同步失败: %w", file, err)
+	}
+	return unlock, nil
+}
+
+// Get 返回档案中文件的记录
+func (s *Store) Get(profile, file string) (Entry, bool, error) {
+	entries, err := s.load()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	for _, e := range entries {
+		if e.Profile == profile && e.File == file {
+			return e, true, nil
+		}
+	}
+	return Entry{}, false, nil
+}
+
+// List 返回档案的全部记录，按文件路径排序
+func (s *Store) List(profile string) ([]Entry, error) {
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var result []Entry
+	for _, e := range entries {
+		if e.Profile == profile {
+			result = append(result, e)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].File < result[j].File })
+	return result, nil
+}
+
+// Put 新增或替换记录，自动维护创建和更新时间
+func (s *Store) Put(entry Entry) error {
+	if entry.Profile == "" || entry.File == "" || entry.MediaID == "" {
+		return fmt.Errorf("记录缺少 profile、file 或 media_id")
+	}
+	return s.update(func(entries []Entry) []Entry {
+		now := s.now()
+		entry.UpdatedAt = now
+		for i, e := range entries {
+			if e.Profile == entry.Profile && e.File == entry.File {
+				entry.CreatedAt = e.CreatedAt
+				entries[i] = entry
+				return entries
+			}
+		}
+		entry.CreatedAt = now
+		return append(entries, entry)
+	})
+}
+
+// Delete 删除记录，记录不存在时不报错
+func (s *Store) Delete(profile, file string) error {
+	return s.update(func(entries []Entry) []Entry {
+		kept := entries[:0]
+		for _, e := range entries {
+			if e.Profile != profile || e.File != file {
+				kept = append(kept, e)
+			}
+		}
+		return kept
+	})
+}
+
+// update 加锁读取、修改并写回
+func (s *Store) update(fn func([]Entry) []Entry) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	return s.write(fn(entries))
+}
+
+// load 读取状态文件，文件不存在时返回空列表
+func (s *Store) load() ([]Entry, error) {
+	data, err := os.ReadFile(s.Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取同步状态失败: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("解析同步状态失败: %w", err)
+	}
+	return entries, nil
+}
+
+// write 原子写入状态文件
+func (s *Store) write(entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFile(s.Path(), data, 0600); err != nil {
+		return fmt.Errorf("写入同步状态失败: %w", err)
+	}
+	return nil
+}
+
+// lock 锁定状态文件，其他进程持有锁时等待
+func (s *Store) lock() (func(), error) {
+	unlock, err := fsutil.Lock(filepath.Join(s.dir, "drafts.lock"), lockTimeout, lockStale)
+	if err != nil {
+		return nil, fmt.Errorf("锁定同步状态失败: %w", err)
+	}
+	return unlock, nil
+}
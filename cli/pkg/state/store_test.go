@@ -0,0 +1,113 @@
+package state
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T, now time.Time) *Store {
+	t.Helper()
+	s := NewStore(t.TempDir())
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestStore_PutGetList(t *testing.T) {
+	created := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	s := newTestStore(t, created)
+
+	if _, found, err := s.Get("default", "/posts/a.md"); found || err != nil {
+		t.Fatalf("Get() on empty store = %v, %v", found, err)
+	}
+
+	for _, e := range []Entry{
+		{Profile: "default", File: "/posts/b.md", Hash: "h1", MediaID: "m1"},
+		{Profile: "default", File: "/posts/a.md", Hash: "h2", MediaID: "m2"},
+		{Profile: "brand-b", File: "/posts/a.md", Hash: "h3", MediaID: "m3"},
+	} {
+		if err := s.Put(e); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	// 同一文件在不同档案中互不影响
+	e, found, _ := s.Get("brand-b", "/posts/a.md")
+	if !found || e.MediaID != "m3" {
+		t.Errorf("Get(brand-b) = %+v, %v", e, found)
+	}
+
+	// 更新保留创建时间
+	updated := created.Add(time.Hour)
+	s.now = func() time.Time { return updated }
+	if err := s.Put(Entry{Profile: "default", File: "/posts/a.md", Hash: "h4", MediaID: "m2"}); err != nil {
+		t.Fatal(err)
+	}
+	e, _, _ = s.Get("default", "/posts/a.md")
+	if e.Hash != "h4" || !e.CreatedAt.Equal(created) || !e.UpdatedAt.Equal(updated) {
+		t.Errorf("Get() after update = %+v", e)
+	}
+
+	list, err := s.List("default")
+	if err != nil || len(list) != 2 || list[0].File != "/posts/a.md" || list[1].File != "/posts/b.md" {
+		t.Errorf("List(default) = %+v, %v", list, err)
+	}
+
+	if err := s.Delete("default", "/posts/a.md"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := s.Get("default", "/posts/a.md"); found {
+		t.Error("Get() after Delete found entry")
+	}
+	if _, found, _ := s.Get("brand-b", "/posts/a.md"); !found {
+		t.Error("Delete() removed entry of another profile")
+	}
+}
+
+func TestStore_PutInvalid(t *testing.T) {
+	s := newTestStore(t, time.Now())
+	if err := s.Put(Entry{Profile: "default", File: "/a.md"}); err == nil {
+		t.Error("Put() without media_id should fail")
+	}
+}
+
+func TestHash(t *testing.T) {
+	h := Hash([]byte("abc"))
+	if !strings.HasPrefix(h, "sha256:ba7816bf") || Hash([]byte("abd")) == h {
+		t.Errorf("Hash() = %s", h)
+	}
+}
+
+func TestStore_LockFile(t *testing.T) {
+	s := newTestStore(t, time.Now())
+	unlock, err := s.LockFile("default", "/posts/a.md")
+	if err != nil {
+		t.Fatalf("LockFile() error = %v", err)
+	}
+
+	// 其他文件和其他档案的同一文件不受影响
+	for _, key := range [][2]string{{"default", "/posts/b.md"}, {"brand-b", "/posts/a.md"}} {
+		other, err := s.LockFile(key[0], key[1])
+		if err != nil {
+			t.Errorf("LockFile(%s, %s) error = %v", key[0], key[1], err)
+			continue
+		}
+		other()
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		again, err := s.LockFile("default", "/posts/a.md")
+		if err == nil {
+			again()
+		}
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("LockFile() on held file returned before unlock")
+	case <-time.After(200 * time.Millisecond):
+	}
+	unlock()
+	<-locked
+}
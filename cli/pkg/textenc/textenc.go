@@ -0,0 +1,144 @@
+// Package textenc 检测文本文件的编码并转换为 UTF-8。
+//
+// 支持带或不带 BOM 的 UTF-8、带 BOM 的 UTF-16（LE/BE）和 GBK（解码使用
+// golang.org/x/text/encoding/simplifiedchinese）。自动检测时，内容不是合法
+// UTF-8 且按 GBK 解码后全部为中文字符和标点才视为 GBK，否则报错并提示用
+// DecodeAs 指定编码，避免把乱码发送到服务端。
+package textenc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// 检测到的编码
+const (
+	UTF8    = "utf-8"
+	UTF8BOM = "utf-8-bom"
+	UTF16LE = "utf-16le"
+	UTF16BE = "utf-16be"
+	GBK     = "gbk"
+	// Auto 自动检测（DecodeAs 的默认值）
+	Auto = "auto"
+)
+
+// Encodings DecodeAs 可以指定的编码
+var Encodings = []string{Auto, UTF8, GBK}
+
+// ErrUnknownEncoding 内容不是支持的文本编码（可能是二进制文件）
+var ErrUnknownEncoding = errors.New("无法识别的文本编码（支持 UTF-8、UTF-16 和 GBK，GBK 文件可用 --encoding gbk 指定）")
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// Decode 检测 data 的编码并返回 UTF-8 文本（已去除 BOM）和检测到的编码
+func Decode(data []byte) (string, string, error) {
+	return DecodeAs(data, Auto)
+}
+
+// DecodeAs 按 encoding（见 Encodings，空字符串同 Auto）解码 data，返回 UTF-8 文本和实际使用的编码
+//
+// 带 BOM 的内容总是按 BOM 解码。指定 GBK 时不做中文字符检查，但仍拒绝无效字节。
+func DecodeAs(data []byte, encoding string) (string, string, error) {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		data = data[len(bomUTF8):]
+		if !utf8.Valid(data) {
+			return "", "", fmt.Errorf("带 UTF-8 BOM 的内容中有无效的 UTF-8 字节")
+		}
+		return string(data), UTF8BOM, nil
+	case bytes.HasPrefix(data, bomUTF16LE):
+		text, err := decodeUTF16(data[len(bomUTF16LE):], binary.LittleEndian)
+		return text, UTF16LE, err
+	case bytes.HasPrefix(data, bomUTF16BE):
+		text, err := decodeUTF16(data[len(bomUTF16BE):], binary.BigEndian)
+		return text, UTF16BE, err
+	}
+
+	// NUL 字节说明是二进制文件或不带 BOM 的 UTF-16
+	if bytes.IndexByte(data, 0) >= 0 {
+		return "", "", ErrUnknownEncoding
+	}
+	switch encoding {
+	case "", Auto:
+		if utf8.Valid(data) {
+			return string(data), UTF8, nil
+		}
+		if text, ok := decodeGBK(data); ok && likelyChinese(text) {
+			return text, GBK, nil
+		}
+		return "", "", ErrUnknownEncoding
+	case UTF8:
+		if !utf8.Valid(data) {
+			return "", "", fmt.Errorf("内容中有无效的 UTF-8 字节")
+		}
+		return string(data), UTF8, nil
+	case GBK:
+		text, ok := decodeGBK(data)
+		if !ok {
+			return "", "", fmt.Errorf("内容不是有效的 GBK 编码")
+		}
+		return text, GBK, nil
+	}
+	return "", "", fmt.Errorf("不支持的编码: %s（可选: %s）", encoding, strings.Join(Encodings, ", "))
+}
+
+// decodeUTF16 解码不含 BOM 的 UTF-16 内容
+func decodeUTF16(data []byte, order binary.ByteOrder) (string, error) {
+	if len(data)%2 != 0 {
+		return "", fmt.Errorf("UTF-16 内容长度不是偶数，文件可能已损坏")
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// decodeGBK 按 GBK 解码，遇到无效或无法映射的字节、控制字符时返回 false
+func decodeGBK(data []byte) (string, bool) {
+	for _, c := range data {
+		// 文本中不应出现除制表、换行外的控制字符
+		if c < 0x20 && c != '\t' && c != '\n' && c != '\r' && c != '\f' {
+			return "", false
+		}
+	}
+	out, err := simplifiedchinese.GBK.NewDecoder().Bytes(data)
+	// 无效字节被替换为 U+FFFD（原内容不是 UTF-8，不会本来就含有该字符）
+	if err != nil || bytes.ContainsRune(out, utf8.RuneError) {
+		return "", false
+	}
+	return string(out), true
+}
+
+// likelyChinese 判断 GBK 解码结果是否像中文文本：非 ASCII 字符全部是汉字、
+// 中文标点或全角字符
+//
+// 其他单字节编码（如 Latin-1、Windows-1252）的内容偶尔也能按 GBK 解码，
+// 但通常会得到生僻符号，据此排除。
+func likelyChinese(text string) bool {
+	for _, r := range text {
+		switch {
+		case r < utf8.RuneSelf:
+		case unicode.Is(unicode.Han, r):
+		case r >= 0x3000 && r <= 0x303F: // 中文标点
+		case r >= 0xFF00 && r <= 0xFFEF: // 全角字符
+		case r >= 0x2010 && r <= 0x2027: // 破折号、引号、省略号等
+		case r == 0x00B7: // 间隔号
+		default:
+			return false
+		}
+	}
+	return true
+}
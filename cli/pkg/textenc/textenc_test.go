@@ -0,0 +1,87 @@
+package textenc
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		want     string
+		encoding string
+	}{
+		{"utf-8", []byte("# 标题\n正文"), "# 标题\n正文", UTF8},
+		{"empty", nil, "", UTF8},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, "# 标题"...), "# 标题", UTF8BOM},
+		{"utf-16le", append([]byte{0xFF, 0xFE}, mustHex(t, "07689898")...), "标题", UTF16LE},
+		{"utf-16be", append([]byte{0xFE, 0xFF}, mustHex(t, "68079898")...), "标题", UTF16BE},
+		{"gbk", mustHex(t, "2320c4e3bac3a3accac0bde70a0ad6d0cec4c4dac8dda3bab2e2cad42047424b20b1e0c2eba1a3"),
+			"# 你好，世界\n\n中文内容：测试 GBK 编码。", GBK},
+	}
+	for _, tt := range tests {
+		got, encoding, err := Decode(tt.data)
+		if err != nil || got != tt.want || encoding != tt.encoding {
+			t.Errorf("%s: Decode() = %q, %q, %v; want %q, %q", tt.name, got, encoding, err, tt.want, tt.encoding)
+		}
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"binary":          {0x89, 'P', 'N', 'G', 0x00, 0x01},
+		"utf-16 no bom":   mustHex(t, "4100420043004400"),
+		"control bytes":   mustHex(t, "07689898"),
+		"truncated gbk":   mustHex(t, "c4e3ba"),
+		"latin-1":         []byte("caf\xe9"),
+		"bad trail":       {0x81, 0x20},
+		"unmapped gbk":    {0xA2, 0xA0},
+		"odd utf-16":      {0xFF, 0xFE, 0x41},
+		"utf-8 bom + gbk": append([]byte{0xEF, 0xBB, 0xBF}, mustHex(t, "c4e3bac3")...),
+	} {
+		if got, encoding, err := Decode(data); err == nil {
+			t.Errorf("%s: Decode() = %q, %q, want error", name, got, encoding)
+		}
+	}
+
+	if _, _, err := Decode([]byte{0x00, 0x41}); !errors.Is(err, ErrUnknownEncoding) {
+		t.Errorf("Decode(binary) error = %v, want ErrUnknownEncoding", err)
+	}
+}
+
+func TestDecodeAs(t *testing.T) {
+	// A8A6 为 GBK 中的拼音字母 é：能按 GBK 解码，但不像中文文本
+	pinyin := mustHex(t, "6361a8a6")
+	if _, _, err := Decode(pinyin); !errors.Is(err, ErrUnknownEncoding) {
+		t.Errorf("Decode(non-chinese gbk) error = %v, want ErrUnknownEncoding", err)
+	}
+	if got, encoding, err := DecodeAs(pinyin, GBK); err != nil || got != "caé" || encoding != GBK {
+		t.Errorf("DecodeAs(gbk) = %q, %q, %v", got, encoding, err)
+	}
+
+	gbk := mustHex(t, "c4e3bac3")
+	if _, _, err := DecodeAs(gbk, UTF8); err == nil {
+		t.Error("DecodeAs(utf-8) on gbk content should fail")
+	}
+	if _, _, err := DecodeAs(mustHex(t, "c4e3ba"), GBK); err == nil {
+		t.Error("DecodeAs(gbk) on truncated content should fail")
+	}
+	// BOM 优先于指定的编码
+	if got, encoding, _ := DecodeAs(append([]byte{0xEF, 0xBB, 0xBF}, "标题"...), GBK); got != "标题" || encoding != UTF8BOM {
+		t.Errorf("DecodeAs(bom, gbk) = %q, %q", got, encoding)
+	}
+	if _, _, err := DecodeAs([]byte("a"), "big5"); err == nil {
+		t.Error("DecodeAs(big5) should fail")
+	}
+}
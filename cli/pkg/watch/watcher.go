@@ -0,0 +1,133 @@
+// Package watch 提供基于轮询的文件变化监听。
+//
+// 为保持零依赖，Watcher 定期比较文件的修改时间和大小，而不是使用
+// 平台相关的文件系统通知。检测到变化后等待防抖时间，期间没有新的
+// 变化才触发回调，避免编辑器分多次写入时重复处理。
+//
+// 使用方法：
+//
+//	w := watch.New([]string{"article.md"}, 500*time.Millisecond, 300*time.Millisecond)
+//	err := w.Run(ctx, func(changed []string) { ... })
+package watch
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// fileState 文件状态快照
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// Watcher 文件变化监听器
+type Watcher struct {
+	interval time.Duration
+	debounce time.Duration
+
+	mu     sync.Mutex
+	paths  []string
+	states map[string]fileState
+}
+
+// New 创建监听器
+//
+// interval 为轮询间隔，debounce 为最后一次变化后到触发回调的等待时间。
+func New(paths []string, interval, debounce time.Duration) *Watcher {
+	w := &Watcher{
+		interval: interval,
+		debounce: debounce,
+		states:   make(map[string]fileState),
+	}
+	w.SetPaths(paths)
+	return w
+}
+
+// SetPaths 替换监听的文件列表，新增文件以当前状态为基准
+func (w *Watcher) SetPaths(paths []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	states := make(map[string]fileState, len(paths))
+	for _, p := range paths {
+		if st, ok := w.states[p]; ok {
+			states[p] = st
+		} else {
+			states[p] = stat(p)
+		}
+	}
+	w.paths = slices.Clone(paths)
+	w.states = states
+}
+
+// Paths 返回当前监听的文件列表
+func (w *Watcher) Paths() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return slices.Clone(w.paths)
+}
+
+// Run 开始监听，直到 ctx 取消
+//
+// 每批变化（防抖后）调用一次 onChange，参数为发生变化的文件。
+// 回调在监听 goroutine 中同步执行，执行期间的变化会在下一轮检测到。
+func (w *Watcher) Run(ctx context.Context, onChange func(changed []string)) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	var pending []string
+	var lastChange time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			for _, p := range w.Poll() {
+				if !slices.Contains(pending, p) {
+					pending = append(pending, p)
+				}
+				lastChange = now
+			}
+			if len(pending) > 0 && now.Sub(lastChange) >= w.debounce {
+				changed := pending
+				pending = nil
+				onChange(changed)
+			}
+		}
+	}
+}
+
+// Poll 检查一次所有文件，返回自上次检查以来发生变化的文件
+func (w *Watcher) Poll() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var changed []string
+	for _, p := range w.paths {
+		current := stat(p)
+		if current != w.states[p] {
+			w.states[p] = current
+			changed = append(changed, p)
+		}
+	}
+	return changed
+}
+
+// stat 获取文件状态，文件不存在时返回零值
+func stat(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{
+		exists:  true,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	}
+}
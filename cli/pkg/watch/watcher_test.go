@@ -0,0 +1,114 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPoll_DetectsChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "article.md")
+	if err := os.WriteFile(path, []byte("# v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New([]string{path}, time.Millisecond, 0)
+
+	if changed := w.Poll(); len(changed) != 0 {
+		t.Errorf("Poll() = %v, want no changes", changed)
+	}
+
+	// 修改内容和修改时间
+	if err := os.WriteFile(path, []byte("# version 2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Second)
+	os.Chtimes(path, future, future)
+
+	changed := w.Poll()
+	if len(changed) != 1 || changed[0] != path {
+		t.Errorf("Poll() = %v, want [%s]", changed, path)
+	}
+	if changed := w.Poll(); len(changed) != 0 {
+		t.Errorf("second Poll() = %v, want no changes", changed)
+	}
+}
+
+func TestPoll_CreateAndDelete(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "image.png")
+
+	w := New([]string{path}, time.Millisecond, 0)
+
+	if err := os.WriteFile(path, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed := w.Poll(); len(changed) != 1 {
+		t.Errorf("Poll() after create = %v, want 1 change", changed)
+	}
+
+	os.Remove(path)
+	if changed := w.Poll(); len(changed) != 1 {
+		t.Errorf("Poll() after delete = %v, want 1 change", changed)
+	}
+}
+
+func TestSetPaths_KeepsState(t *testing.T) {
+	tmpDir := t.TempDir()
+	a := filepath.Join(tmpDir, "a.md")
+	b := filepath.Join(tmpDir, "b.png")
+	os.WriteFile(a, []byte("a"), 0644)
+	os.WriteFile(b, []byte("b"), 0644)
+
+	w := New([]string{a}, time.Millisecond, 0)
+	w.SetPaths([]string{a, b})
+
+	// 新增的文件以当前状态为基准，不应视为变化
+	if changed := w.Poll(); len(changed) != 0 {
+		t.Errorf("Poll() = %v, want no changes", changed)
+	}
+	if got := w.Paths(); len(got) != 2 {
+		t.Errorf("Paths() = %v, want 2 paths", got)
+	}
+}
+
+func TestRun_Debounce(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "article.md")
+	os.WriteFile(path, []byte("# v1"), 0644)
+
+	w := New([]string{path}, 5*time.Millisecond, 30*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	calls := make(chan []string, 10)
+	go w.Run(ctx, func(changed []string) {
+		calls <- changed
+	})
+
+	// 连续写入多次，只应触发一次回调
+	for i := 0; i < 3; i++ {
+		os.WriteFile(path, []byte(strings.Repeat("#", i+5)), 0644)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case changed := <-calls:
+		if len(changed) != 1 || changed[0] != path {
+			t.Errorf("changed = %v, want [%s]", changed, path)
+		}
+	case <-ctx.Done():
+		t.Fatal("onChange not called")
+	}
+
+	select {
+	case changed := <-calls:
+		t.Errorf("unexpected second callback: %v", changed)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
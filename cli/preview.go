@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/markdown"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/preview"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/watch"
+	"github.com/spf13/cobra"
+)
+
+// PreviewCmd 本地预览命令
+var PreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "启动本地预览服务器（保存后自动刷新）",
+	Long: `启动本地 HTTP 服务器，在模拟微信手机端的页面中预览文章。
+
+文章通过转换接口渲染，不会创建草稿。引用的本地图片由预览服务器提供。
+监听 Markdown 文件和本地图片的变化，保存后自动重新渲染并刷新浏览器。
+按 Ctrl-C 停止。
+
+  md2wx preview --file article.md --theme bytedance --port 8080`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validatePreviewFlags()
+	},
+	Run: runPreview,
+}
+
+var (
+	flagPreviewFile           string
+	flagPreviewTheme          string
+	flagPreviewFontSize       string
+	flagPreviewBackgroundType string
+	flagPreviewConvertVersion string
+	flagPreviewHost           string
+	flagPreviewPort           int
+	flagPreviewInterval       time.Duration
+)
+
+func init() {
+	PreviewCmd.Flags().StringVar(&flagPreviewFile, "file", "", "Markdown 文件路径")
+	PreviewCmd.Flags().StringVar(&flagPreviewTheme, "theme", "", "主题名称（默认从配置读取）")
+	PreviewCmd.Flags().StringVar(&flagPreviewFontSize, "font-size", "", "字体大小 (small/medium/large)")
+	PreviewCmd.Flags().StringVar(&flagPreviewBackgroundType, "background-type", "", "背景类型 (default/grid/none)")
+	PreviewCmd.Flags().StringVar(&flagPreviewConvertVersion, "convert-version", "v2", "转换版本")
+	PreviewCmd.Flags().StringVar(&flagPreviewHost, "host", "127.0.0.1", "监听地址")
+	PreviewCmd.Flags().IntVar(&flagPreviewPort, "port", 8080, "监听端口")
+	PreviewCmd.Flags().DurationVar(&flagPreviewInterval, "interval", 500*time.Millisecond, "文件变化检测间隔")
+	addRetriesFlag(PreviewCmd)
+}
+
+func validatePreviewFlags() error {
+	if flagPreviewFile == "" {
+		return fmt.Errorf("必须提供 --file 参数")
+	}
+	if flagPreviewFile == stdinPath {
+		return fmt.Errorf("预览需要监听文件变化，不支持从标准输入读取")
+	}
+	if flagPreviewInterval <= 0 {
+		return fmt.Errorf("--interval 必须大于 0")
+	}
+
+	// 校验主题
+	if err := validateTheme(flagPreviewTheme); err != nil {
+		return err
+	}
+
+	// 预览只需要 API Key，不需要微信凭证
+	return checkAPIKey()
+}
+
+func runPreview(cmd *cobra.Command, args []string) {
+	// 创建 API 客户端
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	// 渲染函数：每次读取最新文件内容并调用转换接口，images 为最近一次读取到的本地图片
+	var (
+		srv    *preview.Server
+		images []string
+	)
+	render := func(ctx context.Context) (string, error) {
+		content, err := readFileContent(flagPreviewFile)
+		if err != nil {
+			return "", err
+		}
+
+		// front matter 可能随文件修改，每次渲染重新计算样式
+		fm, body, err := markdown.SplitFrontMatter(content)
+		if err != nil {
+			return "", err
+		}
+		// 本地图片替换为预览服务器上的地址，浏览器无法直接读取本地路径
+		mapping := map[string]string{}
+		images = images[:0]
+		for _, ref := range markdown.LocalImages(body) {
+			path := resolveLocalPath(ref, filepath.Dir(flagPreviewFile))
+			mapping[ref] = srv.FileURL(path)
+			images = append(images, path)
+		}
+		body = markdown.RewriteImages(body, mapping)
+
+		theme, fontSize, backgroundType := flagPreviewTheme, flagPreviewFontSize, flagPreviewBackgroundType
+		if err := applyStyleDefaults(cfg, fm, &theme, &fontSize, &backgroundType); err != nil {
+			return "", err
+		}
+
+		resp, err := client.ConvertContext(ctx, &api.ConvertRequest{
+			Markdown:       body,
+			Theme:          theme,
+			FontSize:       fontSize,
+			BackgroundType: backgroundType,
+			ConvertVersion: flagPreviewConvertVersion,
+		})
+		if err != nil {
+			return "", err
+		}
+		if err := resp.Err(); err != nil {
+			return "", err
+		}
+		return resp.Data.HTML, nil
+	}
+
+	srv = preview.NewServer(filepath.Base(flagPreviewFile), render)
+	if err := srv.Refresh(ctx); err != nil {
+		if ctx.Err() != nil {
+			exitOnRequestError(err)
+		}
+		output.PrintError("✗ 渲染失败: %v", err)
+	}
+
+	// 启动 HTTP 服务器，请求 context 继承命令 context，Ctrl-C 时断开实时刷新连接
+	addr := net.JoinHostPort(flagPreviewHost, strconv.Itoa(flagPreviewPort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		output.Error(fmt.Errorf("监听 %s 失败: %w", addr, err))
+	}
+	httpServer := &http.Server{
+		Handler:     srv.Handler(),
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			output.PrintError("✗ 预览服务器异常: %v", err)
+			cancel()
+		}
+	}()
+
+	output.PrintSuccess("✓ 预览地址: http://%s", addr)
+	output.PrintSuccess("  监听文件: %s 及 %d 个本地图片（保存后自动刷新，Ctrl-C 停止）", flagPreviewFile, len(images))
+
+	// 监听文件变化并重新渲染
+	watcher := watch.New(append([]string{flagPreviewFile}, images...), flagPreviewInterval, watchDebounce)
+	watcher.Run(ctx, func(changed []string) {
+		err := srv.Refresh(ctx)
+		watcher.SetPaths(append([]string{flagPreviewFile}, images...))
+		if err != nil {
+			if ctx.Err() == nil {
+				output.PrintError("✗ [%s] 渲染失败: %v", time.Now().Format("15:04:05"), err)
+			}
+			return
+		}
+		output.PrintSuccess("✓ [%s] 已重新渲染（版本 %d）", time.Now().Format("15:04:05"), srv.Version())
+	})
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer shutdownCancel()
+	httpServer.Shutdown(shutdownCtx)
+	output.PrintSuccess("预览已停止")
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// errInputClosed 交互输入提前结束
+var errInputClosed = errors.New("输入已结束，请在终端中运行或使用 --non-interactive")
+
+// prompter 交互式输入，提示写到 stderr，stdout 留给 JSON 输出
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	// tty 标准输入是否为终端，非终端时无法隐藏输入
+	tty bool
+}
+
+func newPrompter() *prompter {
+	tty := false
+	if fi, err := os.Stdin.Stat(); err == nil {
+		tty = fi.Mode()&os.ModeCharDevice != 0
+	}
+	return &prompter{in: bufio.NewReader(os.Stdin), out: os.Stderr, tty: tty}
+}
+
+// readLine 读取一行，去掉首尾空白
+func (p *prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", errInputClosed
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ask 提示输入，直接回车使用默认值
+func (p *prompter) ask(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", label)
+	}
+	v, err := p.readLine()
+	if err != nil || v == "" {
+		return def, err
+	}
+	return v, nil
+}
+
+// askSecret 隐藏输入敏感信息，has 为 true 时回车保留已有值（返回空字符串）
+func (p *prompter) askSecret(label string, has bool) (string, error) {
+	if has {
+		fmt.Fprintf(p.out, "%s [已设置，回车保留]: ", label)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", label)
+	}
+	return p.readHidden()
+}
+
+// readHidden 关闭终端回显后读取一行，非终端或 Windows 上直接读取
+func (p *prompter) readHidden() (string, error) {
+	if !p.tty || runtime.GOOS == "windows" || stty("-echo") != nil {
+		return p.readLine()
+	}
+
+	// Ctrl-C 时恢复回显再退出
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-sig:
+			stty("echo")
+			fmt.Fprintln(p.out)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	line, err := p.readLine()
+	close(done)
+	signal.Stop(sig)
+	stty("echo")
+	fmt.Fprintln(p.out)
+	return line, err
+}
+
+// choose 从选项中选择，可输入序号或名称，直接回车使用默认值
+func (p *prompter) choose(label string, options []string, describe func(string) string, def string) (string, error) {
+	fmt.Fprintf(p.out, "%s:\n", label)
+	var row []string
+	flush := func() {
+		if len(row) > 0 {
+			fmt.Fprintf(p.out, "  %s\n", strings.Join(row, "  "))
+			row = nil
+		}
+	}
+	for i, opt := range options {
+		item := fmt.Sprintf("%2d) %-16s", i+1, opt)
+		if desc := describe(opt); desc != "" {
+			flush()
+			fmt.Fprintf(p.out, "  %s %s\n", item, desc)
+			continue
+		}
+		if row = append(row, item); len(row) == 4 {
+			flush()
+		}
+	}
+	flush()
+
+	for {
+		v, err := p.ask("请选择", def)
+		if err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+		if slices.Contains(options, v) {
+			return v, nil
+		}
+		fmt.Fprintf(p.out, "无效的选择: %s\n", v)
+	}
+}
+
+// confirm 询问是/否，直接回车使用默认值
+func (p *prompter) confirm(label string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	fmt.Fprintf(p.out, "%s [%s]: ", label, hint)
+	v, err := p.readLine()
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(v) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+func stty(arg string) error {
+	c := exec.Command("stty", arg)
+	c.Stdin = os.Stdin
+	return c.Run()
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// PublishCmd 发布命令
+var PublishCmd = &cobra.Command{
+	Use:   "publish <media_id>",
+	Short: "发布草稿",
+	Long: `提交草稿发布。发布是异步的，命令返回 publish_id，可用 'publish status' 查询结果。
+
+使用 --wait 时持续轮询（间隔逐步加大）直到发布成功或失败，成功时返回文章链接：
+  md2wx publish <media_id> --wait
+  md2wx publish status <publish_id> --wait
+
+失败状态（原创声明失败、常规失败、审核不通过等）以错误返回，错误码如
+PUBLISH_ORIGINAL_CHECK_FAILED、PUBLISH_AUDIT_FAILED，details 中包含失败文章序号。`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validatePublishFlags()
+	},
+	Run: runPublish,
+}
+
+// publishStatusCmd 查询发布状态命令
+var publishStatusCmd = &cobra.Command{
+	Use:   "status <publish_id>",
+	Short: "查询发布状态",
+	Args:  cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validatePublishFlags()
+	},
+	Run: runPublishStatus,
+}
+
+var (
+	flagPublishWait        bool
+	flagPublishWaitTimeout time.Duration
+	flagPublishInterval    time.Duration
+	flagPublishMaxInterval time.Duration
+)
+
+func init() {
+	PublishCmd.AddCommand(publishStatusCmd)
+	PublishCmd.PersistentFlags().BoolVar(&flagPublishWait, "wait", false, "轮询直到发布成功或失败")
+	PublishCmd.PersistentFlags().DurationVar(&flagPublishWaitTimeout, "wait-timeout", 10*time.Minute, "--wait 最长等待时间")
+	PublishCmd.PersistentFlags().DurationVar(&flagPublishInterval, "interval", 2*time.Second, "初始轮询间隔")
+	PublishCmd.PersistentFlags().DurationVar(&flagPublishMaxInterval, "max-interval", 30*time.Second, "最大轮询间隔")
+	addRetriesFlag(PublishCmd)
+}
+
+func validatePublishFlags() error {
+	if flagPublishInterval <= 0 || flagPublishMaxInterval <= 0 {
+		return fmt.Errorf("--interval 和 --max-interval 必须大于 0")
+	}
+	if flagPublishWaitTimeout <= 0 {
+		return fmt.Errorf("--wait-timeout 必须大于 0")
+	}
+	return checkCredentials()
+}
+
+func runPublish(cmd *cobra.Command, args []string) {
+	mediaID := args[0]
+
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	resp, err := client.PublishContext(ctx, mediaID)
+	if err != nil {
+		exitOnRequestError(err)
+	}
+	if err := resp.Err(); err != nil {
+		output.Error(err)
+	}
+
+	if !flagPublishWait {
+		output.Success(map[string]interface{}{
+			"media_id":   mediaID,
+			"publish_id": resp.Data.PublishID,
+			"state":      api.PublishPublishing.String(),
+		})
+		return
+	}
+
+	status := waitPublish(ctx, cmd, client, resp.Data.PublishID)
+	result := publishResult(status)
+	result["media_id"] = mediaID
+	output.Success(result)
+}
+
+func runPublishStatus(cmd *cobra.Command, args []string) {
+	client, err := newAPIClient(cmd)
+	if err != nil {
+		output.Error(err)
+	}
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	var status *api.PublishStatus
+	if flagPublishWait {
+		status = waitPublish(ctx, cmd, client, args[0])
+	} else {
+		resp, err := client.GetPublishStatusContext(ctx, args[0])
+		if err != nil {
+			exitOnRequestError(err)
+		}
+		if err := resp.Err(); err != nil {
+			output.Error(err)
+		}
+		status = &resp.Data
+	}
+
+	// 失败终态以错误返回，便于脚本按错误码处理
+	if err := status.Err(); err != nil {
+		output.Error(err)
+	}
+	output.Success(publishResult(status))
+}
+
+// waitPublish 轮询发布状态直到终态，失败终态直接输出错误并退出
+func waitPublish(ctx context.Context, cmd *cobra.Command, client *api.Client, publishID string) *api.PublishStatus {
+	ctx, cancel := context.WithTimeout(ctx, flagPublishWaitTimeout)
+	defer cancel()
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	status, err := client.WaitPublishContext(ctx, publishID, flagPublishInterval, flagPublishMaxInterval, func(s *api.PublishStatus) {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "publish %s: %s\n", publishID, s.State)
+		}
+	})
+	if err != nil {
+		exitOnRequestError(err)
+	}
+	if err := status.Err(); err != nil {
+		output.Error(err)
+	}
+	return status
+}
+
+// publishResult 构建发布状态输出
+func publishResult(status *api.PublishStatus) map[string]interface{} {
+	result := map[string]interface{}{
+		"publish_id":     status.PublishID,
+		"publish_status": int(status.State),
+		"state":          status.State.String(),
+	}
+	if status.ArticleID != "" {
+		result["article_id"] = status.ArticleID
+	}
+	if urls := status.ArticleURLs(); len(urls) > 0 {
+		result["article_urls"] = urls
+	}
+	if len(status.FailIndex) > 0 {
+		result["fail_idx"] = status.FailIndex
+	}
+	return result
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/schedule"
+	"github.com/spf13/cobra"
+)
+
+// ScheduleCmd 定时发布命令
+var ScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "管理定时发布任务",
+	Long: `管理定时发布队列。任务保存在配置目录的 schedule/ 下，由 'md2wx scheduler run'
+在计划时间执行发布。任务记录添加时的配置档案（--profile 等），执行时使用该档案的账号。
+
+  md2wx schedule add <media_id> --at "2024-06-01 08:00"
+  md2wx schedule add article.md --at "fri 08:00"
+  md2wx schedule list
+  md2wx schedule cancel <job_id>`,
+}
+
+// scheduleAddCmd 添加定时任务命令
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <media_id|file>",
+	Short: "添加定时发布任务",
+	Long: `添加定时发布任务。参数为已存在的 Markdown 文件时，执行时先创建草稿再发布
+（front matter 与 article-draft 相同）；否则视为草稿 media_id。
+
+--at 支持的时间格式：
+  2024-06-01T08:00:00+08:00   RFC3339
+  "2024-06-01 08:00"          本地时间
+  08:00                       今天该时刻（已过则为明天）
+  "fri 08:00"、"周五 08:00"    下一个星期五
+  +2h、"in 30m"               相对当前时间`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if flagScheduleAt == "" {
+			return fmt.Errorf("必须提供 --at 参数")
+		}
+		if flagScheduleMaxAttempts < 1 {
+			return fmt.Errorf("--max-attempts 必须大于 0")
+		}
+		return nil
+	},
+	Run: runScheduleAdd,
+}
+
+// scheduleListCmd 列出定时任务命令
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出定时发布任务",
+	Long:  `列出定时发布任务，按计划时间排序。默认只显示未结束的任务，--all 显示全部。`,
+	Args:  cobra.NoArgs,
+	Run:   runScheduleList,
+}
+
+// scheduleCancelCmd 取消定时任务命令
+var scheduleCancelCmd = &cobra.Command{
+	Use:   "cancel <job_id>",
+	Short: "取消定时发布任务",
+	Args:  cobra.ExactArgs(1),
+	Run:   runScheduleCancel,
+}
+
+// scheduleLogCmd 查看任务执行记录命令
+var scheduleLogCmd = &cobra.Command{
+	Use:   "log <job_id>",
+	Short: "查看任务执行记录",
+	Args:  cobra.ExactArgs(1),
+	Run:   runScheduleLog,
+}
+
+var (
+	flagScheduleAt          string
+	flagScheduleMaxAttempts int
+	flagScheduleAll         bool
+)
+
+func init() {
+	ScheduleCmd.AddCommand(scheduleAddCmd)
+	ScheduleCmd.AddCommand(scheduleListCmd)
+	ScheduleCmd.AddCommand(scheduleCancelCmd)
+	ScheduleCmd.AddCommand(scheduleLogCmd)
+
+	scheduleAddCmd.Flags().StringVar(&flagScheduleAt, "at", "", "计划发布时间")
+	scheduleAddCmd.Flags().IntVar(&flagScheduleMaxAttempts, "max-attempts", 3, "最多执行次数（含首次）")
+
+	scheduleListCmd.Flags().BoolVar(&flagScheduleAll, "all", false, "包含已完成、失败和已取消的任务")
+}
+
+// scheduleStore 返回配置目录下的任务队列
+func scheduleStore() *schedule.Store {
+	return schedule.NewStore(filepath.Join(config.GetConfigDir(), "schedule"))
+}
+
+func runScheduleAdd(cmd *cobra.Command, args []string) {
+	at, err := schedule.ParseTime(flagScheduleAt, time.Now())
+	if err != nil {
+		output.Error(err)
+	}
+	if !at.After(time.Now()) {
+		output.Error(fmt.Errorf("计划时间 %s 已过", at.Format(time.RFC3339)))
+	}
+
+	job := schedule.Job{At: at, MaxAttempts: flagScheduleMaxAttempts, Profile: cfg.Profile}
+	if info, err := os.Stat(args[0]); err == nil && !info.IsDir() {
+		path, err := filepath.Abs(args[0])
+		if err != nil {
+			output.Error(err)
+		}
+		// 提前校验文章，避免到点才发现问题
+		a, err := prepareArticle(cfg, articleSource{Path: path}, scheduleConvertVersion)
+		if err != nil {
+			output.Error(err)
+		}
+		if !cfg.UploadImages() {
+			if err := a.checkLocalCover(); err != nil {
+				output.Error(err)
+			}
+		}
+		job.File = path
+	} else if strings.ContainsAny(args[0], `/\`) || strings.HasSuffix(args[0], ".md") {
+		output.Error(fmt.Errorf("文件不存在: %s", args[0]))
+	} else {
+		job.MediaID = args[0]
+	}
+
+	job, err = scheduleStore().Add(job)
+	if err != nil {
+		output.Error(err)
+	}
+	output.Success(job)
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) {
+	jobs, err := scheduleStore().List()
+	if err != nil {
+		output.Error(err)
+	}
+
+	items := []schedule.Job{}
+	for _, j := range jobs {
+		if flagScheduleAll || !j.Status.Finished() {
+			items = append(items, j)
+		}
+	}
+	output.Success(map[string]interface{}{
+		"jobs":  items,
+		"count": len(items),
+	})
+}
+
+func runScheduleCancel(cmd *cobra.Command, args []string) {
+	job, err := scheduleStore().Cancel(args[0])
+	if err != nil {
+		output.Error(err)
+	}
+	output.Success(job)
+}
+
+func runScheduleLog(cmd *cobra.Command, args []string) {
+	store := scheduleStore()
+	job, err := store.Get(args[0])
+	if err != nil {
+		output.Error(err)
+	}
+	entries, err := store.ReadLog(job.ID)
+	if err != nil {
+		output.Error(err)
+	}
+	if entries == nil {
+		entries = []schedule.LogEntry{}
+	}
+	output.Success(map[string]interface{}{
+		"job":     job,
+		"entries": entries,
+		"log":     store.LogPath(job.ID),
+	})
+}
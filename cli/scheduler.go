@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/api"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/config"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/output"
+	"github.com/geekjourneyx/md2wechat-lite/cli/pkg/schedule"
+	"github.com/spf13/cobra"
+)
+
+// scheduleConvertVersion 定时任务从文件创建草稿时使用的转换版本
+const scheduleConvertVersion = "v2"
+
+const (
+	// maxRetryDelay 失败重试的最长延迟
+	maxRetryDelay = 24 * time.Hour
+	// maxReportedJobs 调度器退出时输出的最近处理任务数，count 为全部处理数
+	maxReportedJobs = 100
+)
+
+// SchedulerCmd 调度器命令
+var SchedulerCmd = &cobra.Command{
+	Use:   "scheduler",
+	Short: "运行定时发布调度器",
+}
+
+// schedulerRunCmd 前台运行调度器命令
+var schedulerRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "前台运行调度器，按计划时间发布",
+	Long: `前台运行调度器：定期检查 'md2wx schedule' 队列，到点的任务先创建草稿（文件任务），
+再提交发布并等待发布结果。
+
+失败的任务按 --retry-delay 指数退避重试（最长间隔 24 小时），直到达到任务的最多执行次数；原创声明失败、
+审核不通过等发布失败终态不再重试。每次执行的事件写入任务执行记录
+（'md2wx schedule log <job_id>'），同时输出到 stderr。
+
+同一队列只能运行一个调度器，已有调度器运行时直接退出。调度器重启时会恢复上次
+中断的任务；已提交发布的任务只继续查询结果，不会重复发布。
+Ctrl-C 退出，--once 处理一轮到期任务后退出（适合配合 cron 使用）。`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if flagSchedulerInterval <= 0 || flagSchedulerRetryDelay <= 0 || flagSchedulerWaitTimeout <= 0 {
+			return fmt.Errorf("--interval、--retry-delay 和 --wait-timeout 必须大于 0")
+		}
+		return checkCredentials()
+	},
+	Run: runScheduler,
+}
+
+var (
+	flagSchedulerInterval    time.Duration
+	flagSchedulerOnce        bool
+	flagSchedulerWaitTimeout time.Duration
+	flagSchedulerRetryDelay  time.Duration
+)
+
+func init() {
+	SchedulerCmd.AddCommand(schedulerRunCmd)
+
+	schedulerRunCmd.Flags().DurationVar(&flagSchedulerInterval, "interval", 30*time.Second, "检查队列的间隔")
+	schedulerRunCmd.Flags().BoolVar(&flagSchedulerOnce, "once", false, "处理一轮到期任务后退出")
+	schedulerRunCmd.Flags().DurationVar(&flagSchedulerWaitTimeout, "wait-timeout", 10*time.Minute, "每个任务等待发布结果的最长时间")
+	schedulerRunCmd.Flags().DurationVar(&flagSchedulerRetryDelay, "retry-delay", time.Minute, "首次重试的延迟，之后每次翻倍（最长 24h）")
+	addRetriesFlag(schedulerRunCmd)
+}
+
+// permanentError 重试也无法成功的错误，任务直接标记为失败
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func runScheduler(cmd *cobra.Command, args []string) {
+	// 提前校验重试参数，每个任务执行时再按其配置档案创建客户端
+	if _, err := newAPIClient(cmd); err != nil {
+		output.Error(err)
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	store := scheduleStore()
+	unlock, err := store.LockScheduler()
+	if err != nil {
+		output.Error(err)
+	}
+	defer unlock()
+
+	recovered, err := store.Recover()
+	if err != nil {
+		unlock()
+		output.Error(err)
+	}
+	for _, j := range recovered {
+		logf("job %s recovered", j.ID)
+	}
+	logf("scheduler started, queue %s", store.Dir())
+
+	// 常驻运行时只保留最近处理的任务，完整记录见 'md2wx schedule list'
+	processed := []schedule.Job{}
+	count := 0
+	for {
+		jobs, err := store.ClaimDue(time.Now())
+		if err != nil {
+			unlock()
+			output.Error(err)
+		}
+		for _, job := range jobs {
+			processed = append(processed, runScheduledJob(ctx, cmd, store, job))
+			if len(processed) > maxReportedJobs {
+				processed = processed[len(processed)-maxReportedJobs:]
+			}
+			count++
+		}
+
+		if flagSchedulerOnce || ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(flagSchedulerInterval):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	logf("scheduler stopped")
+	unlock()
+	output.Success(map[string]interface{}{
+		"processed": processed,
+		"count":     count,
+	})
+}
+
+// runScheduledJob 执行一个已领取的任务并保存结果
+func runScheduledJob(ctx context.Context, cmd *cobra.Command, store *schedule.Store, job schedule.Job) schedule.Job {
+	event := func(name, format string, args ...interface{}) {
+		msg := fmt.Sprintf(format, args...)
+		store.AppendLog(schedule.LogEntry{JobID: job.ID, Attempt: job.Attempts, Event: name, Message: msg})
+		logf("job %s attempt %d/%d %s", job.ID, job.Attempts, job.MaxAttempts, strings.TrimSpace(name+" "+msg))
+	}
+	save := func() {
+		if err := store.Save(job); err != nil {
+			logf("job %s save failed: %v", job.ID, err)
+		}
+	}
+
+	event("started", "")
+	c, client, err := jobClient(cmd, job)
+	if err == nil {
+		err = publishScheduledJob(ctx, c, client, &job, event, save)
+	}
+
+	switch {
+	case err == nil:
+		job.Status = schedule.StatusDone
+		job.LastError = ""
+		event("done", "%v", job.ArticleURLs)
+	case ctx.Err() != nil:
+		// 调度器退出导致的中断不计入执行次数
+		job.Status = schedule.StatusPending
+		job.Attempts--
+		event("interrupted", "调度器退出，任务重新排队")
+	default:
+		job.LastError = err.Error()
+		var perm *permanentError
+		if errors.As(err, &perm) || job.Attempts >= job.MaxAttempts {
+			job.Status = schedule.StatusFailed
+			event("failed", "%v", err)
+			break
+		}
+		job.Status = schedule.StatusPending
+		job.At = time.Now().Add(retryDelay(flagSchedulerRetryDelay, job.Attempts))
+		event("retry", "%v，将于 %s 重试", err, job.At.Format(time.RFC3339))
+	}
+	save()
+	return job
+}
+
+// retryDelay 返回第 attempt 次执行失败后的重试延迟：base 每次翻倍，最长 maxRetryDelay
+// （base 本身超过 maxRetryDelay 时为 base）
+func retryDelay(base time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	if d > maxRetryDelay {
+		return max(base, maxRetryDelay)
+	}
+	return d
+}
+
+// jobClient 按任务的配置档案加载配置并创建 API 客户端，未记录档案时沿用调度器的档案
+//
+// 文件任务以文件所在目录查找项目配置（.md2wx.yaml），与在该目录手动提交时一致。
+// 每个任务使用独立的配置，不修改全局配置。
+func jobClient(cmd *cobra.Command, job schedule.Job) (*config.Config, *api.Client, error) {
+	c := cfg
+	if job.File != "" || (job.Profile != "" && job.Profile != cfg.Profile) {
+		opts := config.LoadOptions{Profile: job.Profile}
+		if opts.Profile == "" {
+			opts.Profile, _ = cmd.Flags().GetString("profile")
+		}
+		if job.File != "" {
+			opts.ProjectDir = filepath.Dir(job.File)
+		}
+		loaded, err := config.LoadWith(opts)
+		if err != nil {
+			return nil, nil, &permanentError{err}
+		}
+		c = loaded
+	}
+	if err := checkConfigCredentials(c); err != nil {
+		return nil, nil, &permanentError{err}
+	}
+	client, err := newConfigClient(cmd, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, client, nil
+}
+
+// publishScheduledJob 按需创建草稿、提交发布并等待结果，每完成一步即保存进度
+//
+// 文章的默认值、图片上传和上传缓存使用任务的配置 c。
+func publishScheduledJob(ctx context.Context, c *config.Config, client *api.Client, job *schedule.Job, event func(name, format string, args ...interface{}), save func()) error {
+	if job.MediaID == "" {
+		a, err := prepareArticle(c, articleSource{Path: job.File}, scheduleConvertVersion)
+		if err != nil {
+			return &permanentError{err}
+		}
+		if c.UploadImages() {
+			if _, err := a.uploadImages(ctx, client, newImageCache(c)); err != nil {
+				return err
+			}
+		} else if err := a.checkLocalCover(); err != nil {
+			return &permanentError{err}
+		}
+		resp, err := client.ArticleDraftContext(ctx, a.Request)
+		if err != nil {
+			return err
+		}
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		job.MediaID = resp.Data.MediaID
+		save()
+		event("draft_created", "media_id=%s", job.MediaID)
+	}
+
+	// 已提交过发布（上次等待超时或调度器中断）时只继续查询结果，避免重复发布
+	if job.PublishID == "" {
+		resp, err := client.PublishContext(ctx, job.MediaID)
+		if err != nil {
+			return err
+		}
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		job.PublishID = resp.Data.PublishID
+		save()
+		event("submitted", "publish_id=%s", job.PublishID)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, flagSchedulerWaitTimeout)
+	defer cancel()
+	status, err := client.WaitPublishContext(waitCtx, job.PublishID, 2*time.Second, 30*time.Second, nil)
+	if err != nil {
+		return err
+	}
+	if err := status.Err(); err != nil {
+		return &permanentError{err}
+	}
+	job.ArticleURLs = status.ArticleURLs()
+	return nil
+}
+
+// logf 向 stderr 输出带时间的调度器日志
+func logf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, args...))
+}